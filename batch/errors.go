@@ -0,0 +1,11 @@
+package batch
+
+import (
+	"errors"
+)
+
+var (
+	ErrBatchFnCannotBeNil    = errors.New("batchFn cannot be nil")
+	ErrPanicExecutingBatchFn = errors.New("panic executing batchFn")
+	ErrMissingKey            = errors.New("batchFn returned no value for key")
+)
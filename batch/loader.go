@@ -0,0 +1,250 @@
+// Package batch coalesces the keys loaded by concurrent callers within the same request
+// into batches, similar to the dataloader pattern. It is built on top of the memoize
+// cache of the request, which holds the pending batches and memoizes the outcome of
+// every key for the rest of the request.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMaxBatchSize = 100
+	defaultWait         = 2 * time.Millisecond
+)
+
+// Func loads the values of the given keys at once. Keys missing from the returned map
+// fail with ErrMissingKey, and an error fails all keys of this batch.
+type Func[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Option configures a Loader.
+type Option func(*options)
+
+type options struct {
+	maxBatchSize int
+	wait         time.Duration
+}
+
+// WithMaxBatchSize makes a Loader dispatch a batch as soon as it holds this many keys.
+// It defaults to 100.
+func WithMaxBatchSize(maxBatchSize int) Option {
+	return func(o *options) {
+		o.maxBatchSize = maxBatchSize
+	}
+}
+
+// WithWait makes a Loader wait this long after the first key of a batch was loaded for
+// more keys to coalesce before dispatching the batch. It defaults to 2ms.
+func WithWait(wait time.Duration) Option {
+	return func(o *options) {
+		o.wait = wait
+	}
+}
+
+// Loader loads values using a batch Func. A Loader is stateless and meant to be
+// declared once, e.g. as a package-level variable, while its batches live in the
+// memoize cache of every request.
+type Loader[K comparable, V any] struct {
+	batchFn Func[K, V]
+	options options
+}
+
+// NewLoader returns a Loader using the given batch Func.
+func NewLoader[K comparable, V any](batchFn Func[K, V], opts ...Option) *Loader[K, V] {
+	o := options{
+		maxBatchSize: defaultMaxBatchSize,
+		wait:         defaultWait,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Loader[K, V]{
+		batchFn: batchFn,
+		options: o,
+	}
+}
+
+// batcherKey is the memoize executionKey of the batcher of a Loader.
+type batcherKey[K comparable, V any] struct {
+	loader *Loader[K, V]
+}
+
+// loadKey is the memoize executionKey of the outcome of a key loaded by a Loader.
+type loadKey[K comparable, V any] struct {
+	loader *Loader[K, V]
+	key    K
+}
+
+// Load returns the value of the given key. Keys loaded concurrently within the same
+// request are dispatched to the batch Func together, and the outcome of every key is
+// memoized for the rest of the request.
+//
+// Note: keys can only be coalesced & memoized if the given context has been
+// initialized using memoize.WithCache. Otherwise, every call to Load dispatches a
+// batch holding only the given key.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	if l.batchFn == nil {
+		var zero V
+		return zero, ErrBatchFnCannotBeNil
+	}
+
+	outcome, _ := memoize.Execute(
+		ctx, loadKey[K, V]{loader: l, key: key}, func(ctx context.Context) (V, error) {
+			return l.batcher(ctx).load(key)
+		},
+	)
+
+	return outcome.Value, outcome.Err
+}
+
+// LoadMany returns the outcomes of the given keys, which are loaded concurrently so
+// that they get dispatched in as few batches as possible.
+func (l *Loader[K, V]) LoadMany(ctx context.Context, keys []K) map[K]memoize.TypedOutcome[V] {
+	m := make(map[K]memoize.TypedOutcome[V], len(keys))
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, key := range keys {
+		wg.Add(1)
+
+		go func(key K) {
+			defer wg.Done()
+
+			value, err := l.Load(ctx, key)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			m[key] = memoize.TypedOutcome[V]{
+				Value: value,
+				Err:   err,
+			}
+		}(key)
+	}
+
+	wg.Wait()
+
+	return m
+}
+
+// batcher returns the batcher of this Loader memoized in the cache of ctx.
+func (l *Loader[K, V]) batcher(ctx context.Context) *batcher[K, V] {
+	outcome, _ := memoize.Execute(
+		ctx, batcherKey[K, V]{loader: l}, func(ctx context.Context) (*batcher[K, V], error) {
+			return &batcher[K, V]{
+				loader: l,
+				ctx:    ctx,
+			}, nil
+		},
+	)
+
+	if outcome.Value == nil {
+		// The cache was destroyed, dispatch keys on their own
+		return &batcher[K, V]{
+			loader: l,
+			ctx:    ctx,
+		}
+	}
+
+	return outcome.Value
+}
+
+// batcher accumulates keys into batches for a Loader within one request.
+type batcher[K comparable, V any] struct {
+	loader *Loader[K, V]
+	// ctx is given to the batch Func.
+	ctx context.Context
+
+	mu      sync.Mutex
+	pending *pendingBatch[K, V]
+}
+
+type pendingBatch[K comparable, V any] struct {
+	keys     []K
+	dispatch sync.Once
+	done     chan struct{}
+	values   map[K]V
+	err      error
+}
+
+// load adds the given key to the pending batch and waits for this batch to complete.
+func (b *batcher[K, V]) load(key K) (V, error) {
+	b.mu.Lock()
+
+	batch := b.pending
+	if batch == nil {
+		batch = &pendingBatch[K, V]{
+			done: make(chan struct{}),
+		}
+
+		b.pending = batch
+
+		time.AfterFunc(
+			b.loader.options.wait, func() {
+				b.dispatch(batch)
+			},
+		)
+	}
+
+	batch.keys = append(batch.keys, key)
+	isFull := len(batch.keys) >= b.loader.options.maxBatchSize
+
+	b.mu.Unlock()
+
+	if isFull {
+		b.dispatch(batch)
+	}
+
+	<-batch.done
+
+	if batch.err != nil {
+		var zero V
+		return zero, batch.err
+	}
+
+	value, ok := batch.values[key]
+	if !ok {
+		return value, errors.Wrap(ErrMissingKey, fmt.Sprintf("%v", key))
+	}
+
+	return value, nil
+}
+
+// dispatch invokes the batch Func with the keys of the given batch, once.
+func (b *batcher[K, V]) dispatch(batch *pendingBatch[K, V]) {
+	batch.dispatch.Do(
+		func() {
+			b.mu.Lock()
+			if b.pending == batch {
+				b.pending = nil
+			}
+
+			keys := batch.keys
+			b.mu.Unlock()
+
+			batch.values, batch.err = b.execute(keys)
+			close(batch.done)
+		},
+	)
+}
+
+func (b *batcher[K, V]) execute(keys []K) (values map[K]V, err error) {
+	// Convert panics into standard errors for callers to handle gracefully
+	defer func() {
+		if r := recover(); r != nil {
+			values = nil
+			err = errors.Wrap(ErrPanicExecutingBatchFn, fmt.Sprintf("%v \n %v", r, string(debug.Stack())))
+		}
+	}()
+
+	return b.loader.batchFn(b.ctx, keys)
+}
@@ -0,0 +1,180 @@
+package batch
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jamestrandung/go-context/ctxpool"
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingBatchFn struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *recordingBatchFn) load(ctx context.Context, keys []int) (map[int]string, error) {
+	sorted := append([]int(nil), keys...)
+	sort.Ints(sorted)
+
+	r.mu.Lock()
+	r.batches = append(r.batches, sorted)
+	r.mu.Unlock()
+
+	m := make(map[int]string, len(keys))
+	for _, key := range keys {
+		if key >= 0 {
+			m[key] = string(rune('a' + key))
+		}
+	}
+
+	return m, nil
+}
+
+func TestLoader_Load(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "concurrent keys are coalesced and memoized",
+			test: func(t *testing.T) {
+				r := &recordingBatchFn{}
+				loader := NewLoader(r.load, WithWait(10*time.Millisecond))
+
+				ctx, destroyFn := memoize.WithCache(context.Background())
+				defer destroyFn()
+
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+
+					go func(i int) {
+						defer wg.Done()
+
+						value, err := loader.Load(ctx, i%3)
+						assert.Equal(t, string(rune('a'+i%3)), value)
+						assert.Nil(t, err)
+					}(i)
+				}
+
+				wg.Wait()
+
+				value, err := loader.Load(ctx, 1)
+				assert.Equal(t, "b", value)
+				assert.Nil(t, err)
+
+				assert.Equal(t, [][]int{{0, 1, 2}}, r.batches)
+			},
+		},
+		{
+			desc: "full batches are dispatched without waiting",
+			test: func(t *testing.T) {
+				r := &recordingBatchFn{}
+				loader := NewLoader(r.load, WithWait(time.Hour), WithMaxBatchSize(2))
+
+				ctx, destroyFn := memoize.WithCache(context.Background())
+				defer destroyFn()
+
+				outcomes := loader.LoadMany(ctx, []int{0, 1})
+				assert.Equal(
+					t, map[int]memoize.TypedOutcome[string]{
+						0: {Value: "a"},
+						1: {Value: "b"},
+					}, outcomes,
+				)
+
+				assert.Equal(t, [][]int{{0, 1}}, r.batches)
+			},
+		},
+		{
+			desc: "single goroutine pool",
+			test: func(t *testing.T) {
+				pool := ctxpool.New(1)
+				defer pool.Close()
+
+				loader := NewLoader((&recordingBatchFn{}).load, WithWait(0))
+
+				rootCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				ctx, destroyFn := memoize.WithCache(rootCtx, memoize.WithPool(pool))
+				defer destroyFn()
+
+				value, err := loader.Load(ctx, 2)
+				assert.Equal(t, "c", value)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			desc: "missing key",
+			test: func(t *testing.T) {
+				loader := NewLoader((&recordingBatchFn{}).load, WithWait(0))
+
+				_, err := loader.Load(context.Background(), -1)
+				assert.True(t, errors.Is(err, ErrMissingKey))
+			},
+		},
+		{
+			desc: "errors fail all keys of the batch",
+			test: func(t *testing.T) {
+				loader := NewLoader(
+					func(ctx context.Context, keys []int) (map[int]string, error) {
+						return nil, assert.AnError
+					},
+				)
+
+				ctx, destroyFn := memoize.WithCache(context.Background())
+				defer destroyFn()
+
+				for _, outcome := range loader.LoadMany(ctx, []int{0, 1}) {
+					assert.Equal(t, assert.AnError, outcome.Err)
+				}
+			},
+		},
+		{
+			desc: "panics are recovered",
+			test: func(t *testing.T) {
+				loader := NewLoader(
+					func(ctx context.Context, keys []int) (map[int]string, error) {
+						panic("boom")
+					},
+				)
+
+				_, err := loader.Load(context.Background(), 0)
+				assert.True(t, errors.Is(err, ErrPanicExecutingBatchFn))
+			},
+		},
+		{
+			desc: "nil batchFn",
+			test: func(t *testing.T) {
+				_, err := NewLoader[int, string](nil).Load(context.Background(), 0)
+				assert.Equal(t, ErrBatchFnCannotBeNil, err)
+			},
+		},
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				r := &recordingBatchFn{}
+				loader := NewLoader(r.load, WithWait(0))
+
+				loader.Load(context.Background(), 0)
+				loader.Load(context.Background(), 0)
+
+				assert.Equal(t, [][]int{{0}, {0}}, r.batches)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -9,6 +9,25 @@ type contextKey struct{}
 
 var breadcrumbKey = contextKey{}
 
+// BreadcrumbDomain isolates the breadcrumbs of independent subsystems so that
+// they can use the same ID types without interfering with each other's cycle
+// detection within one request context.
+//
+// The zero value is the default domain used by WithAcyclicBreadcrumb.
+type BreadcrumbDomain string
+
+type breadcrumbDomainKey struct {
+	domain BreadcrumbDomain
+}
+
+func (d BreadcrumbDomain) key() interface{} {
+	if d == "" {
+		return breadcrumbKey
+	}
+
+	return breadcrumbDomainKey{d}
+}
+
 // WithAcyclicBreadcrumb return a new context with the given breadcrumbID embedded inside and true
 // if this ID has never been encountered in the execution path before. Otherwise, it returns a nil
 // context.Context and false to indicate the execution is running in circle.
@@ -17,26 +36,56 @@ var breadcrumbKey = contextKey{}
 // built-in type to avoid collisions between packages using this context. You should define your
 // own types for breadcrumbID similar to the best practices for using context.WithValue.
 func WithAcyclicBreadcrumb[V comparable](ctx context.Context, breadcrumbID V) (context.Context, bool) {
-	prevBreadcrumb := findPrevBreadcrumb(ctx, breadcrumbID)
+	return WithAcyclicBreadcrumbInDomain(ctx, "", breadcrumbID)
+}
 
-	newBreadcrumb, ok := appendBreadcrumb(ctx, breadcrumbID, prevBreadcrumb)
+// WithAcyclicBreadcrumbInDomain works like WithAcyclicBreadcrumb except that the given breadcrumbID
+// is only checked against breadcrumbs previously embedded in the same domain.
+func WithAcyclicBreadcrumbInDomain[V comparable](
+	ctx context.Context,
+	domain BreadcrumbDomain,
+	breadcrumbID V,
+) (context.Context, bool) {
+	prevBreadcrumb := findPrevBreadcrumb(ctx, domain, breadcrumbID)
+
+	newBreadcrumb, ok := appendBreadcrumb(ctx, domain, breadcrumbID, prevBreadcrumb)
 	if !ok {
 		return nil, false
 	}
 
-	return context.WithValue(ctx, breadcrumbKey, newBreadcrumb), true
+	return context.WithValue(ctx, domain.key(), newBreadcrumb), true
+}
+
+// Breadcrumbs returns the IDs of the breadcrumbs embedded in the given context in the given
+// domain, ordered from the oldest to the most recent one.
+func Breadcrumbs(ctx context.Context, domain BreadcrumbDomain) []interface{} {
+	var trail []interface{}
+
+	bc, ok := ctx.Value(domain.key()).(*breadcrumb)
+	for ok {
+		trail = append(trail, bc.id)
+		bc, ok = bc.parentCtx.Value(domain.key()).(*breadcrumb)
+	}
+
+	// Reverse to restore the original order
+	for i, j := 0, len(trail)-1; i < j; i, j = i+1, j-1 {
+		trail[i], trail[j] = trail[j], trail[i]
+	}
+
+	return trail
 }
 
 type breadcrumb struct {
 	parentCtx context.Context
+	domain    BreadcrumbDomain
 	id        interface{}
 	prev      *breadcrumb
 }
 
-// findPrevBreadcrumb returns the previous breadcrumb having ID with the same underlying type as
-// the given breadcrumbID or nil if such breadcrumb does not exist.
-func findPrevBreadcrumb[V comparable](ctx context.Context, breadcrumbID V) *breadcrumb {
-	bc, ok := ctx.Value(breadcrumbKey).(*breadcrumb)
+// findPrevBreadcrumb returns the previous breadcrumb in the given domain having ID with the same
+// underlying type as the given breadcrumbID or nil if such breadcrumb does not exist.
+func findPrevBreadcrumb[V comparable](ctx context.Context, domain BreadcrumbDomain, breadcrumbID V) *breadcrumb {
+	bc, ok := ctx.Value(domain.key()).(*breadcrumb)
 	if !ok {
 		return nil
 	}
@@ -45,13 +94,18 @@ func findPrevBreadcrumb[V comparable](ctx context.Context, breadcrumbID V) *brea
 		return bc
 	}
 
-	return findPrevBreadcrumb(bc.parentCtx, breadcrumbID)
+	return findPrevBreadcrumb(bc.parentCtx, domain, breadcrumbID)
 }
 
 // appendBreadcrumb returns a new breadcrumb appended to the end of the existing breadcrumb chain
 // and true if no breadcrumb having the same ID exists in the chain. Otherwise, it returns nil and
 // false, indicating the execution is running in circle.
-func appendBreadcrumb[V comparable](ctx context.Context, breadcrumbID V, prev *breadcrumb) (*breadcrumb, bool) {
+func appendBreadcrumb[V comparable](
+	ctx context.Context,
+	domain BreadcrumbDomain,
+	breadcrumbID V,
+	prev *breadcrumb,
+) (*breadcrumb, bool) {
 	cur := prev
 	for cur != nil {
 		if cur.id == breadcrumbID {
@@ -63,6 +117,7 @@ func appendBreadcrumb[V comparable](ctx context.Context, breadcrumbID V, prev *b
 
 	return &breadcrumb{
 		parentCtx: ctx,
+		domain:    domain,
 		id:        breadcrumbID,
 		prev:      prev,
 	}, true
@@ -0,0 +1,154 @@
+package cext
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type breadcrumbCodec struct {
+	name   string
+	encode func(id interface{}) (string, error)
+	append func(ctx context.Context, domain BreadcrumbDomain, encoded string) (context.Context, bool, error)
+}
+
+var (
+	breadcrumbCodecsMu     sync.RWMutex
+	breadcrumbCodecsByType = make(map[reflect.Type]breadcrumbCodec)
+	breadcrumbCodecsByName = make(map[string]breadcrumbCodec)
+)
+
+// RegisterBreadcrumbCodec registers the functions to encode & decode breadcrumbIDs of type V so
+// that they can be propagated across service boundaries via MarshalBreadcrumbs and
+// UnmarshalBreadcrumbs. The given name must be unique and identical across all services.
+//
+// Note: registering another codec under the same name or for the same type V will replace the
+// existing one.
+func RegisterBreadcrumbCodec[V comparable](
+	name string,
+	encode func(V) (string, error),
+	decode func(string) (V, error),
+) {
+	codec := breadcrumbCodec{
+		name: name,
+		encode: func(id interface{}) (string, error) {
+			return encode(id.(V))
+		},
+		append: func(ctx context.Context, domain BreadcrumbDomain, encoded string) (context.Context, bool, error) {
+			id, err := decode(encoded)
+			if err != nil {
+				return nil, false, err
+			}
+
+			newCtx, ok := WithAcyclicBreadcrumbInDomain(ctx, domain, id)
+			return newCtx, ok, nil
+		},
+	}
+
+	breadcrumbCodecsMu.Lock()
+	defer breadcrumbCodecsMu.Unlock()
+
+	breadcrumbCodecsByType[reflect.TypeOf((*V)(nil)).Elem()] = codec
+	breadcrumbCodecsByName[name] = codec
+}
+
+type encodedBreadcrumb struct {
+	Domain BreadcrumbDomain `json:"d,omitempty"`
+	Codec  string           `json:"c"`
+	ID     string           `json:"id"`
+}
+
+// MarshalBreadcrumbs serializes the breadcrumb trails embedded in the given context for the given
+// domains, or the default domain if no domain is specified. Breadcrumbs having IDs of a type with
+// no registered codec are considered in-process only and will be skipped.
+func MarshalBreadcrumbs(ctx context.Context, domains ...BreadcrumbDomain) ([]byte, error) {
+	if len(domains) == 0 {
+		domains = []BreadcrumbDomain{""}
+	}
+
+	breadcrumbCodecsMu.RLock()
+	defer breadcrumbCodecsMu.RUnlock()
+
+	var encoded []encodedBreadcrumb
+	for _, domain := range domains {
+		trail, err := encodeTrail(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded = append(encoded, trail...)
+	}
+
+	return json.Marshal(encoded)
+}
+
+// encodeTrail returns the encoded breadcrumbs in the given domain, ordered from the oldest to
+// the most recent one.
+func encodeTrail(ctx context.Context, domain BreadcrumbDomain) ([]encodedBreadcrumb, error) {
+	var trail []encodedBreadcrumb
+
+	bc, ok := ctx.Value(domain.key()).(*breadcrumb)
+	for ok {
+		codec, hasCodec := breadcrumbCodecsByType[reflect.TypeOf(bc.id)]
+		if hasCodec {
+			id, err := codec.encode(bc.id)
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to encode breadcrumb %v", bc.id))
+			}
+
+			trail = append(
+				trail, encodedBreadcrumb{
+					Domain: domain,
+					Codec:  codec.name,
+					ID:     id,
+				},
+			)
+		}
+
+		bc, ok = bc.parentCtx.Value(domain.key()).(*breadcrumb)
+	}
+
+	// Reverse to restore the original order
+	for i, j := 0, len(trail)-1; i < j; i, j = i+1, j-1 {
+		trail[i], trail[j] = trail[j], trail[i]
+	}
+
+	return trail, nil
+}
+
+// UnmarshalBreadcrumbs returns a new context with the breadcrumbs serialized by MarshalBreadcrumbs
+// embedded inside so that subsequent calls to WithAcyclicBreadcrumb can detect cycles spanning
+// multiple services. It returns ErrCyclicBreadcrumbs if the execution is already running in circle.
+func UnmarshalBreadcrumbs(ctx context.Context, data []byte) (context.Context, error) {
+	var encoded []encodedBreadcrumb
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, err
+	}
+
+	breadcrumbCodecsMu.RLock()
+	defer breadcrumbCodecsMu.RUnlock()
+
+	for _, eb := range encoded {
+		codec, ok := breadcrumbCodecsByName[eb.Codec]
+		if !ok {
+			return nil, errors.Wrap(ErrBreadcrumbCodecNotFound, eb.Codec)
+		}
+
+		newCtx, ok, err := codec.append(ctx, eb.Domain, eb.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to decode breadcrumb %v", eb.ID))
+		}
+
+		if !ok {
+			return nil, ErrCyclicBreadcrumbs
+		}
+
+		ctx = newCtx
+	}
+
+	return ctx, nil
+}
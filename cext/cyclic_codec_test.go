@@ -0,0 +1,140 @@
+package cext
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nodeID int
+
+type localID int
+
+func init() {
+	RegisterBreadcrumbCodec(
+		"nodeID",
+		func(id nodeID) (string, error) {
+			return strconv.Itoa(int(id)), nil
+		},
+		func(s string) (nodeID, error) {
+			id, err := strconv.Atoi(s)
+			return nodeID(id), err
+		},
+	)
+}
+
+func TestMarshalBreadcrumbs(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no breadcrumbs",
+			test: func(t *testing.T) {
+				data, err := MarshalBreadcrumbs(context.Background())
+				assert.Nil(t, err)
+				assert.Equal(t, "null", string(data))
+			},
+		},
+		{
+			desc: "breadcrumbs without codec are skipped",
+			test: func(t *testing.T) {
+				ctx, _ := WithAcyclicBreadcrumb(context.Background(), nodeID(1))
+				ctx, _ = WithAcyclicBreadcrumb(ctx, localID(1))
+				ctx, _ = WithAcyclicBreadcrumb(ctx, nodeID(2))
+				ctx, _ = WithAcyclicBreadcrumbInDomain(ctx, "pricing", nodeID(3))
+
+				data, err := MarshalBreadcrumbs(ctx)
+				assert.Nil(t, err)
+				assert.JSONEq(t, `[{"c":"nodeID","id":"1"},{"c":"nodeID","id":"2"}]`, string(data))
+
+				data, err = MarshalBreadcrumbs(ctx, "", "pricing")
+				assert.Nil(t, err)
+				assert.JSONEq(
+					t,
+					`[{"c":"nodeID","id":"1"},{"c":"nodeID","id":"2"},{"d":"pricing","c":"nodeID","id":"3"}]`,
+					string(data),
+				)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestUnmarshalBreadcrumbs(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "round trip detects cycles spanning services",
+			test: func(t *testing.T) {
+				upstreamCtx, _ := WithAcyclicBreadcrumb(context.Background(), nodeID(1))
+				upstreamCtx, _ = WithAcyclicBreadcrumbInDomain(upstreamCtx, "pricing", nodeID(2))
+
+				data, err := MarshalBreadcrumbs(upstreamCtx, "", "pricing")
+				assert.Nil(t, err)
+
+				downstreamCtx, err := UnmarshalBreadcrumbs(context.Background(), data)
+				assert.Nil(t, err)
+
+				_, ok := WithAcyclicBreadcrumb(downstreamCtx, nodeID(1))
+				assert.False(t, ok)
+
+				_, ok = WithAcyclicBreadcrumb(downstreamCtx, nodeID(2))
+				assert.True(t, ok)
+
+				_, ok = WithAcyclicBreadcrumbInDomain(downstreamCtx, "pricing", nodeID(2))
+				assert.False(t, ok)
+			},
+		},
+		{
+			desc: "invalid payload",
+			test: func(t *testing.T) {
+				ctx, err := UnmarshalBreadcrumbs(context.Background(), []byte("{"))
+				assert.Nil(t, ctx)
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			desc: "unknown codec",
+			test: func(t *testing.T) {
+				ctx, err := UnmarshalBreadcrumbs(context.Background(), []byte(`[{"c":"unknown","id":"1"}]`))
+				assert.Nil(t, ctx)
+				assert.True(t, errors.Is(err, ErrBreadcrumbCodecNotFound))
+			},
+		},
+		{
+			desc: "undecodable ID",
+			test: func(t *testing.T) {
+				ctx, err := UnmarshalBreadcrumbs(context.Background(), []byte(`[{"c":"nodeID","id":"x"}]`))
+				assert.Nil(t, ctx)
+				assert.NotNil(t, err)
+			},
+		},
+		{
+			desc: "incoming trail is already cyclic",
+			test: func(t *testing.T) {
+				ctx, err := UnmarshalBreadcrumbs(
+					context.Background(), []byte(`[{"c":"nodeID","id":"1"},{"c":"nodeID","id":"1"}]`),
+				)
+				assert.Nil(t, ctx)
+				assert.Equal(t, ErrCyclicBreadcrumbs, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
@@ -32,3 +32,44 @@ func TestWithAcyclicBreadcrumb(t *testing.T) {
 	assert.Nil(t, ctxWithBadBreadcrumb)
 	assert.False(t, ok)
 }
+
+func TestWithAcyclicBreadcrumbInDomain(t *testing.T) {
+	pricing := BreadcrumbDomain("pricing")
+	routing := BreadcrumbDomain("routing")
+
+	// New breadcrumb with ID as 1 in pricing domain
+	ctx, ok := WithAcyclicBreadcrumbInDomain(context.Background(), pricing, 1)
+	assert.NotNil(t, ctx)
+	assert.True(t, ok)
+
+	// Same ID in another domain must not be considered as a cycle
+	ctx, ok = WithAcyclicBreadcrumbInDomain(ctx, routing, 1)
+	assert.NotNil(t, ctx)
+	assert.True(t, ok)
+
+	// Same ID in the default domain must not be considered as a cycle
+	ctx, ok = WithAcyclicBreadcrumb(ctx, 1)
+	assert.NotNil(t, ctx)
+	assert.True(t, ok)
+
+	// Old breadcrumb with ID as 1 in pricing domain
+	ctxWithBadBreadcrumb, ok := WithAcyclicBreadcrumbInDomain(ctx, pricing, 1)
+	assert.Nil(t, ctxWithBadBreadcrumb)
+	assert.False(t, ok)
+
+	// Old breadcrumb with ID as 1 in default domain
+	ctxWithBadBreadcrumb, ok = WithAcyclicBreadcrumbInDomain(ctx, "", 1)
+	assert.Nil(t, ctxWithBadBreadcrumb)
+	assert.False(t, ok)
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	assert.Empty(t, Breadcrumbs(context.Background(), ""))
+
+	ctx, _ := WithAcyclicBreadcrumb(context.Background(), 1)
+	ctx, _ = WithAcyclicBreadcrumbInDomain(ctx, "other", 2)
+	ctx, _ = WithAcyclicBreadcrumb(ctx, "a")
+
+	assert.Equal(t, []interface{}{1, "a"}, Breadcrumbs(ctx, ""))
+	assert.Equal(t, []interface{}{2}, Breadcrumbs(ctx, "other"))
+}
@@ -3,21 +3,49 @@ package cext
 import (
     "context"
     "fmt"
+    "sync"
     "time"
+
+    "github.com/jamestrandung/go-context/helper"
 )
 
+// DelegateOption configures the context returned by Delegate.
+type DelegateOption func(*delegatingContext)
+
+// WithValueCache memoizes the results of Value lookups for the lifetime of the
+// returned context. This is useful when the valueCtx sits at the end of a deep
+// chain and the same keys are requested over and over again.
+//
+// Note: values stored in a context are immutable, hence caching them is safe.
+// Only keys resolving to a non-nil value are cached so that the cache can't grow
+// beyond the keys present in the chain. Keys that are missing or not comparable
+// are always looked up without caching.
+func WithValueCache() DelegateOption {
+    return func(c *delegatingContext) {
+        c.values = &sync.Map{}
+    }
+}
+
 // Delegate returns a context that keeps all values of the valueCtx while
 // taking its cancellation signal and error from the cancelCtx.
-func Delegate(cancelCtx context.Context, valueCtx context.Context) context.Context {
-    return &delegatingContext{
+func Delegate(cancelCtx context.Context, valueCtx context.Context, opts ...DelegateOption) context.Context {
+    c := &delegatingContext{
         cancelCtx: cancelCtx,
         valueCtx:  valueCtx,
     }
+
+    for _, opt := range opts {
+        opt(c)
+    }
+
+    return c
 }
 
 type delegatingContext struct {
     cancelCtx context.Context
     valueCtx  context.Context
+    // values caches the results of Value lookups, nil if caching is disabled.
+    values *sync.Map
 }
 
 // Deadline ...
@@ -37,7 +65,21 @@ func (c *delegatingContext) Err() error {
 
 // Value ...
 func (c *delegatingContext) Value(key interface{}) interface{} {
-    return c.valueCtx.Value(key)
+    if c.values == nil || !helper.TryComparable(key) {
+        return c.valueCtx.Value(key)
+    }
+
+    if cached, ok := c.values.Load(key); ok {
+        return cached
+    }
+
+    value := c.valueCtx.Value(key)
+    if value != nil {
+        // Caching misses would grow the cache with every distinct key requested
+        c.values.Store(key, value)
+    }
+
+    return value
 }
 
 // String ...
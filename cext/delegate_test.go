@@ -0,0 +1,98 @@
+package cext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingContext struct {
+	context.Context
+	lookups int
+}
+
+func (c *countingContext) Value(key interface{}) interface{} {
+	c.lookups++
+	return c.Context.Value(key)
+}
+
+type delegateTestKey struct{}
+
+func TestDelegate(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "signals come from cancelCtx while values come from valueCtx",
+			test: func(t *testing.T) {
+				cancelCtx, cancel := context.WithCancel(context.Background())
+				valueCtx := context.WithValue(context.Background(), delegateTestKey{}, "value")
+
+				ctx := Delegate(cancelCtx, valueCtx)
+				assert.Equal(t, "value", ctx.Value(delegateTestKey{}))
+				assert.Nil(t, ctx.Err())
+
+				cancel()
+
+				assert.Equal(t, context.Canceled, ctx.Err())
+				<-ctx.Done()
+			},
+		},
+		{
+			desc: "without value cache",
+			test: func(t *testing.T) {
+				valueCtx := &countingContext{
+					Context: context.WithValue(context.Background(), delegateTestKey{}, "value"),
+				}
+
+				ctx := Delegate(context.Background(), valueCtx)
+				for i := 0; i < 10; i++ {
+					assert.Equal(t, "value", ctx.Value(delegateTestKey{}))
+				}
+
+				assert.Equal(t, 10, valueCtx.lookups)
+			},
+		},
+		{
+			desc: "with value cache",
+			test: func(t *testing.T) {
+				valueCtx := &countingContext{
+					Context: context.WithValue(context.Background(), delegateTestKey{}, "value"),
+				}
+
+				ctx := Delegate(context.Background(), valueCtx, WithValueCache())
+				for i := 0; i < 10; i++ {
+					assert.Equal(t, "value", ctx.Value(delegateTestKey{}))
+					assert.Nil(t, ctx.Value("missing"))
+				}
+
+				// Only the hit is cached, misses are looked up every time
+				assert.Equal(t, 11, valueCtx.lookups)
+			},
+		},
+		{
+			desc: "with value cache and non-comparable key",
+			test: func(t *testing.T) {
+				valueCtx := &countingContext{
+					Context: context.Background(),
+				}
+
+				ctx := Delegate(context.Background(), valueCtx, WithValueCache())
+				assert.NotPanics(t, func() {
+					assert.Nil(t, ctx.Value([]int{1}))
+					assert.Nil(t, ctx.Value([]int{1}))
+				})
+
+				assert.Equal(t, 2, valueCtx.lookups)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
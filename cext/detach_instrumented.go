@@ -0,0 +1,20 @@
+package cext
+
+import (
+	"context"
+
+	"github.com/jamestrandung/go-context/instrument"
+)
+
+// StartDetached detaches the given context using DetachTraced and starts a span for the
+// background work using the given Instrumentation, or the default one set by
+// instrument.SetDefault if it is nil. Unlike StartDetachedSpan, it works with any
+// Instrumentation instead of an OpenTelemetry Tracer.
+func StartDetached(
+	ctx context.Context,
+	i instrument.Instrumentation,
+	spanName string,
+	attrs ...instrument.Attr,
+) (context.Context, instrument.Span) {
+	return instrument.OrDefault(i).StartSpan(DetachTraced(ctx), spanName, attrs...)
+}
@@ -0,0 +1,73 @@
+package cext
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jamestrandung/go-context/instrument"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type spanningInstrumentation struct {
+	instrument.Noop
+	spanNames []string
+	spanCtxs  []context.Context
+}
+
+func (i *spanningInstrumentation) StartSpan(
+	ctx context.Context,
+	name string,
+	attrs ...instrument.Attr,
+) (context.Context, instrument.Span) {
+	i.spanNames = append(i.spanNames, name)
+	i.spanCtxs = append(i.spanCtxs, ctx)
+
+	return i.Noop.StartSpan(ctx, name, attrs...)
+}
+
+func TestStartDetached(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "explicit instrumentation",
+			test: func(t *testing.T) {
+				i := &spanningInstrumentation{}
+
+				ctx := trace.ContextWithSpanContext(context.Background(), newTestSpanContext())
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				detached, span := StartDetached(ctx, i, "background")
+				span.End(nil)
+
+				assert.Nil(t, detached.Err())
+				assert.Equal(t, []string{"background"}, i.spanNames)
+				assert.Nil(t, i.spanCtxs[0].Err())
+				assert.Equal(t, newTestSpanContext().TraceID(), trace.SpanContextFromContext(i.spanCtxs[0]).TraceID())
+			},
+		},
+		{
+			desc: "default instrumentation",
+			test: func(t *testing.T) {
+				i := &spanningInstrumentation{}
+
+				instrument.SetDefault(i)
+				defer instrument.SetDefault(nil)
+
+				_, span := StartDetached(context.Background(), nil, "background")
+				span.End(nil)
+
+				assert.Equal(t, []string{"background"}, i.spanNames)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
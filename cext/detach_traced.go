@@ -0,0 +1,48 @@
+package cext
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DetachTraced returns a context that keeps all values of the parent context
+// but detaches from its cancellation and error handling, similar to Detach.
+//
+// In addition, the span context & baggage that are active in the parent context
+// are guaranteed to be re-attached to the returned context. Only the span context
+// is carried over instead of the span itself because the parent span will most
+// likely end before the detached work does.
+func DetachTraced(ctx context.Context) context.Context {
+	detached := Detach(ctx)
+
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		detached = trace.ContextWithRemoteSpanContext(detached, sc)
+	}
+
+	if b := baggage.FromContext(ctx); b.Len() > 0 {
+		detached = baggage.ContextWithBaggage(detached, b)
+	}
+
+	return detached
+}
+
+// StartDetachedSpan detaches the given context using DetachTraced and starts a new
+// root span for the background work. This span is linked to the span that is active
+// in the parent context, if any, so that both traces can be navigated from each other.
+func StartDetachedSpan(
+	ctx context.Context,
+	tracer trace.Tracer,
+	spanName string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	detached := DetachTraced(ctx)
+
+	startOpts := []trace.SpanStartOption{trace.WithNewRoot()}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		startOpts = append(startOpts, trace.WithLinks(trace.Link{SpanContext: sc}))
+	}
+
+	return tracer.Start(detached, spanName, append(startOpts, opts...)...)
+}
@@ -0,0 +1,127 @@
+package cext
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingTracer struct {
+	trace.Tracer
+	configs []trace.SpanConfig
+}
+
+func (t *recordingTracer) Start(
+	ctx context.Context,
+	spanName string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	t.configs = append(t.configs, trace.NewSpanStartConfig(opts...))
+	return trace.NewNoopTracerProvider().Tracer("").Start(ctx, spanName, opts...)
+}
+
+func newTestSpanContext() trace.SpanContext {
+	return trace.NewSpanContext(
+		trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{2},
+			TraceFlags: trace.FlagsSampled,
+		},
+	)
+}
+
+func TestDetachTraced(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "span context and baggage are carried over",
+			test: func(t *testing.T) {
+				member, _ := baggage.NewMember("tenant", "abc")
+				b, _ := baggage.New(member)
+
+				ctx := trace.ContextWithSpanContext(context.Background(), newTestSpanContext())
+				ctx = baggage.ContextWithBaggage(ctx, b)
+				ctx, cancel := context.WithTimeout(ctx, time.Minute)
+				cancel()
+
+				detached := DetachTraced(ctx)
+
+				assert.Nil(t, detached.Err())
+				assert.Nil(t, detached.Done())
+
+				_, hasDeadline := detached.Deadline()
+				assert.False(t, hasDeadline)
+
+				sc := trace.SpanContextFromContext(detached)
+				assert.Equal(t, newTestSpanContext().TraceID(), sc.TraceID())
+				assert.Equal(t, newTestSpanContext().SpanID(), sc.SpanID())
+				assert.Equal(t, "abc", baggage.FromContext(detached).Member("tenant").Value())
+			},
+		},
+		{
+			desc: "nothing to carry over",
+			test: func(t *testing.T) {
+				detached := DetachTraced(context.Background())
+
+				assert.False(t, trace.SpanContextFromContext(detached).IsValid())
+				assert.Equal(t, 0, baggage.FromContext(detached).Len())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestStartDetachedSpan(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "background span is linked to the active span",
+			test: func(t *testing.T) {
+				tracer := &recordingTracer{}
+
+				ctx := trace.ContextWithSpanContext(context.Background(), newTestSpanContext())
+				_, span := StartDetachedSpan(ctx, tracer, "background", trace.WithSpanKind(trace.SpanKindInternal))
+				defer span.End()
+
+				assert.Equal(t, 1, len(tracer.configs))
+
+				cfg := tracer.configs[0]
+				assert.True(t, cfg.NewRoot())
+				assert.Equal(t, trace.SpanKindInternal, cfg.SpanKind())
+				assert.Equal(t, 1, len(cfg.Links()))
+				assert.Equal(t, newTestSpanContext(), cfg.Links()[0].SpanContext)
+			},
+		},
+		{
+			desc: "no active span",
+			test: func(t *testing.T) {
+				tracer := &recordingTracer{}
+
+				_, span := StartDetachedSpan(context.Background(), tracer, "background")
+				defer span.End()
+
+				assert.Equal(t, 1, len(tracer.configs))
+				assert.Empty(t, tracer.configs[0].Links())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
@@ -0,0 +1,12 @@
+package cext
+
+import (
+	"errors"
+
+	"github.com/jamestrandung/go-context/ctxerr"
+)
+
+var (
+	ErrBreadcrumbCodecNotFound = errors.New("no breadcrumb codec registered under this name")
+	ErrCyclicBreadcrumbs       = ctxerr.New(ctxerr.Cycle, "breadcrumb trail is running in circle")
+)
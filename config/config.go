@@ -0,0 +1,147 @@
+// Package config lets the packages of this library be set up from one declarative
+// Config, so that organization-wide defaults can be applied in one place, e.g. from
+// environment variables.
+package config
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/jamestrandung/go-context/instrument"
+	"github.com/jamestrandung/go-context/memoize"
+)
+
+// Config holds the settings of every package of this library.
+type Config struct {
+	Memoize MemoizeConfig
+	Dvow    DvowConfig
+	Cext    CextConfig
+	// Instrumentation receives the spans & metrics of memoize caches and dvow storages
+	// created using this Config. It defaults to instrument.Default() if nil.
+	Instrumentation instrument.Instrumentation
+}
+
+// MemoizeConfig holds the settings of memoize caches.
+type MemoizeConfig struct {
+	// ConcurrencyLevel is the number of shards of caches created by WithCache. It
+	// defaults to the default of memoize.WithConcurrentCache if zero.
+	ConcurrencyLevel int
+	// TTL enables memoize.WithDefaultTTL if positive.
+	TTL time.Duration
+	// MaxEntries enables memoize.WithMaxEntries if positive.
+	MaxEntries int
+	// ExecutionLimit enables memoize.WithExecutionLimit if positive.
+	ExecutionLimit int
+	// PanicHandler enables memoize.WithPanicHandler if not nil.
+	PanicHandler memoize.PanicHandler
+	// PropagatePanics enables memoize.WithPanicPropagation.
+	PropagatePanics bool
+}
+
+// DvowConfig holds the settings of the storages of overwritten variables.
+type DvowConfig struct {
+	// Lenient enables dvow.LenientConversion.
+	Lenient bool
+	// DeepCopy enables dvow.DeepCopyValues.
+	DeepCopy bool
+}
+
+// CextConfig holds the settings of the contexts created by cext.
+type CextConfig struct {
+	// ValueCache enables cext.WithValueCache on contexts created by Delegate.
+	ValueCache bool
+}
+
+var defaultConfig atomic.Value
+
+func init() {
+	defaultConfig.Store(Config{})
+}
+
+// Default returns the Config set by SetDefault, or the zero Config if it was never
+// called.
+func Default() Config {
+	return defaultConfig.Load().(Config)
+}
+
+// SetDefault sets the Config returned by Default. It should be called once during the
+// initialization of a program.
+func SetDefault(cfg Config) {
+	defaultConfig.Store(cfg)
+}
+
+// WithCache calls memoize.WithConcurrentCache using the settings of this Config.
+func (c Config) WithCache(ctx context.Context) (context.Context, memoize.DestroyFn) {
+	return memoize.WithConcurrentCache(ctx, c.Memoize.ConcurrencyLevel, c.MemoizeOptions()...)
+}
+
+// MemoizeOptions returns the memoize.Option matching the settings of this Config.
+func (c Config) MemoizeOptions() []memoize.Option {
+	var opts []memoize.Option
+	if c.Memoize.TTL > 0 {
+		opts = append(opts, memoize.WithDefaultTTL(c.Memoize.TTL))
+	}
+
+	if c.Memoize.MaxEntries > 0 {
+		opts = append(opts, memoize.WithMaxEntries(c.Memoize.MaxEntries))
+	}
+
+	if c.Memoize.ExecutionLimit > 0 {
+		opts = append(opts, memoize.WithExecutionLimit(c.Memoize.ExecutionLimit))
+	}
+
+	if c.Memoize.PanicHandler != nil {
+		opts = append(opts, memoize.WithPanicHandler(c.Memoize.PanicHandler))
+	}
+
+	if c.Memoize.PropagatePanics {
+		opts = append(opts, memoize.WithPanicPropagation())
+	}
+
+	if c.Instrumentation != nil {
+		opts = append(opts, memoize.WithInstrumentation(c.Instrumentation))
+	}
+
+	return opts
+}
+
+// DvowOptions returns the dvow.Option matching the settings of this Config.
+func (c Config) DvowOptions() []dvow.Option {
+	var opts []dvow.Option
+	if c.Dvow.Lenient {
+		opts = append(opts, dvow.LenientConversion())
+	}
+
+	if c.Dvow.DeepCopy {
+		opts = append(opts, dvow.DeepCopyValues())
+	}
+
+	if c.Instrumentation != nil {
+		opts = append(opts, dvow.WithInstrumentation(c.Instrumentation))
+	}
+
+	return opts
+}
+
+// WithOverwrittenVariables calls dvow.WithOverwrittenVariables using the settings of
+// this Config. The given options are applied after those of this Config.
+func (c Config) WithOverwrittenVariables(
+	ctx context.Context,
+	overwrittenVariables map[string]interface{},
+	opts ...dvow.Option,
+) context.Context {
+	return dvow.Ops.WithOverwrittenVariables(ctx, overwrittenVariables, append(c.DvowOptions(), opts...)...)
+}
+
+// Delegate calls cext.Delegate using the settings of this Config.
+func (c Config) Delegate(cancelCtx context.Context, valueCtx context.Context) context.Context {
+	var opts []cext.DelegateOption
+	if c.Cext.ValueCache {
+		opts = append(opts, cext.WithValueCache())
+	}
+
+	return cext.Delegate(cancelCtx, valueCtx, opts...)
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/jamestrandung/go-context/instrument"
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/stretchr/testify/assert"
+)
+
+type configTestKey struct{}
+
+func TestDefault(t *testing.T) {
+	defer SetDefault(Config{})
+
+	assert.Equal(t, Config{}, Default())
+
+	cfg := Config{Memoize: MemoizeConfig{ConcurrencyLevel: 16}}
+	SetDefault(cfg)
+	assert.Equal(t, cfg, Default())
+}
+
+func TestConfig(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "WithCache",
+			test: func(t *testing.T) {
+				ctx, destroyFn := Config{Memoize: MemoizeConfig{ConcurrencyLevel: 16}}.WithCache(context.Background())
+				defer destroyFn()
+
+				_, extra := memoize.Execute(ctx, configTestKey{}, func(context.Context) (int, error) {
+					return 1, nil
+				})
+				assert.True(t, extra.IsMemoized)
+			},
+		},
+		{
+			desc: "MemoizeOptions",
+			test: func(t *testing.T) {
+				var panicked interface{}
+				cfg := Config{
+					Memoize: MemoizeConfig{
+						ConcurrencyLevel: 16,
+						TTL:              30 * time.Second,
+						MaxEntries:       100,
+						ExecutionLimit:   8,
+						PanicHandler: func(executionKey interface{}, recovered interface{}, stack []byte) {
+							panicked = recovered
+						},
+						PropagatePanics: true,
+					},
+				}
+				assert.Len(t, cfg.MemoizeOptions(), 5)
+
+				ctx, destroyFn := cfg.WithCache(context.Background())
+				defer destroyFn()
+
+				assert.Panics(t, func() {
+					memoize.Execute(ctx, configTestKey{}, func(context.Context) (int, error) {
+						panic("boom")
+					})
+				})
+				assert.Equal(t, "boom", panicked)
+			},
+		},
+		{
+			desc: "WithOverwrittenVariables",
+			test: func(t *testing.T) {
+				cfg := Config{Dvow: DvowConfig{Lenient: true, DeepCopy: true}}
+				assert.Len(t, cfg.DvowOptions(), 2)
+				assert.Empty(t, Config{}.DvowOptions())
+
+				list := []interface{}{1}
+				ctx := cfg.WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"count": "2",
+						"list":  list,
+					},
+				)
+
+				list[0] = 2
+
+				assert.Equal(t, int64(2), dvow.Ops.GetOverwrittenValue(ctx, "count").AsInt())
+				assert.Equal(t, []interface{}{1}, dvow.Ops.GetOverwrittenValue(ctx, "list").AsIs())
+			},
+		},
+		{
+			desc: "Instrumentation",
+			test: func(t *testing.T) {
+				assert.Empty(t, Config{}.MemoizeOptions())
+
+				cfg := Config{Dvow: DvowConfig{Lenient: true}, Instrumentation: instrument.Noop{}}
+				assert.Len(t, cfg.MemoizeOptions(), 1)
+				assert.Len(t, cfg.DvowOptions(), 2)
+			},
+		},
+		{
+			desc: "Delegate",
+			test: func(t *testing.T) {
+				cancelCtx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				valueCtx := context.WithValue(context.Background(), configTestKey{}, "value")
+
+				ctx := Config{Cext: CextConfig{ValueCache: true}}.Delegate(cancelCtx, valueCtx)
+				assert.Equal(t, context.Canceled, ctx.Err())
+				assert.Equal(t, "value", ctx.Value(configTestKey{}))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
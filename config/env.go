@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables read by FromEnv.
+const DefaultEnvPrefix = "GOCONTEXT_"
+
+// FromEnv returns Default with its settings overridden by the environment variables
+// below, each name being preceded by the given prefix:
+//   - MEMOIZE_CONCURRENCY_LEVEL: MemoizeConfig.ConcurrencyLevel
+//   - MEMOIZE_TTL: MemoizeConfig.TTL
+//   - MEMOIZE_MAX_ENTRIES: MemoizeConfig.MaxEntries
+//   - MEMOIZE_EXECUTION_LIMIT: MemoizeConfig.ExecutionLimit
+//   - MEMOIZE_PROPAGATE_PANICS: MemoizeConfig.PropagatePanics
+//   - DVOW_LENIENT: DvowConfig.Lenient
+//   - DVOW_DEEP_COPY: DvowConfig.DeepCopy
+//   - CEXT_VALUE_CACHE: CextConfig.ValueCache
+//
+// Settings whose variables are not set keep their default values. Booleans accept the
+// values supported by strconv.ParseBool, and durations those supported by
+// time.ParseDuration. It returns an error wrapping ErrInvalidEnv if
+// a variable cannot be parsed.
+func FromEnv(prefix string) (Config, error) {
+	cfg := Default()
+
+	if err := lookupInt(prefix+"MEMOIZE_CONCURRENCY_LEVEL", &cfg.Memoize.ConcurrencyLevel); err != nil {
+		return Config{}, err
+	}
+
+	if err := lookupDuration(prefix+"MEMOIZE_TTL", &cfg.Memoize.TTL); err != nil {
+		return Config{}, err
+	}
+
+	if err := lookupInt(prefix+"MEMOIZE_MAX_ENTRIES", &cfg.Memoize.MaxEntries); err != nil {
+		return Config{}, err
+	}
+
+	if err := lookupInt(prefix+"MEMOIZE_EXECUTION_LIMIT", &cfg.Memoize.ExecutionLimit); err != nil {
+		return Config{}, err
+	}
+
+	if err := lookupBool(prefix+"MEMOIZE_PROPAGATE_PANICS", &cfg.Memoize.PropagatePanics); err != nil {
+		return Config{}, err
+	}
+
+	if err := lookupBool(prefix+"DVOW_LENIENT", &cfg.Dvow.Lenient); err != nil {
+		return Config{}, err
+	}
+
+	if err := lookupBool(prefix+"DVOW_DEEP_COPY", &cfg.Dvow.DeepCopy); err != nil {
+		return Config{}, err
+	}
+
+	if err := lookupBool(prefix+"CEXT_VALUE_CACHE", &cfg.Cext.ValueCache); err != nil {
+		return Config{}, err
+	}
+
+	return cfg, nil
+}
+
+func lookupInt(name string, target *int) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return errors.Wrap(ErrInvalidEnv, fmt.Sprintf("%s=%q is not a non-negative integer", name, value))
+	}
+
+	*target = parsed
+
+	return nil
+}
+
+func lookupBool(name string, target *bool) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return errors.Wrap(ErrInvalidEnv, fmt.Sprintf("%s=%q is not a boolean", name, value))
+	}
+
+	*target = parsed
+
+	return nil
+}
+
+func lookupDuration(name string, target *time.Duration) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return errors.Wrap(ErrInvalidEnv, fmt.Sprintf("%s=%q is not a non-negative duration", name, value))
+	}
+
+	*target = parsed
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEnv(t *testing.T) {
+	scenarios := []struct {
+		desc     string
+		env      map[string]string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			desc:     "no variables",
+			env:      map[string]string{},
+			expected: Config{},
+		},
+		{
+			desc: "all variables",
+			env: map[string]string{
+				"TEST_MEMOIZE_CONCURRENCY_LEVEL": "16",
+				"TEST_MEMOIZE_TTL":               "30s",
+				"TEST_MEMOIZE_MAX_ENTRIES":       "100",
+				"TEST_MEMOIZE_EXECUTION_LIMIT":   "8",
+				"TEST_MEMOIZE_PROPAGATE_PANICS":  "true",
+				"TEST_DVOW_LENIENT":              "true",
+				"TEST_DVOW_DEEP_COPY":            "1",
+				"TEST_CEXT_VALUE_CACHE":          "TRUE",
+			},
+			expected: Config{
+				Memoize: MemoizeConfig{
+					ConcurrencyLevel: 16,
+					TTL:              30 * time.Second,
+					MaxEntries:       100,
+					ExecutionLimit:   8,
+					PropagatePanics:  true,
+				},
+				Dvow: DvowConfig{Lenient: true, DeepCopy: true},
+				Cext: CextConfig{ValueCache: true},
+			},
+		},
+		{
+			desc: "invalid integer",
+			env: map[string]string{
+				"TEST_MEMOIZE_CONCURRENCY_LEVEL": "-1",
+			},
+			wantErr: true,
+		},
+		{
+			desc: "invalid duration",
+			env: map[string]string{
+				"TEST_MEMOIZE_TTL": "30",
+			},
+			wantErr: true,
+		},
+		{
+			desc: "invalid boolean",
+			env: map[string]string{
+				"TEST_DVOW_LENIENT": "maybe",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			for name, value := range sc.env {
+				t.Setenv(name, value)
+			}
+
+			actual, err := FromEnv("TEST_")
+			if sc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidEnv)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, sc.expected, actual)
+		})
+	}
+}
+
+func TestFromEnv_Default(t *testing.T) {
+	defer SetDefault(Config{})
+
+	SetDefault(Config{Memoize: MemoizeConfig{ConcurrencyLevel: 16}, Dvow: DvowConfig{Lenient: true}})
+	t.Setenv("TEST_DVOW_LENIENT", "false")
+
+	actual, err := FromEnv("TEST_")
+	assert.Nil(t, err)
+	assert.Equal(t, Config{Memoize: MemoizeConfig{ConcurrencyLevel: 16}}, actual)
+}
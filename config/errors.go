@@ -0,0 +1,9 @@
+package config
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidEnv = errors.New("invalid environment variable")
+)
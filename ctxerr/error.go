@@ -0,0 +1,103 @@
+// Package ctxerr classifies the errors returned by the packages of this library so that
+// callers can branch on the kind of an error instead of comparing it against sentinel
+// errors scattered across packages.
+package ctxerr
+
+import (
+	"context"
+	"errors"
+)
+
+// Kind classifies errors. Every Kind is an error itself so that errors.Is(err, kind)
+// reports whether err is an Error of this Kind.
+type Kind string
+
+// Various kinds.
+const (
+	// Unknown is the Kind of errors that were not classified.
+	Unknown Kind = ""
+	// NotMemoized is the Kind of errors returned when an outcome is not memoized.
+	NotMemoized Kind = "not memoized"
+	// CacheDestroyed is the Kind of errors returned when using a destroyed cache.
+	CacheDestroyed Kind = "cache destroyed"
+	// Cycle is the Kind of errors returned when an execution is running in circle.
+	Cycle Kind = "cycle"
+	// ConversionFailed is the Kind of errors returned when a value cannot be converted
+	// to the requested type.
+	ConversionFailed Kind = "conversion failed"
+	// Cancelled is the Kind of errors returned when a context was cancelled or its
+	// deadline exceeded.
+	Cancelled Kind = "cancelled"
+)
+
+// Error implements the error interface.
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// Error is an error of a given Kind.
+type Error struct {
+	Kind    Kind
+	Message string
+	// Cause is the underlying error, if any.
+	Cause error
+}
+
+// New returns an Error of the given Kind with the given message.
+func New(kind Kind, message string) error {
+	return &Error{
+		Kind:    kind,
+		Message: message,
+	}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	if e.Cause == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Cause.Error()
+}
+
+// Unwrap returns the Cause of this Error.
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is returns whether target is the Kind of this Error.
+func (e *Error) Is(target error) bool {
+	kind, ok := target.(Kind)
+	return ok && kind == e.Kind
+}
+
+// KindOf returns the Kind of the first Error in the chain of err. Errors of the context
+// package are classified as Cancelled. It returns Unknown if err is nil or not
+// classified.
+func KindOf(err error) Kind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return Cancelled
+	}
+
+	return Unknown
+}
+
+// FromContext returns an Error of Kind Cancelled whose Cause is ctx.Err(), or nil if
+// ctx is not done.
+func FromContext(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	return &Error{
+		Kind:    Cancelled,
+		Message: "context is done",
+		Cause:   err,
+	}
+}
@@ -0,0 +1,71 @@
+package ctxerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "message",
+			test: func(t *testing.T) {
+				assert.Equal(t, "failed", New(Cycle, "failed").Error())
+				assert.Equal(t, "failed: cause", (&Error{Kind: Cycle, Message: "failed", Cause: errors.New("cause")}).Error())
+			},
+		},
+		{
+			desc: "errors.Is and errors.As through wrapping",
+			test: func(t *testing.T) {
+				sentinel := New(ConversionFailed, "cannot convert")
+				err := fmt.Errorf("outer: %w", pkgerrors.Wrap(sentinel, "inner"))
+
+				assert.ErrorIs(t, err, sentinel)
+				assert.ErrorIs(t, err, ConversionFailed)
+				assert.False(t, errors.Is(err, Cycle))
+
+				var e *Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, ConversionFailed, e.Kind)
+			},
+		},
+		{
+			desc: "KindOf",
+			test: func(t *testing.T) {
+				assert.Equal(t, Unknown, KindOf(nil))
+				assert.Equal(t, Unknown, KindOf(errors.New("plain")))
+				assert.Equal(t, CacheDestroyed, KindOf(pkgerrors.Wrap(New(CacheDestroyed, "destroyed"), "wrapped")))
+				assert.Equal(t, Cancelled, KindOf(context.Canceled))
+				assert.Equal(t, Cancelled, KindOf(fmt.Errorf("wrapped: %w", context.DeadlineExceeded)))
+			},
+		},
+		{
+			desc: "FromContext",
+			test: func(t *testing.T) {
+				assert.Nil(t, FromContext(context.Background()))
+
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				err := FromContext(ctx)
+				assert.ErrorIs(t, err, Cancelled)
+				assert.ErrorIs(t, err, context.Canceled)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ctxerr_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/ctxerr"
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type kindsTestKey struct{}
+
+func TestKinds(t *testing.T) {
+	scenarios := []struct {
+		desc     string
+		err      func() error
+		expected ctxerr.Kind
+	}{
+		{
+			desc: "destroyed cache",
+			err: func() error {
+				ctx, destroyFn := memoize.WithCache(context.Background())
+				destroyFn()
+
+				outcome, _ := memoize.Execute(ctx, kindsTestKey{}, func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				return outcome.Err
+			},
+			expected: ctxerr.CacheDestroyed,
+		},
+		{
+			desc: "cancelled wait",
+			err: func() error {
+				ctx, destroyFn := memoize.WithCache(context.Background())
+				defer destroyFn()
+
+				ctx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				outcome, _ := memoize.Execute(ctx, kindsTestKey{}, func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				return outcome.Err
+			},
+			expected: ctxerr.Cancelled,
+		},
+		{
+			desc: "cyclic breadcrumbs",
+			err: func() error {
+				return errors.Wrap(cext.ErrCyclicBreadcrumbs, "trail 1 -> 2 -> 1")
+			},
+			expected: ctxerr.Cycle,
+		},
+		{
+			desc: "failed conversion",
+			err: func() error {
+				ctx := dvow.WithOverwrittenVariables(context.Background(), map[string]interface{}{"count": "a"})
+
+				_, err := dvow.Ops.GetOverwrittenValue(ctx, "count").AsIntE()
+				return err
+			},
+			expected: ctxerr.ConversionFailed,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			assert.Equal(t, sc.expected, ctxerr.KindOf(sc.err()))
+		})
+	}
+
+}
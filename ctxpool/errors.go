@@ -0,0 +1,9 @@
+package ctxpool
+
+import (
+	"errors"
+)
+
+var (
+	ErrPoolClosed = errors.New("pool already closed, cannot accept tasks anymore")
+)
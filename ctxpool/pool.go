@@ -0,0 +1,154 @@
+// Package ctxpool provides a bounded pool of goroutines running tasks with contexts that
+// keep the values of the submitting context but not its cancellation, so that the same
+// pool can be shared by memoize caches and the background tasks of a request.
+package ctxpool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/jamestrandung/go-context/cext"
+)
+
+// Option configures a Pool.
+type Option func(*Pool)
+
+// WithPanicHandler makes the Pool call fn with the value recovered from tasks that
+// panicked. Without it, such panics are swallowed to keep the program running.
+func WithPanicHandler(fn func(recovered interface{})) Option {
+	return func(p *Pool) {
+		p.panicHandler = fn
+	}
+}
+
+// Pool runs tasks on at most a fixed number of goroutines at a time.
+type Pool struct {
+	slots        chan struct{}
+	panicHandler func(recovered interface{})
+
+	mu       sync.RWMutex
+	isClosed bool
+	closed   chan struct{}
+	running  sync.WaitGroup
+}
+
+// New returns a Pool running at most size tasks concurrently. It defaults to
+// runtime.GOMAXPROCS(0) if size is not positive.
+func New(size int, opts ...Option) *Pool {
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+
+	p := &Pool{
+		slots:  make(chan struct{}, size),
+		closed: make(chan struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Submit runs fn on a goroutine of this Pool, waiting for one to become available if
+// all of them are busy. fn receives a context built by cext.Detach(ctx), which keeps
+// all values of ctx but never gets cancelled, since the submitter may well be gone by
+// the time fn runs.
+//
+// Submit returns ErrPoolClosed if Close was called, including while it was waiting, or
+// ctx.Err() if ctx gets cancelled before fn could be scheduled, in which case fn never
+// runs.
+func (p *Pool) Submit(ctx context.Context, fn func(context.Context)) error {
+	// The lock must not be held while waiting, otherwise Close could not go through
+	// and tasks submitting other tasks to this Pool would deadlock with it.
+	select {
+	case p.slots <- struct{}{}:
+	case <-p.closed:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.isClosed {
+		<-p.slots
+		return ErrPoolClosed
+	}
+
+	p.run(ctx, fn)
+
+	return nil
+}
+
+// TrySubmit is similar to Submit but returns false instead of waiting if all
+// goroutines of this Pool are busy or if it was closed.
+func (p *Pool) TrySubmit(ctx context.Context, fn func(context.Context)) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.isClosed {
+		return false
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		return false
+	}
+
+	p.run(ctx, fn)
+
+	return true
+}
+
+// Close stops this Pool from accepting new tasks and waits for running ones to
+// complete. Pending and subsequent calls to Submit return ErrPoolClosed.
+func (p *Pool) Close() {
+	p.mu.Lock()
+	if !p.isClosed {
+		p.isClosed = true
+		close(p.closed)
+	}
+	p.mu.Unlock()
+
+	p.running.Wait()
+}
+
+// Size returns the maximum number of tasks this Pool runs concurrently.
+func (p *Pool) Size() int {
+	return cap(p.slots)
+}
+
+// Running returns the number of tasks this Pool is currently running.
+func (p *Pool) Running() int {
+	return len(p.slots)
+}
+
+// run runs fn on a new goroutine that releases its slot once done. The caller must
+// have acquired this slot and hold p.mu.
+func (p *Pool) run(ctx context.Context, fn func(context.Context)) {
+	detached := cext.Detach(ctx)
+
+	p.running.Add(1)
+	go func() {
+		defer func() {
+			<-p.slots
+			p.running.Done()
+		}()
+
+		defer p.recover()
+
+		fn(detached)
+	}()
+}
+
+func (p *Pool) recover() {
+	r := recover()
+	if r != nil && p.panicHandler != nil {
+		p.panicHandler(r)
+	}
+}
@@ -0,0 +1,194 @@
+package ctxpool
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type poolTestKey struct{}
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, 3, New(3).Size())
+	assert.Equal(t, runtime.GOMAXPROCS(0), New(0).Size())
+}
+
+func TestPool_Submit(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "values are kept but cancellation is not",
+			test: func(t *testing.T) {
+				p := New(1)
+				defer p.Close()
+
+				ctx, cancel := context.WithCancel(context.WithValue(context.Background(), poolTestKey{}, "value"))
+
+				done := make(chan context.Context)
+				assert.Nil(t, p.Submit(ctx, func(ctx context.Context) {
+					cancel()
+					done <- ctx
+				}))
+
+				taskCtx := <-done
+				assert.Equal(t, "value", taskCtx.Value(poolTestKey{}))
+				assert.Nil(t, taskCtx.Err())
+			},
+		},
+		{
+			desc: "concurrency is bounded",
+			test: func(t *testing.T) {
+				p := New(2)
+
+				var running, maxRunning int32
+				for i := 0; i < 10; i++ {
+					assert.Nil(t, p.Submit(context.Background(), func(context.Context) {
+						current := atomic.AddInt32(&running, 1)
+						defer atomic.AddInt32(&running, -1)
+
+						for {
+							observed := atomic.LoadInt32(&maxRunning)
+							if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+								break
+							}
+						}
+
+						time.Sleep(time.Millisecond)
+					}))
+				}
+
+				p.Close()
+
+				assert.Equal(t, int32(2), maxRunning)
+				assert.Equal(t, 0, p.Running())
+			},
+		},
+		{
+			desc: "cancelled while waiting",
+			test: func(t *testing.T) {
+				p := New(1)
+				defer p.Close()
+
+				release := make(chan struct{})
+				defer close(release)
+
+				assert.Nil(t, p.Submit(context.Background(), func(context.Context) {
+					<-release
+				}))
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+				defer cancel()
+
+				err := p.Submit(ctx, func(context.Context) {
+					assert.Fail(t, "task must not run")
+				})
+				assert.Equal(t, context.DeadlineExceeded, err)
+			},
+		},
+		{
+			desc: "closed pool",
+			test: func(t *testing.T) {
+				p := New(1)
+				p.Close()
+
+				assert.Equal(t, ErrPoolClosed, p.Submit(context.Background(), func(context.Context) {}))
+				assert.False(t, p.TrySubmit(context.Background(), func(context.Context) {}))
+			},
+		},
+		{
+			desc: "closed while waiting",
+			test: func(t *testing.T) {
+				p := New(1)
+
+				release := make(chan struct{})
+				assert.Nil(t, p.Submit(context.Background(), func(context.Context) {
+					<-release
+				}))
+
+				submitted := make(chan error)
+				go func() {
+					submitted <- p.Submit(context.Background(), func(context.Context) {
+						assert.Fail(t, "task must not run")
+					})
+				}()
+
+				go p.Close()
+
+				assert.Equal(t, ErrPoolClosed, <-submitted)
+
+				close(release)
+			},
+		},
+		{
+			desc: "nested submit while closing",
+			test: func(t *testing.T) {
+				p := New(1)
+
+				started := make(chan struct{})
+				nested := make(chan error, 1)
+				assert.Nil(t, p.Submit(context.Background(), func(ctx context.Context) {
+					close(started)
+					nested <- p.Submit(ctx, func(context.Context) {
+						assert.Fail(t, "task must not run")
+					})
+				}))
+
+				<-started
+				p.Close()
+
+				assert.Equal(t, ErrPoolClosed, <-nested)
+			},
+		},
+		{
+			desc: "panics are recovered",
+			test: func(t *testing.T) {
+				var recovered interface{}
+				p := New(1, WithPanicHandler(func(r interface{}) {
+					recovered = r
+				}))
+
+				assert.Nil(t, p.Submit(context.Background(), func(context.Context) {
+					panic("boom")
+				}))
+
+				p.Close()
+
+				assert.Equal(t, "boom", recovered)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestPool_TrySubmit(t *testing.T) {
+	p := New(1)
+	defer p.Close()
+
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	assert.True(t, p.TrySubmit(context.Background(), func(context.Context) {
+		defer wg.Done()
+		<-release
+	}))
+	assert.False(t, p.TrySubmit(context.Background(), func(context.Context) {}))
+	assert.Equal(t, 1, p.Running())
+
+	close(release)
+	wg.Wait()
+}
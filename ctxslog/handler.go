@@ -0,0 +1,210 @@
+//go:build go1.21
+
+// Package ctxslog provides a log/slog integration that enriches log records with the
+// state carried by their contexts, such as overwritten variables and memoized
+// executions, so a single log line shows what a request was working with.
+package ctxslog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/jamestrandung/go-context/helper"
+	"github.com/jamestrandung/go-context/memoize"
+)
+
+const (
+	// DvowGroup is the name of the group holding the overwritten variables of a context.
+	DvowGroup = "dvow"
+	// MemoizeGroup is the name of the group holding the state of the memoize cache of a
+	// context.
+	MemoizeGroup = "memoize"
+	// BreadcrumbsGroup is the name of the group holding the breadcrumb trails of a
+	// context, keyed by domain.
+	BreadcrumbsGroup = "breadcrumbs"
+	// DefaultBreadcrumbDomain is the key of the breadcrumb trail of the default domain.
+	DefaultBreadcrumbDomain = "default"
+	// MaskedValue replaces the values of overwritten variables unless they are revealed.
+	MaskedValue = "***"
+
+	defaultMaxValueLen = 64
+)
+
+// Options configures what gets added to log records.
+type Options struct {
+	// RevealValues makes overwritten variables be logged with their values instead of
+	// MaskedValue. Values are rendered using helper.Stringify, hence those implementing
+	// helper.Redactor stay redacted.
+	RevealValues bool
+	// MaxValueLen is the maximum length of revealed values, 64 if not positive.
+	MaxValueLen int
+	// SkipDvow disables logging overwritten variables.
+	SkipDvow bool
+	// SkipMemoize disables logging the state of the memoize cache.
+	SkipMemoize bool
+	// BreadcrumbDomains are the domains whose breadcrumb trails get logged, in
+	// addition to the default domain.
+	BreadcrumbDomains []cext.BreadcrumbDomain
+	// SkipBreadcrumbs disables logging breadcrumb trails.
+	SkipBreadcrumbs bool
+}
+
+// Handler is a slog.Handler that adds the Attrs of the context of every record before
+// passing it to the next slog.Handler.
+type Handler struct {
+	next slog.Handler
+	opts Options
+}
+
+// NewHandler returns a Handler wrapping the given slog.Handler.
+func NewHandler(next slog.Handler, opts Options) *Handler {
+	return &Handler{
+		next: next,
+		opts: opts,
+	}
+}
+
+// Enabled ...
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle ...
+func (h *Handler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := Attrs(ctx, h.opts); len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs ...
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return NewHandler(h.next.WithAttrs(attrs), h.opts)
+}
+
+// WithGroup returns a Handler whose records are passed to the next slog.Handler under
+// the given group.
+//
+// Note: like any other attribute of these records, the Attrs of their contexts are
+// nested under this group too.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	return NewHandler(h.next.WithGroup(name), h.opts)
+}
+
+// Attrs returns the state carried by ctx as a list of slog.Attr, e.g. for loggers that
+// do not go through a Handler:
+//
+//	logger.LogAttrs(ctx, slog.LevelInfo, "done", ctxslog.Attrs(ctx, ctxslog.Options{})...)
+//
+// Subsystems that hold no state in ctx are omitted.
+func Attrs(ctx context.Context, opts Options) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+
+	var attrs []slog.Attr
+
+	if !opts.SkipDvow {
+		if attr, ok := dvowAttr(ctx, opts); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if !opts.SkipMemoize {
+		if attr, ok := memoizeAttr(ctx); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	if !opts.SkipBreadcrumbs {
+		if attr, ok := breadcrumbsAttr(ctx, opts); ok {
+			attrs = append(attrs, attr)
+		}
+	}
+
+	return attrs
+}
+
+func dvowAttr(ctx context.Context, opts Options) (slog.Attr, bool) {
+	storage := dvow.ExtractOverwritingStorageV2(ctx)
+	if storage == nil {
+		return slog.Attr{}, false
+	}
+
+	maxValueLen := opts.MaxValueLen
+	if maxValueLen <= 0 {
+		maxValueLen = defaultMaxValueLen
+	}
+
+	// Keys are already sorted, and values are only read when they get revealed
+	// so that masked records do not pay for copying them.
+	names := storage.Keys()
+
+	args := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		rendered := MaskedValue
+		if opts.RevealValues {
+			value := storage.Get(name)
+			if value == nil {
+				continue
+			}
+
+			rendered = helper.Stringify(value.AsIs(), maxValueLen)
+		}
+
+		args = append(args, slog.String(name, rendered))
+	}
+
+	if len(args) == 0 {
+		return slog.Attr{}, false
+	}
+
+	return slog.Group(DvowGroup, args...), true
+}
+
+func memoizeAttr(ctx context.Context) (slog.Attr, bool) {
+	pending, completed := memoize.CountPromises(ctx)
+	if pending == 0 && completed == 0 {
+		return slog.Attr{}, false
+	}
+
+	return slog.Group(
+		MemoizeGroup,
+		slog.Int("pending", pending),
+		slog.Int("completed", completed),
+	), true
+}
+
+func breadcrumbsAttr(ctx context.Context, opts Options) (slog.Attr, bool) {
+	domains := append([]cext.BreadcrumbDomain{""}, opts.BreadcrumbDomains...)
+
+	var args []interface{}
+	for _, domain := range domains {
+		trail := cext.Breadcrumbs(ctx, domain)
+		if len(trail) == 0 {
+			continue
+		}
+
+		rendered := make([]string, 0, len(trail))
+		for _, id := range trail {
+			rendered = append(rendered, helper.Stringify(id, defaultMaxValueLen))
+		}
+
+		key := string(domain)
+		if domain == "" {
+			key = DefaultBreadcrumbDomain
+		}
+
+		args = append(args, slog.Any(key, rendered))
+	}
+
+	if len(args) == 0 {
+		return slog.Attr{}, false
+	}
+
+	return slog.Group(BreadcrumbsGroup, args...), true
+}
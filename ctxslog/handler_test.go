@@ -0,0 +1,151 @@
+//go:build go1.21
+
+package ctxslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/stretchr/testify/assert"
+)
+
+type handlerTestSecret string
+
+func (s handlerTestSecret) Redact() string {
+	return "<redacted>"
+}
+
+func TestHandler(t *testing.T) {
+	log := func(ctx context.Context, opts Options) map[string]interface{} {
+		var buf bytes.Buffer
+
+		logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), opts)).With("service", "test")
+		logger.InfoContext(ctx, "message")
+
+		var record map[string]interface{}
+		assert.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+
+		return record
+	}
+
+	ctx := dvow.WithOverwrittenVariables(
+		context.Background(), map[string]interface{}{
+			"flag":   true,
+			"token":  handlerTestSecret("secret"),
+			"config": map[string]interface{}{"timeout": "5s"},
+		},
+	)
+
+	ctx, destroyFn := memoize.WithCache(ctx)
+	defer destroyFn()
+
+	memoize.Execute(ctx, "key", func(context.Context) (int, error) {
+		return 1, nil
+	})
+
+	ctx, _ = cext.WithAcyclicBreadcrumb(ctx, "quote")
+	ctx, _ = cext.WithAcyclicBreadcrumb(ctx, 42)
+	ctx, _ = cext.WithAcyclicBreadcrumbInDomain(ctx, "pricing", "surge")
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "empty context",
+			test: func(t *testing.T) {
+				record := log(context.Background(), Options{})
+				assert.Equal(t, "test", record["service"])
+				assert.NotContains(t, record, DvowGroup)
+				assert.NotContains(t, record, MemoizeGroup)
+				assert.NotContains(t, record, BreadcrumbsGroup)
+			},
+		},
+		{
+			desc: "masked values",
+			test: func(t *testing.T) {
+				record := log(ctx, Options{})
+				assert.Equal(t, map[string]interface{}{
+					"config": MaskedValue,
+					"flag":   MaskedValue,
+					"token":  MaskedValue,
+				}, record[DvowGroup])
+				assert.Equal(t, map[string]interface{}{
+					"pending":   float64(0),
+					"completed": float64(1),
+				}, record[MemoizeGroup])
+				assert.Equal(t, map[string]interface{}{
+					DefaultBreadcrumbDomain: []interface{}{`"quote"`, "42"},
+				}, record[BreadcrumbsGroup])
+			},
+		},
+		{
+			desc: "revealed values",
+			test: func(t *testing.T) {
+				record := log(ctx, Options{RevealValues: true, MaxValueLen: 10})
+				assert.Equal(t, map[string]interface{}{
+					"config": `map["ti...`,
+					"flag":   "true",
+					"token":  "<redacted>",
+				}, record[DvowGroup])
+			},
+		},
+		{
+			desc: "breadcrumb domains",
+			test: func(t *testing.T) {
+				record := log(ctx, Options{BreadcrumbDomains: []cext.BreadcrumbDomain{"pricing", "missing"}})
+				assert.Equal(t, map[string]interface{}{
+					DefaultBreadcrumbDomain: []interface{}{`"quote"`, "42"},
+					"pricing":               []interface{}{`"surge"`},
+				}, record[BreadcrumbsGroup])
+			},
+		},
+		{
+			desc: "grouped logger",
+			test: func(t *testing.T) {
+				var buf bytes.Buffer
+
+				logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, nil), Options{})).WithGroup("request")
+				logger.InfoContext(ctx, "message")
+
+				var record map[string]interface{}
+				assert.Nil(t, json.Unmarshal(buf.Bytes(), &record))
+
+				assert.NotContains(t, record, DvowGroup)
+				assert.Contains(t, record["request"], DvowGroup)
+			},
+		},
+		{
+			desc: "masked variables",
+			test: func(t *testing.T) {
+				record := log(dvow.WithoutOverwrittenVariables(ctx, "flag"), Options{RevealValues: true})
+				assert.Equal(t, map[string]interface{}{
+					"config": `map["timeout":"5s"]`,
+					"token":  "<redacted>",
+				}, record[DvowGroup])
+			},
+		},
+		{
+			desc: "skipped subsystems",
+			test: func(t *testing.T) {
+				record := log(ctx, Options{SkipDvow: true, SkipMemoize: true, SkipBreadcrumbs: true})
+				assert.NotContains(t, record, DvowGroup)
+				assert.NotContains(t, record, MemoizeGroup)
+				assert.NotContains(t, record, BreadcrumbsGroup)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package ctxtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// AssertValue asserts that ctx holds the given value under this key.
+func AssertValue(t testing.TB, ctx context.Context, key interface{}, want interface{}, msgAndArgs ...interface{}) bool {
+	t.Helper()
+
+	return assert.Equal(t, want, ctx.Value(key), msgAndArgs...)
+}
+
+// AssertNoValue asserts that ctx does not hold any value under this key.
+func AssertNoValue(t testing.TB, ctx context.Context, key interface{}, msgAndArgs ...interface{}) bool {
+	t.Helper()
+
+	return assert.Nil(t, ctx.Value(key), msgAndArgs...)
+}
+
+// AssertCancelledWithin asserts that ctx gets cancelled within the given duration.
+func AssertCancelledWithin(t testing.TB, ctx context.Context, d time.Duration, msgAndArgs ...interface{}) bool {
+	t.Helper()
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return assert.Error(t, ctx.Err(), msgAndArgs...)
+	case <-timer.C:
+		return assert.Fail(t, "context was not cancelled within "+d.String(), msgAndArgs...)
+	}
+}
+
+// AssertNotCancelled asserts that ctx has not been cancelled.
+func AssertNotCancelled(t testing.TB, ctx context.Context, msgAndArgs ...interface{}) bool {
+	t.Helper()
+
+	select {
+	case <-ctx.Done():
+		return assert.Fail(t, "context was cancelled: "+ctx.Err().Error(), msgAndArgs...)
+	default:
+		return assert.Nil(t, ctx.Err(), msgAndArgs...)
+	}
+}
+
+// AssertNoDeadline asserts that ctx does not have a deadline.
+func AssertNoDeadline(t testing.TB, ctx context.Context, msgAndArgs ...interface{}) bool {
+	t.Helper()
+
+	deadline, ok := ctx.Deadline()
+	if ok {
+		return assert.Fail(t, "context has a deadline at "+deadline.String(), msgAndArgs...)
+	}
+
+	return true
+}
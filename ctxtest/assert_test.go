@@ -0,0 +1,83 @@
+package ctxtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type assertTestKey struct{}
+
+// recordingT records failures instead of failing the test running the assertions.
+type recordingT struct {
+	testing.TB
+	failed bool
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failed = true
+}
+
+func TestAssertions(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "AssertValue",
+			test: func(t *testing.T) {
+				ctx := context.WithValue(context.Background(), assertTestKey{}, "value")
+
+				assert.True(t, AssertValue(&recordingT{TB: t}, ctx, assertTestKey{}, "value"))
+				assert.False(t, AssertValue(&recordingT{TB: t}, ctx, assertTestKey{}, "other"))
+				assert.True(t, AssertNoValue(&recordingT{TB: t}, context.Background(), assertTestKey{}))
+				assert.False(t, AssertNoValue(&recordingT{TB: t}, ctx, assertTestKey{}))
+			},
+		},
+		{
+			desc: "AssertCancelledWithin",
+			test: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+				defer cancel()
+
+				assert.True(t, AssertCancelledWithin(&recordingT{TB: t}, ctx, time.Second))
+
+				rt := &recordingT{TB: t}
+				assert.False(t, AssertCancelledWithin(rt, context.Background(), 10*time.Millisecond))
+				assert.True(t, rt.failed)
+			},
+		},
+		{
+			desc: "AssertNotCancelled",
+			test: func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+
+				assert.True(t, AssertNotCancelled(&recordingT{TB: t}, ctx))
+
+				cancel()
+				assert.False(t, AssertNotCancelled(&recordingT{TB: t}, ctx))
+			},
+		},
+		{
+			desc: "AssertNoDeadline",
+			test: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+				defer cancel()
+
+				assert.True(t, AssertNoDeadline(&recordingT{TB: t}, context.Background()))
+				assert.False(t, AssertNoDeadline(&recordingT{TB: t}, ctx))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
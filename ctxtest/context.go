@@ -0,0 +1,94 @@
+// Package ctxtest provides helpers for tests, i.e. a Context whose cancellation and
+// deadline are controlled by the test, and assertions on the values and cancellation
+// of contexts.
+package ctxtest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Context is a context.Context whose cancellation and deadline are controlled by
+// the test using it.
+type Context struct {
+	parent context.Context
+	done   chan struct{}
+
+	mu       sync.Mutex
+	err      error
+	deadline time.Time
+}
+
+// New returns a Context that inherits the values of the given parent but none of its
+// cancellation. It is cancelled automatically when the test completes so that code
+// waiting on it does not leak goroutines.
+func New(t testing.TB, parent context.Context) *Context {
+	if parent == nil {
+		parent = context.Background()
+	}
+
+	c := &Context{
+		parent: parent,
+		done:   make(chan struct{}),
+	}
+
+	t.Cleanup(c.Cancel)
+
+	return c
+}
+
+// Cancel cancels this Context with context.Canceled.
+func (c *Context) Cancel() {
+	c.CancelWithError(context.Canceled)
+}
+
+// CancelWithError cancels this Context with the given error, e.g.
+// context.DeadlineExceeded to simulate a timeout. Only the first call has an effect.
+func (c *Context) CancelWithError(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+
+	c.err = err
+	close(c.done)
+}
+
+// SetDeadline makes Deadline report the given time. It does not cancel this Context
+// when the deadline passes, use CancelWithError for that.
+func (c *Context) SetDeadline(deadline time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.deadline = deadline
+}
+
+// Deadline ...
+func (c *Context) Deadline() (time.Time, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.deadline, !c.deadline.IsZero()
+}
+
+// Done ...
+func (c *Context) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err ...
+func (c *Context) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Value ...
+func (c *Context) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
@@ -0,0 +1,76 @@
+package ctxtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContext(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "inherits values but not cancellation",
+			test: func(t *testing.T) {
+				parent, cancel := context.WithCancel(context.WithValue(context.Background(), assertTestKey{}, "value"))
+				cancel()
+
+				ctx := New(t, parent)
+				AssertValue(t, ctx, assertTestKey{}, "value")
+				AssertNotCancelled(t, ctx)
+				AssertNoDeadline(t, ctx)
+			},
+		},
+		{
+			desc: "cancel",
+			test: func(t *testing.T) {
+				ctx := New(t, nil)
+
+				go func() {
+					time.Sleep(10 * time.Millisecond)
+					ctx.CancelWithError(context.DeadlineExceeded)
+					ctx.Cancel()
+				}()
+
+				AssertCancelledWithin(t, ctx, time.Second)
+				assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+			},
+		},
+		{
+			desc: "deadline",
+			test: func(t *testing.T) {
+				ctx := New(t, nil)
+
+				deadline := time.Now().Add(time.Hour)
+				ctx.SetDeadline(deadline)
+
+				actual, ok := ctx.Deadline()
+				assert.True(t, ok)
+				assert.Equal(t, deadline, actual)
+				AssertNotCancelled(t, ctx)
+			},
+		},
+		{
+			desc: "cancelled on cleanup",
+			test: func(t *testing.T) {
+				var ctx *Context
+				t.Run("inner", func(t *testing.T) {
+					ctx = New(t, nil)
+				})
+
+				assert.Equal(t, context.Canceled, ctx.Err())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package dvow
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+type accessLogKey struct{}
+
+// Access records one lookup of an overwritten variable.
+type Access struct {
+	// Name is the name of the variable that was looked up.
+	Name string
+	// Overwritten indicates whether the variable was overwritten at the time of lookup.
+	Overwritten bool
+	// Caller is the file:line of the code that looked up the variable. It is empty
+	// unless RecordCallers was given to WithAccessLog.
+	Caller string
+}
+
+// AccessLogOption configures how accesses are recorded.
+type AccessLogOption func(*accessLog)
+
+// RecordCallers makes the access log also record the file:line of the code that looked
+// up each variable. This requires capturing a stack trace on every lookup, hence it is
+// not enabled by default.
+func RecordCallers() AccessLogOption {
+	return func(l *accessLog) {
+		l.recordCallers = true
+	}
+}
+
+type accessLog struct {
+	mu            sync.Mutex
+	accesses      []Access
+	recordCallers bool
+}
+
+// WithAccessLog returns a new context.Context in which all lookups of overwritten
+// variables via GetOverwrittenValue and the functions built on top of it are recorded.
+// The recorded accesses can be retrieved via AccessLog. This lets experimenters verify
+// whether an overwrite actually influenced the code path of a request.
+func WithAccessLog(ctx context.Context, opts ...AccessLogOption) context.Context {
+	l := &accessLog{}
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return context.WithValue(ctx, accessLogKey{}, l)
+}
+
+// AccessLog returns all accesses recorded so far in the given context in the order
+// they happened, or nil if the context was not initialized using WithAccessLog.
+func AccessLog(ctx context.Context) []Access {
+	l, ok := ctx.Value(accessLogKey{}).(*accessLog)
+	if !ok {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make([]Access, len(l.accesses))
+	copy(result, l.accesses)
+
+	return result
+}
+
+// recordAccess adds an Access to the access log of the given context, if any.
+func recordAccess(ctx context.Context, name string, overwritten bool) {
+	l, ok := ctx.Value(accessLogKey{}).(*accessLog)
+	if !ok {
+		return
+	}
+
+	access := Access{
+		Name:        name,
+		Overwritten: overwritten,
+	}
+
+	if l.recordCallers {
+		access.Caller = findCaller()
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.accesses = append(l.accesses, access)
+}
+
+// packageDir is the directory containing the source files of this package.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// findCaller returns the file:line of the first caller outside of this package.
+func findCaller() string {
+	pcs := make([]uintptr, 16)
+	n := runtime.Callers(3, pcs)
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package dvow
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccessLog(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "context without access log",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+				assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+				assert.Nil(t, AccessLog(ctx))
+			},
+		},
+		{
+			desc: "accesses are recorded",
+			test: func(t *testing.T) {
+				ctx := WithAccessLog(context.Background())
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"a": 1})
+
+				GetOverwrittenValue(ctx, "a")
+				GetAs[int](ctx, "b")
+
+				expected := []Access{
+					{Name: "a", Overwritten: true},
+					{Name: "b", Overwritten: false},
+				}
+
+				assert.Equal(t, expected, AccessLog(ctx))
+			},
+		},
+		{
+			desc: "callers are recorded",
+			test: func(t *testing.T) {
+				ctx := WithAccessLog(context.Background(), RecordCallers())
+
+				GetOrDefault(ctx, "a", 1)
+
+				accesses := AccessLog(ctx)
+				assert.Equal(t, 1, len(accesses))
+				assert.True(t, strings.Contains(accesses[0].Caller, "access_log_test.go:"), accesses[0].Caller)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
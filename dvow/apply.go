@@ -0,0 +1,117 @@
+package dvow
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/jamestrandung/go-context/helper"
+	"github.com/pkg/errors"
+)
+
+// ApplyTagName is the struct tag used by ApplyOverwrites to map fields to variables.
+const ApplyTagName = "dvow"
+
+// ApplyOverwrites scans the fields of the struct pointed to by target and replaces the
+// value of every field tagged `dvow:"name"` with the overwritten value of the variable
+// under that name in the given context, if any. Fields whose variable is not overwritten
+// keep their current values, so config structs can be overridden in one call.
+//
+//	type PricingConfig struct {
+//		SurgeMultiplier float64       `dvow:"surge_multiplier"`
+//		Timeout         time.Duration `dvow:"pricing.timeout"`
+//	}
+//
+// Values are converted to the field types using the same rules as UnmarshalInto, and
+// strings are also parsed into numbers and booleans if the overwrites were installed
+// with LenientConversion. Untagged fields of struct types, including embedded structs,
+// are scanned recursively.
+//
+// All fields that can be converted are applied. If some cannot, the returned error
+// wraps ErrIncompatibleField and describes the first of them.
+func ApplyOverwrites(ctx context.Context, target interface{}) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrPointerArgumentRequired
+	}
+
+	return applyOverwrites(ctx, rv.Elem())
+}
+
+func applyOverwrites(ctx context.Context, rv reflect.Value) error {
+	var firstErr error
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rv.Field(i)
+
+		name, tagged := rt.Field(i).Tag.Lookup(ApplyTagName)
+		if !tagged {
+			// Exported fields of embedded structs are settable even if the
+			// embedded struct itself is unexported
+			if err := applyOverwritesToNested(ctx, field); err != nil && firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		if name == "" || name == "-" || !field.CanSet() {
+			continue
+		}
+
+		value := Ops.GetOverwrittenValue(ctx, name)
+		if value == nil {
+			continue
+		}
+
+		if err := assignValue(field, value); err != nil && firstErr == nil {
+			firstErr = errors.Wrap(
+				ErrIncompatibleField,
+				fmt.Sprintf("variable %s, field %s.%s: %v", name, rt.Name(), rt.Field(i).Name, err),
+			)
+		}
+	}
+
+	return firstErr
+}
+
+// applyOverwritesToNested scans the given untagged field if it is a struct or a non-nil
+// pointer to a struct.
+func applyOverwritesToNested(ctx context.Context, field reflect.Value) error {
+	switch {
+	case field.Kind() == reflect.Struct:
+		return applyOverwrites(ctx, field)
+	case field.Kind() == reflect.Pointer && !field.IsNil() && field.Elem().Kind() == reflect.Struct:
+		return applyOverwrites(ctx, field.Elem())
+	default:
+		return nil
+	}
+}
+
+// assignValue converts the raw value wrapped inside v to the type of the given field
+// and assigns it. Variables overwritten to nil, including typed nils such as nil maps,
+// reset the field to its zero value.
+func assignValue(field reflect.Value, v Value) error {
+	raw := v.AsIs()
+	if helper.IsNil(raw) {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	if rawValue := reflect.ValueOf(raw); rawValue.Type().AssignableTo(field.Type()) {
+		field.Set(rawValue)
+		return nil
+	}
+
+	ov, _ := v.(overwriteValue)
+
+	result := reflect.New(field.Type())
+	if err := decode(raw, result.Interface(), decodeOptions{weaklyTyped: ov.lenient}); err != nil {
+		return err
+	}
+
+	field.Set(result.Elem())
+
+	return nil
+}
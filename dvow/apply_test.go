@@ -0,0 +1,161 @@
+package dvow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type applyTestLimits struct {
+	MaxItems int `dvow:"limits.max_items"`
+}
+
+type applyTestConfig struct {
+	applyTestLimits
+	Multiplier float64           `dvow:"multiplier"`
+	Timeout    time.Duration     `dvow:"timeout"`
+	Enabled    bool              `dvow:"enabled"`
+	Name       string            `dvow:"name"`
+	Tags       []string          `dvow:"tags"`
+	Labels     map[string]string `dvow:"labels"`
+	Ignored    string            `dvow:"-"`
+	Untagged   string
+	Nested     *applyTestLimits
+	unexported string `dvow:"unexported"`
+}
+
+func TestApplyOverwrites(t *testing.T) {
+	newConfig := func() applyTestConfig {
+		return applyTestConfig{
+			applyTestLimits: applyTestLimits{MaxItems: 1},
+			Multiplier:      1,
+			Timeout:         time.Second,
+			Name:            "default",
+			Ignored:         "default",
+			Untagged:        "default",
+			Nested:          &applyTestLimits{MaxItems: 1},
+			unexported:      "default",
+		}
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "invalid target",
+			test: func(t *testing.T) {
+				cfg := newConfig()
+
+				assert.Equal(t, ErrPointerArgumentRequired, ApplyOverwrites(context.Background(), cfg))
+				assert.Equal(t, ErrPointerArgumentRequired, ApplyOverwrites(context.Background(), (*applyTestConfig)(nil)))
+				assert.Equal(t, ErrPointerArgumentRequired, ApplyOverwrites(context.Background(), new(int)))
+			},
+		},
+		{
+			desc: "no overwrites",
+			test: func(t *testing.T) {
+				cfg := newConfig()
+
+				assert.Nil(t, ApplyOverwrites(context.Background(), &cfg))
+				assert.Equal(t, newConfig(), cfg)
+			},
+		},
+		{
+			desc: "overwrites with type conversion",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"limits.max_items": json.Number("10"),
+						"multiplier":       2,
+						"timeout":          "5s",
+						"enabled":          true,
+						"name":             nil,
+						"tags":             []interface{}{"a", "b"},
+						"labels":           map[string]interface{}{"k": "v"},
+						"-":                "overwritten",
+						"Untagged":         "overwritten",
+						"unexported":       "overwritten",
+					},
+				)
+
+				cfg := newConfig()
+				assert.Nil(t, ApplyOverwrites(ctx, &cfg))
+
+				expected := newConfig()
+				expected.MaxItems = 10
+				expected.Nested.MaxItems = 10
+				expected.Multiplier = 2
+				expected.Timeout = 5 * time.Second
+				expected.Enabled = true
+				expected.Name = ""
+				expected.Tags = []string{"a", "b"}
+				expected.Labels = map[string]string{"k": "v"}
+
+				assert.Equal(t, expected, cfg)
+			},
+		},
+		{
+			desc: "typed nil resets field",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"labels":           map[string]interface{}(nil),
+						"limits.max_items": (*int)(nil),
+					},
+				)
+
+				cfg := newConfig()
+				cfg.Labels = map[string]string{"k": "v"}
+				assert.Nil(t, ApplyOverwrites(ctx, &cfg))
+				assert.Nil(t, cfg.Labels)
+				assert.Equal(t, 0, cfg.MaxItems)
+			},
+		},
+		{
+			desc: "lenient conversion",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"multiplier": "1.5",
+						"enabled":    "true",
+					}, LenientConversion(),
+				)
+
+				cfg := newConfig()
+				assert.Nil(t, ApplyOverwrites(ctx, &cfg))
+				assert.Equal(t, 1.5, cfg.Multiplier)
+				assert.True(t, cfg.Enabled)
+			},
+		},
+		{
+			desc: "incompatible values",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"multiplier": "1.5",
+						"name":       "overwritten",
+					},
+				)
+
+				cfg := newConfig()
+
+				err := ApplyOverwrites(ctx, &cfg)
+				assert.ErrorIs(t, err, ErrIncompatibleField)
+				assert.Contains(t, err.Error(), "applyTestConfig.Multiplier")
+				assert.Equal(t, 1.0, cfg.Multiplier)
+				assert.Equal(t, "overwritten", cfg.Name, "compatible fields must still be applied")
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package dvow
+
+import (
+	"context"
+)
+
+// WithOverwrittenVariable returns a new context.Context that holds a reference to the
+// given overwritten variable. It is a shorthand for WithOverwrittenVariables with a
+// single-entry map.
+func WithOverwrittenVariable(ctx context.Context, name string, value interface{}, opts ...Option) context.Context {
+	return WithOverwrittenVariables(ctx, map[string]interface{}{name: value}, opts...)
+}
+
+// Builder accumulates overwritten variables to be applied to a context.Context in one
+// go, so that call sites don't have to construct throwaway maps. The zero value is
+// ready to use.
+//
+//	ctx = new(dvow.Builder).
+//		Set("surge_multiplier", 1.5).
+//		SetIf(isVIP, "priority", "high").
+//		Apply(ctx)
+type Builder struct {
+	variables map[string]interface{}
+	opts      []Option
+}
+
+// NewBuilder returns a new Builder whose variables will be applied using the given
+// Option.
+func NewBuilder(opts ...Option) *Builder {
+	return &Builder{
+		opts: opts,
+	}
+}
+
+// Set records the given overwritten variable, replacing any value previously set
+// under the same name.
+func (b *Builder) Set(name string, value interface{}) *Builder {
+	if b.variables == nil {
+		b.variables = make(map[string]interface{})
+	}
+
+	b.variables[name] = value
+
+	return b
+}
+
+// SetIf records the given overwritten variable only if the given condition is true.
+func (b *Builder) SetIf(condition bool, name string, value interface{}) *Builder {
+	if !condition {
+		return b
+	}
+
+	return b.Set(name, value)
+}
+
+// Apply returns a new context.Context that holds a reference to all variables set in
+// this Builder. If no variable was set, the given context is returned as is. The
+// Builder can be reused afterwards since Apply makes a copy of its variables.
+func (b *Builder) Apply(ctx context.Context) context.Context {
+	return WithOverwrittenVariables(ctx, b.variables, b.opts...)
+}
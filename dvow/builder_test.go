@@ -0,0 +1,71 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOverwrittenVariable(t *testing.T) {
+	ctx := WithOverwrittenVariable(context.Background(), "a", 1, WithSource(SourceTest))
+
+	value := GetOverwrittenValue(ctx, "a")
+	assert.Equal(t, 1, value.AsIs())
+	assert.Equal(t, SourceTest, SourceOf(value))
+}
+
+func TestBuilder(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "zero value without variables",
+			test: func(t *testing.T) {
+				ctx := context.Background()
+
+				var b Builder
+				assert.Equal(t, ctx, b.Apply(ctx))
+				assert.Equal(t, ctx, b.SetIf(false, "a", 1).Apply(ctx))
+			},
+		},
+		{
+			desc: "chained calls",
+			test: func(t *testing.T) {
+				ctx := NewBuilder(LenientConversion()).
+					Set("a", "1").
+					Set("b", 1).
+					Set("b", 2).
+					SetIf(true, "c", 3).
+					SetIf(false, "d", 4).
+					Apply(context.Background())
+
+				assert.Equal(t, int64(1), GetOverwrittenValue(ctx, "a").AsInt())
+				assert.Equal(t, 2, GetOverwrittenValue(ctx, "b").AsIs())
+				assert.Equal(t, 3, GetOverwrittenValue(ctx, "c").AsIs())
+				assert.Nil(t, GetOverwrittenValue(ctx, "d"))
+			},
+		},
+		{
+			desc: "reused after apply",
+			test: func(t *testing.T) {
+				b := new(Builder).Set("a", 1)
+				first := b.Apply(context.Background())
+
+				b.Set("a", 2)
+				second := b.Apply(context.Background())
+
+				assert.Equal(t, 1, GetOverwrittenValue(first, "a").AsIs())
+				assert.Equal(t, 2, GetOverwrittenValue(second, "a").AsIs())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
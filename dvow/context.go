@@ -2,6 +2,9 @@ package dvow
 
 import (
     "context"
+    "time"
+
+    "github.com/jamestrandung/go-context/helper"
 )
 
 type contextKey struct{}
@@ -17,43 +20,207 @@ var overwritingStorageKey = contextKey{}
 // pointer or a complex struct containing some pointers or a pointer-like object such as
 // an array or a map, they should NOT update this value since the context is most likely
 // passed into many go-routines running in parallel. As a consequence, clients may run into
-// a race condition if things goes wrong.
-func WithOverwrittenVariables(ctx context.Context, overwrittenVariables map[string]interface{}) context.Context {
+// a race condition if things goes wrong. Use DeepCopyValues to store deep copies instead.
+//
+// The given Option can be used to customize how these variables are validated and exposed.
+func WithOverwrittenVariables(
+    ctx context.Context,
+    overwrittenVariables map[string]interface{},
+    opts ...Option,
+) context.Context {
+    if len(overwrittenVariables) == 0 {
+        return ctx
+    }
+
+    return withOverwrittenVariables(ctx, overwrittenVariables, time.Time{}, opts...)
+}
+
+// WithOverwrittenVariablesTTL works like WithOverwrittenVariables except that the given
+// overwritten variables expire after the given ttl. After that, lookups will behave as
+// if these variables were never overwritten. This prevents long-lived contexts such as
+// those of background workers from accumulating stale overwrites.
+func WithOverwrittenVariablesTTL(
+    ctx context.Context,
+    overwrittenVariables map[string]interface{},
+    ttl time.Duration,
+    opts ...Option,
+) context.Context {
     if len(overwrittenVariables) == 0 {
         return ctx
     }
 
+    return withOverwrittenVariables(ctx, overwrittenVariables, timeNow().Add(ttl), opts...)
+}
+
+func withOverwrittenVariables(
+    ctx context.Context,
+    overwrittenVariables map[string]interface{},
+    expiresAt time.Time,
+    opts ...Option,
+) context.Context {
+    o := newOptions(opts...)
+
+    clone := prepareVariables(overwrittenVariables, o)
+    if len(clone) == 0 {
+        return ctx
+    }
+
+    derivedStorage := newDynamicOverwritingStorage(Ops.ExtractOverwritingStorage(ctx), clone, o, expiresAt)
+
+    return context.WithValue(ctx, overwritingStorageKey, derivedStorage)
+}
+
+// prepareVariables returns a copy of the given overwritten variables after applying
+// the validation and copying behaviors configured in the given options.
+func prepareVariables(overwrittenVariables map[string]interface{}, o options) map[string]interface{} {
     // Make a copy so that our storage wouldn't be affected by changes to the input map
     clone := make(map[string]interface{}, len(overwrittenVariables))
     for name, value := range overwrittenVariables {
         clone[name] = value
     }
 
-    derivedStorage := dynamicOverwritingStorage{
-        parent: Ops.ExtractOverwritingStorage(ctx),
-        variables: clone,
+    clone = o.validate(clone)
+
+    if o.deepCopy {
+        for name, value := range clone {
+            clone[name] = helper.DeepCopy(value)
+        }
+    }
+
+    return clone
+}
+
+// WithoutOverwrittenVariables returns a new context.Context in which the variables under
+// the given names are no longer overwritten, even if a parent Storage defines them. This
+// allows cancelling inherited overwrites for a sub-scope. Masking a name also masks the
+// dot-paths below it, e.g. masking "pricing.surge" hides "pricing.surge.multiplier" even
+// if a parent Storage overwrote "pricing" as a whole.
+func WithoutOverwrittenVariables(ctx context.Context, names ...string) context.Context {
+    if len(names) == 0 {
+        return ctx
+    }
+
+    masked := make(map[string]interface{}, len(names))
+    for _, name := range names {
+        masked[name] = tombstone{}
     }
 
+    derivedStorage := newDynamicOverwritingStorage(Ops.ExtractOverwritingStorage(ctx), masked, options{}, time.Time{})
+
     return context.WithValue(ctx, overwritingStorageKey, derivedStorage)
 }
 
 // ExtractOverwritingStorage returns the Storage currently associated with ctx, or
-// nil if no such Storage could be found.
+// the default Storage set via SetDefaultStorage if no such Storage could be found.
 func ExtractOverwritingStorage(ctx context.Context) Storage {
     val := ctx.Value(overwritingStorageKey)
     if s, ok := val.(Storage); ok {
         return s
     }
 
-    return nil
+    return defaultStorage()
+}
+
+// WithOverwritingStorage returns a new context.Context that holds a reference to the
+// given Storage, replacing the Storage currently associated with ctx, if any. This is
+// useful to attach a Storage obtained from MergeStorages or UnmarshalStorage.
+func WithOverwritingStorage(ctx context.Context, storage Storage) context.Context {
+    if storage == nil {
+        return ctx
+    }
+
+    return context.WithValue(ctx, overwritingStorageKey, storage)
 }
 
-// GetOverwrittenValue returns the Value of the variable under this name if it was overwritten
+// GetOverwrittenValue returns the Value of the variable under this name if it was overwritten.
+// Variables that were explicitly overwritten to nil yield a non-nil Value whose IsNil is
+// true, so that callers can tell "overwritten to null" apart from "not overwritten".
+//
+// The name can also be a dot-path such as "pricing.surge.multiplier" to read an individual
+// leaf of a nested map or struct that was overwritten as a single variable (e.g. "pricing").
+// Variables overwritten under the full name always take precedence.
 func GetOverwrittenValue(ctx context.Context, name string) Value {
     storage := Ops.ExtractOverwritingStorage(ctx)
+
+    value := evaluateExpression(ctx, name, getOverwrittenValue(storage, name))
+    recordAccess(ctx, name, value != nil)
+    reportLookup(storage, name, value != nil)
+
+    return value
+}
+
+func getOverwrittenValue(storage Storage, name string) Value {
+    if storage == nil {
+        return nil
+    }
+
+    if value, isMasked := lookup(storage, name); value != nil || isMasked {
+        return value
+    }
+
+    return getNestedValue(storage, name)
+}
+
+// GetAs returns the overwritten value of the variable under this name converted to T
+// and true if it was overwritten and the conversion succeeded. Otherwise, it returns
+// the zero value of T and false.
+//
+// The conversion first attempts a direct type assertion, then a numeric coercion if
+// both the raw value and T are numbers, and finally falls back to Unmarshal. The numeric
+// coercion fails if the number overflows T or loses its fractional part, e.g. 300 as a
+// uint8 or 1.5 as an int.
+func GetAs[T any](ctx context.Context, name string) (T, bool) {
+    value := Ops.GetOverwrittenValue(ctx, name)
+    if value == nil {
+        var zero T
+        return zero, false
+    }
+
+    return convertValue[T](value)
+}
+
+// GetOrDefault returns the Value of the variable under this name if it was overwritten.
+// Otherwise, it returns a Value wrapping the given fallback whose IsPresent is false.
+//
+// Note: a variable overwritten with a zero value, e.g. 0 or "", is real data and is
+// returned as-is instead of the fallback. Use Value.IsZero to tell it apart.
+func GetOrDefault(ctx context.Context, name string, fallback interface{}) Value {
+    if value := Ops.GetOverwrittenValue(ctx, name); value != nil {
+        return value
+    }
+
+    return overwriteValue{
+        value:      fallback,
+        isFallback: true,
+    }
+}
+
+// GetOrDefaultAs returns the overwritten value of the variable under this name converted
+// to T if it was overwritten and the conversion succeeded. Otherwise, it returns the given
+// fallback.
+func GetOrDefaultAs[T any](ctx context.Context, name string, fallback T) T {
+    if result, ok := GetAs[T](ctx, name); ok {
+        return result
+    }
+
+    return fallback
+}
+
+// Snapshot returns a deep copy of all variables that are effectively overwritten in
+// the given context, flattened from the whole Storage chain into one map. This map
+// can be echoed back in debug responses or forwarded to downstream systems.
+func Snapshot(ctx context.Context) map[string]interface{} {
+    storage := ExtractOverwritingStorageV2(ctx)
     if storage == nil {
         return nil
     }
 
-    return storage.Get(name)
+    variables := storage.Snapshot()
+
+    snapshot := make(map[string]interface{}, len(variables))
+    for name, value := range variables {
+        snapshot[name] = helper.DeepCopy(value)
+    }
+
+    return snapshot
 }
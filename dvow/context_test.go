@@ -5,6 +5,7 @@ import (
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
     "testing"
+    "time"
 )
 
 func TestWithOverwrittenVariables(t *testing.T) {
@@ -54,6 +55,166 @@ func TestWithOverwrittenVariables(t *testing.T) {
                 assert.NotEqual(t, expectedStorage, actual.Value(overwritingStorageKey), "changes to the input map must not affect our Storage")
             },
         },
+        {
+            desc: "lenient conversion",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("ExtractOverwritingStorage", ctx).Return(nil).Once()
+
+                actual := WithOverwrittenVariables(ctx, map[string]interface{}{"test": "123"}, LenientConversion())
+
+                storage := actual.Value(overwritingStorageKey).(Storage)
+                assert.Equal(t, int64(123), storage.Get("test").AsInt())
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "deep copy values",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("ExtractOverwritingStorage", ctx).Return(nil).Twice()
+
+                nested := map[string]interface{}{"key": []int{1}}
+                overwrittenVariables := map[string]interface{}{"test": nested}
+
+                shallow := WithOverwrittenVariables(ctx, overwrittenVariables)
+                deep := WithOverwrittenVariables(ctx, overwrittenVariables, DeepCopyValues())
+
+                nested["key"].([]int)[0] = 2
+                nested["new_key"] = "random"
+
+                assert.Equal(t, nested, shallow.Value(overwritingStorageKey).(Storage).Get("test").AsIs())
+                assert.Equal(t, map[string]interface{}{"key": []int{1}}, deep.Value(overwritingStorageKey).(Storage).Get("test").AsIs())
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+    }
+
+    for _, scenario := range scenarios {
+        sc := scenario
+        t.Run(sc.desc, func(t *testing.T) {
+            sc.test(t)
+        })
+    }
+}
+
+func TestWithOverwrittenVariablesTTL(t *testing.T) {
+    opsMock, cleanup := MockOps()
+    defer cleanup()
+
+    now := time.Now()
+
+    oldTimeNow := timeNow
+    defer func() {
+        timeNow = oldTimeNow
+    }()
+
+    scenarios := []struct {
+        desc string
+        test func(t *testing.T)
+    }{
+        {
+            desc: "input map is empty/nil",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                actual := WithOverwrittenVariablesTTL(ctx, nil, time.Minute)
+
+                assert.Equal(t, ctx, actual)
+            },
+        },
+        {
+            desc: "variables expire after ttl",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                parent := dynamicOverwritingStorage{
+                    variables: map[string]interface{}{
+                        "a": 1,
+                    },
+                }
+
+                opsMock.On("ExtractOverwritingStorage", ctx).Return(parent).Once()
+
+                timeNow = func() time.Time {
+                    return now
+                }
+
+                actual := WithOverwrittenVariablesTTL(ctx, map[string]interface{}{"a": 2, "b": 2}, time.Minute)
+                storage := actual.Value(overwritingStorageKey).(StorageV2)
+
+                timeNow = func() time.Time {
+                    return now.Add(time.Minute - time.Nanosecond)
+                }
+
+                assert.Equal(t, int64(2), storage.Get("a").AsInt())
+                assert.Equal(t, int64(2), storage.Get("b").AsInt())
+                assert.Equal(t, []string{"a", "b"}, storage.Keys())
+
+                timeNow = func() time.Time {
+                    return now.Add(time.Minute)
+                }
+
+                assert.Equal(t, int64(1), storage.Get("a").AsInt())
+                assert.Nil(t, storage.Get("b"))
+                assert.Equal(t, []string{"a"}, storage.Keys())
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+    }
+
+    for _, scenario := range scenarios {
+        sc := scenario
+        t.Run(sc.desc, func(t *testing.T) {
+            sc.test(t)
+        })
+    }
+}
+
+func TestWithoutOverwrittenVariables(t *testing.T) {
+    opsMock, cleanup := MockOps()
+    defer cleanup()
+
+    scenarios := []struct {
+        desc string
+        test func(t *testing.T)
+    }{
+        {
+            desc: "no names",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                actual := WithoutOverwrittenVariables(ctx)
+
+                assert.Equal(t, ctx, actual)
+            },
+        },
+        {
+            desc: "names are masked",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                parent := dynamicOverwritingStorage{
+                    variables: map[string]interface{}{
+                        "a": 1,
+                        "b": 2,
+                    },
+                }
+
+                opsMock.On("ExtractOverwritingStorage", ctx).Return(parent).Once()
+
+                actual := WithoutOverwrittenVariables(ctx, "a", "c")
+
+                storage := actual.Value(overwritingStorageKey).(StorageV2)
+                assert.Nil(t, storage.Get("a"))
+                assert.Nil(t, storage.Get("c"))
+                assert.Equal(t, int64(2), storage.Get("b").AsInt())
+                assert.Equal(t, []string{"b"}, storage.Keys())
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
     }
 
     for _, scenario := range scenarios {
@@ -101,6 +262,24 @@ func TestExtractOverwritingStorage(t *testing.T) {
                 assert.Equal(t, storageMock, actual)
             },
         },
+        {
+            desc: "ctx does not contain a Storage but a default Storage was set",
+            test: func(t *testing.T) {
+                defaultStorage := NewStorage(map[string]interface{}{"a": 1, "b": 1})
+
+                SetDefaultStorage(defaultStorage)
+                defer SetDefaultStorage(nil)
+
+                ctx := context.Background()
+
+                assert.Equal(t, defaultStorage, ExtractOverwritingStorage(ctx))
+
+                ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"b": 2})
+
+                assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+                assert.Equal(t, 2, GetOverwrittenValue(ctx, "b").AsIs())
+            },
+        },
     }
 
     for _, scenario := range scenarios {
@@ -149,6 +328,218 @@ func TestGetOverwrittenValue(t *testing.T) {
                 mock.AssertExpectationsForObjects(t, opsMock, storageMock)
             },
         },
+        {
+            desc: "name is a dot-path into a nested variable",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+                storageMock := &MockStorage{}
+                varName := "pricing.surge"
+
+                opsMock.On("ExtractOverwritingStorage", ctx).Return(storageMock).Once()
+                storageMock.On("Get", varName).Return(nil).Once()
+                storageMock.On("Get", "pricing").Return(overwriteValue{value: map[string]interface{}{"surge": 1.5}}).Once()
+
+                actual := GetOverwrittenValue(ctx, varName)
+
+                assert.Equal(t, overwriteValue{value: 1.5}, actual)
+                mock.AssertExpectationsForObjects(t, opsMock, storageMock)
+            },
+        },
+    }
+
+    for _, scenario := range scenarios {
+        sc := scenario
+        t.Run(sc.desc, func(t *testing.T) {
+            sc.test(t)
+        })
+    }
+}
+func TestGetAs(t *testing.T) {
+    opsMock, cleanup := MockOps()
+    defer cleanup()
+
+    scenarios := []struct {
+        desc string
+        test func(t *testing.T)
+    }{
+        {
+            desc: "variable was NOT overwritten",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(nil).Once()
+
+                actual, ok := GetAs[int](ctx, "name")
+
+                assert.False(t, ok)
+                assert.Equal(t, 0, actual)
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "variable was overwritten",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: float64(1)}).Once()
+
+                actual, ok := GetAs[int](ctx, "name")
+
+                assert.True(t, ok)
+                assert.Equal(t, 1, actual)
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "variable was overwritten with a number that does NOT fit",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: 300}).Once()
+                actualUint8, ok := GetAs[uint8](ctx, "name")
+                assert.False(t, ok)
+                assert.Equal(t, uint8(0), actualUint8)
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: -1}).Once()
+                actualUint64, ok := GetAs[uint64](ctx, "name")
+                assert.False(t, ok)
+                assert.Equal(t, uint64(0), actualUint64)
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: 1e20}).Once()
+                actualInt32, ok := GetAs[int32](ctx, "name")
+                assert.False(t, ok)
+                assert.Equal(t, int32(0), actualInt32)
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: 1.5}).Once()
+                actualInt, ok := GetAs[int](ctx, "name")
+                assert.False(t, ok)
+                assert.Equal(t, 0, actualInt)
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: 300}).Once()
+                actualUint16, ok := GetAs[uint16](ctx, "name")
+                assert.True(t, ok)
+                assert.Equal(t, uint16(300), actualUint16)
+
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+    }
+
+    for _, scenario := range scenarios {
+        sc := scenario
+        t.Run(sc.desc, func(t *testing.T) {
+            sc.test(t)
+        })
+    }
+}
+
+func TestGetOrDefault(t *testing.T) {
+    opsMock, cleanup := MockOps()
+    defer cleanup()
+
+    scenarios := []struct {
+        desc string
+        test func(t *testing.T)
+    }{
+        {
+            desc: "variable was NOT overwritten",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(nil).Once()
+
+                actual := GetOrDefault(ctx, "name", 10)
+
+                assert.Equal(t, int64(10), actual.AsInt())
+                assert.False(t, actual.IsPresent())
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "variable was overwritten",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: 0}).Once()
+
+                actual := GetOrDefault(ctx, "name", 10)
+
+                assert.Equal(t, int64(0), actual.AsInt())
+                assert.True(t, actual.IsPresent())
+                assert.True(t, actual.IsZero(), "zero overwrites must not be replaced by the fallback")
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+    }
+
+    for _, scenario := range scenarios {
+        sc := scenario
+        t.Run(sc.desc, func(t *testing.T) {
+            sc.test(t)
+        })
+    }
+}
+
+func TestGetOrDefaultAs(t *testing.T) {
+    opsMock, cleanup := MockOps()
+    defer cleanup()
+
+    scenarios := []struct {
+        desc string
+        test func(t *testing.T)
+    }{
+        {
+            desc: "variable was NOT overwritten",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(nil).Once()
+
+                actual := GetOrDefaultAs(ctx, "name", 10)
+
+                assert.Equal(t, 10, actual)
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "variable was overwritten to zero value",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: 0}).Once()
+
+                actual := GetOrDefaultAs(ctx, "name", 10)
+
+                assert.Equal(t, 0, actual)
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "variable was overwritten with an inconvertible value",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: "text"}).Once()
+
+                actual := GetOrDefaultAs(ctx, "name", 10)
+
+                assert.Equal(t, 10, actual)
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "variable was overwritten with a number that does NOT fit",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("GetOverwrittenValue", ctx, "name").Return(overwriteValue{value: 300}).Once()
+
+                actual := GetOrDefaultAs(ctx, "name", uint8(10))
+
+                assert.Equal(t, uint8(10), actual)
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
     }
 
     for _, scenario := range scenarios {
@@ -157,4 +548,62 @@ func TestGetOverwrittenValue(t *testing.T) {
             sc.test(t)
         })
     }
-}
\ No newline at end of file
+}
+
+func TestSnapshot(t *testing.T) {
+    opsMock, cleanup := MockOps()
+    defer cleanup()
+
+    scenarios := []struct {
+        desc string
+        test func(t *testing.T)
+    }{
+        {
+            desc: "ctx does NOT contain a Storage",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                opsMock.On("ExtractOverwritingStorage", ctx).Return(nil).Once()
+
+                assert.Nil(t, Snapshot(ctx))
+                mock.AssertExpectationsForObjects(t, opsMock)
+            },
+        },
+        {
+            desc: "ctx contains a Storage chain",
+            test: func(t *testing.T) {
+                ctx := context.Background()
+
+                parent := dynamicOverwritingStorage{
+                    variables: map[string]interface{}{
+                        "a": 1,
+                        "b": []int{1},
+                    },
+                }
+
+                storage := dynamicOverwritingStorage{
+                    parent: parent,
+                    variables: map[string]interface{}{
+                        "a": 2,
+                    },
+                }
+
+                opsMock.On("ExtractOverwritingStorage", ctx).Return(storage).Once()
+
+                actual := Snapshot(ctx)
+                assert.Equal(t, map[string]interface{}{"a": 2, "b": []int{1}}, actual)
+                mock.AssertExpectationsForObjects(t, opsMock)
+
+                actual["b"].([]int)[0] = 2
+                assert.Equal(t, []int{1}, parent.variables["b"], "changes to the snapshot must not affect our Storage")
+            },
+        },
+    }
+
+    for _, scenario := range scenarios {
+        sc := scenario
+        t.Run(sc.desc, func(t *testing.T) {
+            sc.test(t)
+        })
+    }
+}
@@ -0,0 +1,108 @@
+package dvow
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// DecodeHook is called before decoding every value in UnmarshalInto. It can return a
+// transformed version of data that should be decoded into the given type instead.
+type DecodeHook func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error)
+
+// DecodeOption configures how UnmarshalInto decodes values.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	hooks       []DecodeHook
+	errorUnused bool
+	// weaklyTyped makes strings parseable into numbers and booleans.
+	weaklyTyped bool
+}
+
+// WithDecodeHooks adds the given hooks, which run in order before the built-in hooks
+// parsing strings into time.Duration and time.Time.
+func WithDecodeHooks(hooks ...DecodeHook) DecodeOption {
+	return func(o *decodeOptions) {
+		o.hooks = append(o.hooks, hooks...)
+	}
+}
+
+// ErrorUnused makes UnmarshalInto return an error if the raw value contains keys that
+// do not match any field of the target struct.
+func ErrorUnused() DecodeOption {
+	return func(o *decodeOptions) {
+		o.errorUnused = true
+	}
+}
+
+// EpochToTimeHook returns a DecodeHook converting numbers to time.Time. Numbers are
+// treated as Unix timestamps in seconds, or in milliseconds if they are too large to
+// be seconds, similar to Value.AsTime.
+func EpochToTimeHook() DecodeHook {
+	timeType := reflect.TypeOf(time.Time{})
+
+	return func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != timeType || from == timeType {
+			return data, nil
+		}
+
+		if _, isNumber := castNumber(data); !isNumber {
+			return data, nil
+		}
+
+		if result, ok := castTime(data); ok {
+			return result, nil
+		}
+
+		return data, nil
+	}
+}
+
+// UnmarshalInto decodes the raw value wrapped inside v into the given target, which
+// must be a non-nil pointer. Struct fields are matched by their JSON tags and strings
+// are parsed into time.Duration and time.Time (RFC3339) by default. Unlike Unmarshal,
+// it does not fall back to a JSON round trip if decoding fails.
+func UnmarshalInto(v Value, target interface{}, opts ...DecodeOption) error {
+	rv := reflect.ValueOf(target)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrPointerArgumentRequired
+	}
+
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return decode(v.AsIs(), target, o)
+}
+
+// decode converts the given raw value into result, which must be a pointer.
+func decode(raw interface{}, result interface{}, o decodeOptions) error {
+	hooks := make([]mapstructure.DecodeHookFunc, 0, len(o.hooks)+2)
+	for _, hook := range o.hooks {
+		hooks = append(hooks, mapstructure.DecodeHookFuncType(hook))
+	}
+
+	hooks = append(
+		hooks,
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+	)
+
+	decoder, err := mapstructure.NewDecoder(
+		&mapstructure.DecoderConfig{
+			DecodeHook:       mapstructure.ComposeDecodeHookFunc(hooks...),
+			ErrorUnused:      o.errorUnused,
+			WeaklyTypedInput: o.weaklyTyped,
+			TagName:          "json",
+			Result:           result,
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(raw)
+}
@@ -0,0 +1,105 @@
+package dvow
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnmarshalInto(t *testing.T) {
+	type dummy struct {
+		Timeout   time.Duration `json:"timeout"`
+		CreatedAt time.Time     `json:"created_at"`
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "target is not a pointer",
+			test: func(t *testing.T) {
+				var target dummy
+
+				err := UnmarshalInto(overwriteValue{value: map[string]interface{}{}}, target)
+				assert.Equal(t, ErrPointerArgumentRequired, err)
+
+				err = UnmarshalInto(overwriteValue{value: map[string]interface{}{}}, (*dummy)(nil))
+				assert.Equal(t, ErrPointerArgumentRequired, err)
+			},
+		},
+		{
+			desc: "built-in hooks",
+			test: func(t *testing.T) {
+				var target dummy
+
+				err := UnmarshalInto(
+					overwriteValue{
+						value: map[string]interface{}{
+							"timeout":    "2s",
+							"created_at": "2022-01-02T03:04:05Z",
+						},
+					}, &target,
+				)
+
+				assert.Nil(t, err)
+				assert.Equal(t, dummy{Timeout: 2 * time.Second, CreatedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)}, target)
+			},
+		},
+		{
+			desc: "custom hooks",
+			test: func(t *testing.T) {
+				minutes := func(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+					if to != reflect.TypeOf(time.Duration(0)) || from.Kind() != reflect.Int {
+						return data, nil
+					}
+
+					return time.Duration(data.(int)) * time.Minute, nil
+				}
+
+				var target dummy
+
+				err := UnmarshalInto(
+					overwriteValue{
+						value: map[string]interface{}{
+							"timeout":    3,
+							"created_at": int64(1641092645),
+						},
+					}, &target, WithDecodeHooks(minutes, EpochToTimeHook()),
+				)
+
+				assert.Nil(t, err)
+				assert.Equal(t, 3*time.Minute, target.Timeout)
+				assert.Equal(t, time.Unix(1641092645, 0), target.CreatedAt)
+			},
+		},
+		{
+			desc: "unknown fields",
+			test: func(t *testing.T) {
+				v := overwriteValue{
+					value: map[string]interface{}{
+						"timeout": "2s",
+						"unknown": true,
+					},
+				}
+
+				var target dummy
+				assert.Nil(t, UnmarshalInto(v, &target))
+				assert.Equal(t, 2*time.Second, target.Timeout)
+
+				err := UnmarshalInto(v, &target, ErrorUnused())
+				assert.NotNil(t, err)
+				assert.Contains(t, err.Error(), "unknown")
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
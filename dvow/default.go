@@ -0,0 +1,38 @@
+package dvow
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+type storageHolder struct {
+	storage Storage
+}
+
+var globalDefaultStorage atomic.Value
+
+// SetDefaultStorage sets the process-wide Storage returned by ExtractOverwritingStorage
+// for contexts that do not have a Storage. As a result, variables overwritten in this
+// Storage are also inherited by all storages created via WithOverwrittenVariables and
+// the like. This allows static environment-level overwrites to work without per-request
+// wiring. Passing nil removes the default Storage.
+//
+// Note: the default Storage is considered part of the effective overwrites of every
+// context, hence it is also included in Snapshot and propagated downstream by the
+// gRPC interceptors.
+func SetDefaultStorage(storage Storage) {
+	globalDefaultStorage.Store(storageHolder{storage: storage})
+}
+
+// defaultStorage returns the Storage set via SetDefaultStorage, if any.
+func defaultStorage() Storage {
+	holder, _ := globalDefaultStorage.Load().(storageHolder)
+	return holder.storage
+}
+
+// NewStorage returns a standalone Storage holding the given overwritten variables,
+// e.g. to be used with SetDefaultStorage or MergeStorages.
+func NewStorage(overwrittenVariables map[string]interface{}, opts ...Option) Storage {
+	o := newOptions(opts...)
+	return newDynamicOverwritingStorage(nil, prepareVariables(overwrittenVariables, o), o, time.Time{})
+}
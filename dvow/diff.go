@@ -0,0 +1,85 @@
+package dvow
+
+import (
+	"context"
+
+	"github.com/jamestrandung/go-context/helper"
+)
+
+// ChangeType describes how an overwritten variable differs between two contexts.
+type ChangeType int
+
+const (
+	// Added means the variable is only overwritten in the second context.
+	Added ChangeType = iota + 1
+	// Removed means the variable is only overwritten in the first context.
+	Removed
+	// Modified means the variable is overwritten with different values in both contexts.
+	Modified
+)
+
+// String returns the name of this ChangeType.
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// Change describes how an overwritten variable differs between two contexts.
+type Change struct {
+	Type ChangeType
+	// Before is the value in the first context, nil if the variable was Added.
+	Before interface{}
+	// After is the value in the second context, nil if the variable was Removed.
+	After interface{}
+}
+
+// Diff returns the Change of every variable whose effective overwrite differs between
+// the two given contexts, keyed by variable name. This helps debug why two seemingly
+// identical requests took different code paths.
+func Diff(a context.Context, b context.Context) map[string]Change {
+	return DiffSnapshots(Snapshot(a), Snapshot(b))
+}
+
+// DiffSnapshots works like Diff but compares two maps returned by Snapshot.
+func DiffSnapshots(a map[string]interface{}, b map[string]interface{}) map[string]Change {
+	changes := make(map[string]Change)
+
+	for name, before := range a {
+		after, ok := b[name]
+		if !ok {
+			changes[name] = Change{
+				Type:   Removed,
+				Before: before,
+			}
+
+			continue
+		}
+
+		if !helper.Equal(before, after) {
+			changes[name] = Change{
+				Type:   Modified,
+				Before: before,
+				After:  after,
+			}
+		}
+	}
+
+	for name, after := range b {
+		if _, ok := a[name]; !ok {
+			changes[name] = Change{
+				Type:  Added,
+				After: after,
+			}
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,62 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no overwrites",
+			test: func(t *testing.T) {
+				assert.Equal(t, map[string]Change{}, Diff(context.Background(), context.Background()))
+			},
+		},
+		{
+			desc: "added, removed & modified",
+			test: func(t *testing.T) {
+				base := WithOverwrittenVariables(context.Background(), map[string]interface{}{"same": []int{1}, "modified": 1})
+
+				a := WithOverwrittenVariables(base, map[string]interface{}{"removed": true})
+				b := WithOverwrittenVariables(base, map[string]interface{}{"modified": "1", "added": nil})
+
+				expected := map[string]Change{
+					"removed": {
+						Type:   Removed,
+						Before: true,
+					},
+					"modified": {
+						Type:   Modified,
+						Before: 1,
+						After:  "1",
+					},
+					"added": {
+						Type: Added,
+					},
+				}
+
+				assert.Equal(t, expected, Diff(a, b))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestChangeType_String(t *testing.T) {
+	assert.Equal(t, "added", Added.String())
+	assert.Equal(t, "removed", Removed.String())
+	assert.Equal(t, "modified", Modified.String())
+	assert.Equal(t, "unknown", ChangeType(0).String())
+}
@@ -1,8 +1,52 @@
 package dvow
 
-import "errors"
+import (
+    "errors"
+
+    "github.com/jamestrandung/go-context/ctxerr"
+)
 
 var (
     // ErrPointerArgumentRequired ...
     ErrPointerArgumentRequired = errors.New("value type should be a pointer to struct")
-)
\ No newline at end of file
+    // ErrUnsupportedWireVersion is returned when the data to unmarshal was produced
+    // by an unsupported version of the wire format.
+    ErrUnsupportedWireVersion = errors.New("unsupported wire format version")
+    // ErrUnknownWireTypeHint is returned when a value in the data to unmarshal
+    // carries an unknown type hint.
+    ErrUnknownWireTypeHint = errors.New("unknown wire format type hint")
+    // ErrUnexpectedKind is returned when an overwritten value is not of the kind
+    // required by its Rule.
+    ErrUnexpectedKind = errors.New("unexpected kind of overwritten value")
+    // ErrOutOfRange is returned when an overwritten value is outside the range
+    // allowed by its Rule.
+    ErrOutOfRange = errors.New("overwritten value is out of range")
+    // ErrNotInEnum is returned when an overwritten value is not one of the values
+    // allowed by its Rule.
+    ErrNotInEnum = errors.New("overwritten value is not allowed")
+    // ErrTypeMismatch is returned by the strict accessors of Value when the overwritten
+    // value cannot be cast to the requested type.
+    ErrTypeMismatch = ctxerr.New(ctxerr.ConversionFailed, "overwritten value cannot be cast to the requested type")
+    // ErrImmutableStorage is returned when trying to change overwritten variables in
+    // a context that does not have a mutable Storage.
+    ErrImmutableStorage = errors.New("context does not have a mutable storage")
+    // ErrInvalidExpression is returned when an Expression cannot be evaluated.
+    ErrInvalidExpression = errors.New("invalid expression")
+    // ErrUnresolvedIdentifier is returned when an Expression references an identifier
+    // that cannot be resolved.
+    ErrUnresolvedIdentifier = errors.New("unresolved identifier in expression")
+    // ErrDivisionByZero is returned when an Expression divides by zero.
+    ErrDivisionByZero = errors.New("division by zero in expression")
+    // ErrIncompatibleField is returned by ApplyOverwrites when an overwritten value
+    // cannot be converted to the type of its struct field.
+    ErrIncompatibleField = ctxerr.New(ctxerr.ConversionFailed, "overwritten value cannot be assigned to struct field")
+    // ErrMutatedValue is raised in the DetectMutations mode when an overwritten value
+    // was modified after it had been stored.
+    ErrMutatedValue = errors.New("overwritten value was mutated after being stored")
+    // ErrOverflow is returned by the strict accessors of Value when the overwritten
+    // value is outside the range of the requested type.
+    ErrOverflow = ctxerr.New(ctxerr.ConversionFailed, "overwritten value overflows the requested type")
+    // ErrPrecisionLoss is returned by the strict accessors of Value when the overwritten
+    // value cannot be represented exactly in the requested type.
+    ErrPrecisionLoss = ctxerr.New(ctxerr.ConversionFailed, "overwritten value loses precision in the requested type")
+)
@@ -0,0 +1,330 @@
+package dvow
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+// Expression is an overwritten value that is evaluated on lookup, e.g. "base_fee * 1.1".
+// Expressions are only evaluated in storages created with the EvaluateExpressions option,
+// and only by GetOverwrittenValue and the functions built on top of it. Storage.Get and
+// Snapshot return the Expression as-is.
+type Expression string
+
+// Resolver returns the value of an identifier referenced by an Expression and true, or
+// false if the identifier cannot be resolved.
+type Resolver func(name string) (interface{}, bool)
+
+// Evaluator evaluates an Expression using the given Resolver to look up identifiers.
+//
+//go:generate mockery --name Evaluator --case underscore --inpkg
+type Evaluator interface {
+	// Evaluate returns the result of the given expression.
+	Evaluate(expression string, resolve Resolver) (interface{}, error)
+}
+
+// EvaluatorFunc is an adapter to allow the use of ordinary functions as Evaluator.
+type EvaluatorFunc func(expression string, resolve Resolver) (interface{}, error)
+
+// Evaluate calls f(expression, resolve).
+func (f EvaluatorFunc) Evaluate(expression string, resolve Resolver) (interface{}, error) {
+	return f(expression, resolve)
+}
+
+type expressionConfig struct {
+	evaluator  Evaluator
+	attributes AttributesExtractor
+}
+
+// EvaluateExpressions makes overwritten values of type Expression get evaluated on lookup
+// using the given Evaluator, or ArithmeticEvaluator if it is nil. Identifiers referenced
+// by expressions are resolved against other overwritten variables first, then against the
+// Attributes returned by the given extractor, if not nil. If the evaluation fails, the
+// variable behaves as if it was not overwritten.
+func EvaluateExpressions(evaluator Evaluator, extractor AttributesExtractor) Option {
+	if evaluator == nil {
+		evaluator = ArithmeticEvaluator()
+	}
+
+	return func(o *options) {
+		o.expressions = &expressionConfig{
+			evaluator:  evaluator,
+			attributes: extractor,
+		}
+	}
+}
+
+type evaluatingKey struct{}
+
+// evaluation tracks the names of variables being evaluated to detect cycles.
+type evaluation struct {
+	name   string
+	parent *evaluation
+}
+
+func (e *evaluation) contains(name string) bool {
+	for cur := e; cur != nil; cur = cur.parent {
+		if cur.name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evaluateExpression returns a Value wrapping the result of the Expression wrapped in
+// the given Value if any, nil if its evaluation fails. Other values are returned as-is.
+func evaluateExpression(ctx context.Context, name string, value Value) Value {
+	ov, ok := value.(overwriteValue)
+	if !ok || ov.expressions == nil {
+		return value
+	}
+
+	expression, ok := ov.value.(Expression)
+	if !ok {
+		return value
+	}
+
+	result, err := ov.expressions.evaluate(ctx, name, string(expression))
+	if err != nil {
+		return nil
+	}
+
+	ov.value = result
+	return ov
+}
+
+func (c *expressionConfig) evaluate(ctx context.Context, name string, expression string) (interface{}, error) {
+	parent, _ := ctx.Value(evaluatingKey{}).(*evaluation)
+	current := &evaluation{
+		name:   name,
+		parent: parent,
+	}
+
+	ctx = context.WithValue(ctx, evaluatingKey{}, current)
+
+	// Identifiers are looked up from the Storage directly so that the access log &
+	// metrics only reflect the variables the caller asked for.
+	storage := Ops.ExtractOverwritingStorage(ctx)
+
+	var attributes Attributes
+	resolve := func(identifier string) (interface{}, bool) {
+		// Variables being evaluated cannot be referenced to avoid infinite recursion
+		if current.contains(identifier) {
+			return nil, false
+		}
+
+		if value := evaluateExpression(ctx, identifier, getOverwrittenValue(storage, identifier)); value != nil {
+			return value.AsIs(), true
+		}
+
+		if c.attributes == nil {
+			return nil, false
+		}
+
+		if attributes == nil {
+			attributes = c.attributes(ctx)
+		}
+
+		value, ok := attributes[identifier]
+		return value, ok
+	}
+
+	return c.evaluator.Evaluate(expression, resolve)
+}
+
+// ArithmeticEvaluator returns an Evaluator supporting numbers, identifiers (which may
+// contain dots), parentheses and the +, -, * and / operators. The result is a float64.
+// Dividing by zero fails with ErrDivisionByZero.
+func ArithmeticEvaluator() Evaluator {
+	return EvaluatorFunc(
+		func(expression string, resolve Resolver) (interface{}, error) {
+			p := &arithmeticParser{
+				input:   expression,
+				resolve: resolve,
+			}
+
+			result, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+
+			p.skipSpaces()
+			if p.pos < len(p.input) {
+				return nil, p.unexpected()
+			}
+
+			return result, nil
+		},
+	)
+}
+
+// arithmeticParser is a recursive descent parser evaluating arithmetic expressions.
+type arithmeticParser struct {
+	input   string
+	pos     int
+	resolve Resolver
+}
+
+// parseExpression parses: term (('+' | '-') term)*
+func (p *arithmeticParser) parseExpression() (float64, error) {
+	result, err := p.parseTerm()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			return result, nil
+		}
+
+		op := p.input[p.pos]
+		p.pos++
+
+		operand, err := p.parseTerm()
+		if err != nil {
+			return 0, err
+		}
+
+		if op == '+' {
+			result += operand
+		} else {
+			result -= operand
+		}
+	}
+}
+
+// parseTerm parses: factor (('*' | '/') factor)*
+func (p *arithmeticParser) parseTerm() (float64, error) {
+	result, err := p.parseFactor()
+	if err != nil {
+		return 0, err
+	}
+
+	for {
+		p.skipSpaces()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '*' && p.input[p.pos] != '/') {
+			return result, nil
+		}
+
+		op := p.input[p.pos]
+		p.pos++
+
+		operand, err := p.parseFactor()
+		if err != nil {
+			return 0, err
+		}
+
+		if op == '*' {
+			result *= operand
+			continue
+		}
+
+		if operand == 0 {
+			return 0, ErrDivisionByZero
+		}
+
+		result /= operand
+	}
+}
+
+// parseFactor parses: '-' factor | '(' expression ')' | number | identifier
+func (p *arithmeticParser) parseFactor() (float64, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0, p.unexpected()
+	}
+
+	c := rune(p.input[p.pos])
+	switch {
+	case c == '-':
+		p.pos++
+
+		result, err := p.parseFactor()
+		return -result, err
+
+	case c == '(':
+		p.pos++
+
+		result, err := p.parseExpression()
+		if err != nil {
+			return 0, err
+		}
+
+		p.skipSpaces()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return 0, p.unexpected()
+		}
+
+		p.pos++
+
+		return result, nil
+
+	case unicode.IsDigit(c) || c == '.':
+		start := p.pos
+		for p.pos < len(p.input) && (unicode.IsDigit(rune(p.input[p.pos])) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+
+		result, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return 0, errors.Wrap(ErrInvalidExpression, err.Error())
+		}
+
+		return result, nil
+
+	case unicode.IsLetter(c) || c == '_':
+		start := p.pos
+		for p.pos < len(p.input) && isIdentifierChar(rune(p.input[p.pos])) {
+			p.pos++
+		}
+
+		return p.resolveNumber(p.input[start:p.pos])
+
+	default:
+		return 0, p.unexpected()
+	}
+}
+
+func (p *arithmeticParser) resolveNumber(identifier string) (float64, error) {
+	value, ok := p.resolve(identifier)
+	if !ok {
+		return 0, errors.Wrap(ErrUnresolvedIdentifier, identifier)
+	}
+
+	if result, ok := castNumber(value); ok {
+		return result, nil
+	}
+
+	if str, ok := value.(string); ok {
+		if result, err := strconv.ParseFloat(strings.TrimSpace(str), 64); err == nil {
+			return result, nil
+		}
+	}
+
+	return 0, errors.Wrap(ErrInvalidExpression, fmt.Sprintf("%s is not a number", identifier))
+}
+
+func (p *arithmeticParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *arithmeticParser) unexpected() error {
+	if p.pos >= len(p.input) {
+		return errors.Wrap(ErrInvalidExpression, "unexpected end of expression")
+	}
+
+	return errors.Wrap(ErrInvalidExpression, fmt.Sprintf("unexpected %q at position %d", p.input[p.pos], p.pos))
+}
+
+func isIdentifierChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '.'
+}
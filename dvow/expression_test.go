@@ -0,0 +1,183 @@
+package dvow
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestArithmeticEvaluator(t *testing.T) {
+	resolve := func(name string) (interface{}, bool) {
+		switch name {
+		case "base_fee":
+			return 10, true
+		case "pricing.ratio":
+			return "0.5", true
+		case "text":
+			return "abc", true
+		default:
+			return nil, false
+		}
+	}
+
+	scenarios := []struct {
+		desc        string
+		expression  string
+		expected    interface{}
+		expectedErr error
+	}{
+		{
+			desc:       "precedence",
+			expression: "1 + 2 * 3",
+			expected:   7.0,
+		},
+		{
+			desc:       "parentheses and unary minus",
+			expression: "-(1 + 2) * 3 / 2",
+			expected:   -4.5,
+		},
+		{
+			desc:       "identifiers",
+			expression: "base_fee * pricing.ratio - .5",
+			expected:   4.5,
+		},
+		{
+			desc:        "unresolved identifier",
+			expression:  "unknown * 2",
+			expectedErr: ErrUnresolvedIdentifier,
+		},
+		{
+			desc:        "non-numeric identifier",
+			expression:  "text * 2",
+			expectedErr: ErrInvalidExpression,
+		},
+		{
+			desc:        "division by zero",
+			expression:  "base_fee / (1 - 1)",
+			expectedErr: ErrDivisionByZero,
+		},
+		{
+			desc:        "missing closing parenthesis",
+			expression:  "(1 + 2",
+			expectedErr: ErrInvalidExpression,
+		},
+		{
+			desc:        "trailing garbage",
+			expression:  "1 + 2 )",
+			expectedErr: ErrInvalidExpression,
+		},
+		{
+			desc:        "empty",
+			expression:  "",
+			expectedErr: ErrInvalidExpression,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			actual, err := ArithmeticEvaluator().Evaluate(sc.expression, resolve)
+			if sc.expectedErr != nil {
+				assert.True(t, errors.Is(err, sc.expectedErr), err)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, sc.expected, actual)
+		})
+	}
+}
+
+func TestEvaluateExpressions(t *testing.T) {
+	extractor := func(ctx context.Context) Attributes {
+		return Attributes{"distance": "3"}
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "expressions are NOT evaluated by default",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"fee": Expression("1 + 1")})
+
+				assert.Equal(t, Expression("1 + 1"), GetOverwrittenValue(ctx, "fee").AsIs())
+			},
+		},
+		{
+			desc: "expressions reference other overwrites and attributes",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"fee":   Expression("base_fee * 1.1 + distance"),
+						"total": Expression("fee * 2"),
+					}, EvaluateExpressions(nil, extractor),
+				)
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"base_fee": 10})
+
+				assert.InDelta(t, 14.0, GetOverwrittenValue(ctx, "fee").AsFloat(), 1e-9)
+				assert.InDelta(t, 28.0, GetOverwrittenValue(ctx, "total").AsFloat(), 1e-9)
+
+				actual, ok := GetAs[float64](ctx, "total")
+				assert.True(t, ok)
+				assert.InDelta(t, 28.0, actual, 1e-9)
+			},
+		},
+		{
+			desc: "referenced variables are not recorded as accessed",
+			test: func(t *testing.T) {
+				ctx := WithAccessLog(context.Background())
+				ctx = WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"fee":      Expression("base_fee * 2"),
+						"base_fee": 10,
+					}, EvaluateExpressions(nil, nil),
+				)
+
+				assert.InDelta(t, 20.0, GetOverwrittenValue(ctx, "fee").AsFloat(), 1e-9)
+				assert.Equal(t, []string{"fee"}, ReportUsage(ctx).Read)
+			},
+		},
+		{
+			desc: "failed evaluations behave as if not overwritten",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"a": Expression("b + 1"),
+						"b": Expression("a + 1"),
+						"c": Expression("c"),
+					}, EvaluateExpressions(nil, nil),
+				)
+
+				assert.Nil(t, GetOverwrittenValue(ctx, "a"))
+				assert.Nil(t, GetOverwrittenValue(ctx, "b"))
+				assert.Nil(t, GetOverwrittenValue(ctx, "c"))
+			},
+		},
+		{
+			desc: "custom evaluator",
+			test: func(t *testing.T) {
+				evaluatorMock := &MockEvaluator{}
+				evaluatorMock.On("Evaluate", "upper(name)", mock.Anything).Return("TEST", nil).Once()
+
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{"a": Expression("upper(name)")}, EvaluateExpressions(evaluatorMock, nil),
+				)
+
+				assert.Equal(t, "TEST", GetOverwrittenValue(ctx, "a").AsString())
+				mock.AssertExpectationsForObjects(t, evaluatorMock)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package dvow
+
+import (
+	"context"
+)
+
+// multiGetter is implemented by Storage that can resolve several variables in one pass
+// over their chain of parents.
+type multiGetter interface {
+	// getMany stores the Value of every variable under the given names into result,
+	// nil for those that were not overwritten, and adds those that were masked to masked.
+	getMany(names []string, result map[string]Value, masked map[string]struct{})
+}
+
+// GetMany returns the Value of all variables under the given names that were overwritten,
+// keyed by their names. Variables that were not overwritten are absent from the result.
+//
+// It behaves like calling GetOverwrittenValue for each name, including dot-paths and
+// expressions, but walks the Storage chain only once for all names. Prefer it to read
+// the many variables of a hot config struct.
+func GetMany(ctx context.Context, names ...string) map[string]Value {
+	storage := Ops.ExtractOverwritingStorage(ctx)
+
+	resolved := make(map[string]Value, len(names))
+	masked := make(map[string]struct{})
+	getMany(storage, names, resolved, masked)
+
+	result := make(map[string]Value, len(names))
+	for _, name := range names {
+		value := resolved[name]
+		if _, isMasked := masked[name]; value == nil && !isMasked && storage != nil {
+			value = getNestedValue(storage, name)
+		}
+
+		value = evaluateExpression(ctx, name, value)
+		recordAccess(ctx, name, value != nil)
+		reportLookup(storage, name, value != nil)
+
+		if value != nil {
+			result[name] = value
+		}
+	}
+
+	return result
+}
+
+// GetManyAs works like GetMany except that values are converted to T. Variables that
+// were not overwritten or whose values cannot be converted are absent from the result.
+func GetManyAs[T any](ctx context.Context, names ...string) map[string]T {
+	values := GetMany(ctx, names...)
+
+	result := make(map[string]T, len(values))
+	for name, value := range values {
+		if converted, ok := convertValue[T](value); ok {
+			result[name] = converted
+		}
+	}
+
+	return result
+}
+
+// getMany resolves the given names using the given Storage, in one pass if it is a
+// multiGetter or one by one otherwise.
+func getMany(storage Storage, names []string, result map[string]Value, masked map[string]struct{}) {
+	if storage == nil || len(names) == 0 {
+		return
+	}
+
+	if mg, ok := storage.(multiGetter); ok {
+		mg.getMany(names, result, masked)
+		return
+	}
+
+	for _, name := range names {
+		value, isMasked := lookup(storage, name)
+		if isMasked {
+			masked[name] = struct{}{}
+		}
+
+		result[name] = value
+	}
+}
+
+func (s dynamicOverwritingStorage) getMany(names []string, result map[string]Value, masked map[string]struct{}) {
+	isExpired := s.isExpired()
+
+	var remaining []string
+	for _, name := range names {
+		if value, isPresent := s.variables[name]; isPresent && !isExpired {
+			s.options.verifyChecksum(s.checksums, name, value)
+			if _, isMasked := value.(tombstone); isMasked {
+				masked[name] = struct{}{}
+			}
+
+			result[name] = s.wrap(value)
+			continue
+		}
+
+		if value, isPresent := s.inherited[name]; isPresent {
+			if value == nil {
+				// Masked variables are flattened to nil
+				masked[name] = struct{}{}
+			}
+
+			result[name] = value
+			continue
+		}
+
+		remaining = append(remaining, name)
+	}
+
+	getMany(s.parent, remaining, result, masked)
+}
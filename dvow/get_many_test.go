@@ -0,0 +1,106 @@
+package dvow
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMany(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no storage",
+			test: func(t *testing.T) {
+				assert.Empty(t, GetMany(context.Background(), "a", "b"))
+			},
+		},
+		{
+			desc: "same results as GetOverwrittenValue",
+			test: func(t *testing.T) {
+				storageMock := &MockStorage{}
+				storageMock.On("Get", "root").Return(NewStorage(map[string]interface{}{"root": 1}).Get("root")).Once()
+				storageMock.On("Get", "missing").Return(nil)
+				storageMock.On("Get", "nested.leaf").Return(nil)
+
+				ctx := WithOverwritingStorage(context.Background(), storageMock)
+				ctx = WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"a":      1,
+						"nested": map[string]interface{}{"leaf": 2},
+					},
+				)
+				ctx = WithoutOverwrittenVariables(ctx, "masked")
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"b": "2"}, LenientConversion())
+
+				names := []string{"a", "b", "root", "masked", "missing", "nested.leaf", "a"}
+
+				actual := GetMany(ctx, names...)
+				storageMock.AssertExpectations(t)
+				storageMock.AssertNotCalled(t, "Get", "masked")
+
+				assert.Equal(t, []string{"a", "b", "nested.leaf", "root"}, sortedKeys(actual))
+				for _, name := range names {
+					if name == "root" {
+						continue
+					}
+
+					assert.Equal(t, GetOverwrittenValue(ctx, name), actual[name], name)
+				}
+
+				assert.Equal(t, 1, actual["root"].AsIs())
+				assert.Equal(t, int64(2), actual["b"].AsInt())
+			},
+		},
+		{
+			desc: "typed variant",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"a": 1,
+						"b": int64(2),
+						"c": "3",
+					},
+				)
+
+				assert.Equal(t, map[string]int64{"a": 1, "b": 2}, GetManyAs[int64](ctx, "a", "b", "c", "d"))
+			},
+		},
+		{
+			desc: "typed variant skips numbers that do not fit",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"a": 1,
+						"b": 300,
+						"c": -1,
+					},
+				)
+
+				assert.Equal(t, map[string]uint8{"a": 1}, GetManyAs[uint8](ctx, "a", "b", "c"))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func sortedKeys(m map[string]Value) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
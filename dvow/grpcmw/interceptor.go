@@ -0,0 +1,145 @@
+// Package grpcmw provides gRPC interceptors that propagate overwritten variables
+// across service boundaries using gRPC metadata.
+package grpcmw
+
+import (
+	"context"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the metadata key carrying the overwritten variables.
+const MetadataKey = "x-dvow-overwrites"
+
+// UnaryClientInterceptor returns a client interceptor that serializes all variables
+// effectively overwritten in the outgoing context into metadata using the wire format
+// of dvow.MarshalStorage.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		outgoingCtx, err := inject(ctx)
+		if err != nil {
+			return err
+		}
+
+		return invoker(outgoingCtx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor that serializes all variables
+// effectively overwritten in the outgoing context into metadata using the wire format
+// of dvow.MarshalStorage.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		outgoingCtx, err := inject(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return streamer(outgoingCtx, desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerInterceptor returns a server interceptor that reconstructs the Storage
+// of overwritten variables from the incoming metadata. The given options are passed
+// to dvow.WithOverwrittenVariables.
+func UnaryServerInterceptor(opts ...dvow.Option) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		incomingCtx, err := extract(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(incomingCtx, req)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor that reconstructs the Storage
+// of overwritten variables from the incoming metadata. The given options are passed
+// to dvow.WithOverwrittenVariables.
+func StreamServerInterceptor(opts ...dvow.Option) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		incomingCtx, err := extract(ss.Context(), opts...)
+		if err != nil {
+			return err
+		}
+
+		return handler(
+			srv, &serverStream{
+				ServerStream: ss,
+				ctx:          incomingCtx,
+			},
+		)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context ...
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// inject returns a context carrying the overwritten variables in ctx in its
+// outgoing metadata.
+func inject(ctx context.Context) (context.Context, error) {
+	storage := dvow.ExtractOverwritingStorageV2(ctx)
+	if storage == nil || len(storage.Keys()) == 0 {
+		return ctx, nil
+	}
+
+	data, err := dvow.MarshalStorage(storage)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to serialize overwritten variables: %v", err)
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, string(data)), nil
+}
+
+// extract returns a context holding the overwritten variables found in the incoming
+// metadata of ctx.
+func extract(ctx context.Context, opts ...dvow.Option) (context.Context, error) {
+	values := metadata.ValueFromIncomingContext(ctx, MetadataKey)
+	if len(values) == 0 {
+		return ctx, nil
+	}
+
+	opts = append([]dvow.Option{dvow.WithSource(dvow.SourceRemote)}, opts...)
+
+	incomingCtx, err := dvow.Ops.WithOverwrittenVariablesFromWire(ctx, []byte(values[0]), opts...)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid overwritten variables: %v", err)
+	}
+
+	return incomingCtx, nil
+}
@@ -0,0 +1,161 @@
+package grpcmw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// propagate simulates sending the outgoing metadata of ctx over the wire.
+func propagate(ctx context.Context) context.Context {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	return metadata.NewIncomingContext(context.Background(), md)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "overwrites follow the request",
+			test: func(t *testing.T) {
+				ctx := dvow.WithOverwrittenVariables(context.Background(), map[string]interface{}{"surge": 1.5, "count": int64(2)})
+
+				var incomingCtx context.Context
+				err := UnaryClientInterceptor()(
+					ctx, "/svc/method", nil, nil, nil,
+					func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+						incomingCtx = propagate(ctx)
+						return nil
+					},
+				)
+				assert.Nil(t, err)
+
+				var actual map[string]interface{}
+				_, err = UnaryServerInterceptor()(
+					incomingCtx, nil, &grpc.UnaryServerInfo{},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						actual = dvow.Snapshot(ctx)
+						return nil, nil
+					},
+				)
+				assert.Nil(t, err)
+				assert.Equal(t, map[string]interface{}{"surge": 1.5, "count": int64(2)}, actual)
+			},
+		},
+		{
+			desc: "no overwrites",
+			test: func(t *testing.T) {
+				var incomingCtx context.Context
+				err := UnaryClientInterceptor()(
+					context.Background(), "/svc/method", nil, nil, nil,
+					func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+						incomingCtx = propagate(ctx)
+						return nil
+					},
+				)
+				assert.Nil(t, err)
+
+				_, err = UnaryServerInterceptor()(
+					incomingCtx, nil, &grpc.UnaryServerInfo{},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						assert.Nil(t, dvow.ExtractOverwritingStorage(ctx))
+						return nil, nil
+					},
+				)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			desc: "overwrites cannot be serialized",
+			test: func(t *testing.T) {
+				ctx := dvow.WithOverwrittenVariables(context.Background(), map[string]interface{}{"fn": func() {}})
+
+				err := UnaryClientInterceptor()(
+					ctx, "/svc/method", nil, nil, nil,
+					func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+						assert.Fail(t, "invoker must not be called")
+						return nil
+					},
+				)
+				assert.Equal(t, codes.Internal, status.Code(err))
+			},
+		},
+		{
+			desc: "invalid incoming metadata",
+			test: func(t *testing.T) {
+				incomingCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "{"))
+
+				_, err := UnaryServerInterceptor()(
+					incomingCtx, nil, &grpc.UnaryServerInfo{},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						assert.Fail(t, "handler must not be called")
+						return nil, nil
+					},
+				)
+				assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	ctx := dvow.WithOverwrittenVariables(context.Background(), map[string]interface{}{"count": "3"})
+
+	var incomingCtx context.Context
+	_, err := StreamClientInterceptor()(
+		ctx, &grpc.StreamDesc{}, nil, "/svc/method",
+		func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			incomingCtx = propagate(ctx)
+			return nil, nil
+		},
+	)
+	assert.Nil(t, err)
+
+	var actual int64
+	var source dvow.Source
+	err = StreamServerInterceptor(dvow.LenientConversion())(
+		nil, &fakeServerStream{ctx: incomingCtx}, &grpc.StreamServerInfo{},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			value := dvow.GetOverwrittenValue(stream.Context(), "count")
+			actual = value.AsInt()
+			source = dvow.SourceOf(value)
+			return nil
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), actual)
+	assert.Equal(t, dvow.SourceRemote, source)
+
+	err = StreamServerInterceptor()(
+		nil, &fakeServerStream{ctx: metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "["))},
+		&grpc.StreamServerInfo{},
+		func(srv interface{}, stream grpc.ServerStream) error {
+			assert.Fail(t, "handler must not be called")
+			return nil
+		},
+	)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
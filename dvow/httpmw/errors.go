@@ -0,0 +1,10 @@
+package httpmw
+
+import (
+	"errors"
+)
+
+var (
+	ErrInvalidPayload = errors.New("invalid overwrite payload")
+	ErrNameNotAllowed = errors.New("variable is not allowed to be overwritten")
+)
@@ -0,0 +1,199 @@
+// Package httpmw provides a net/http middleware that extracts overwritten variables
+// from request headers and attaches them to the request context using dvow.
+package httpmw
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/pkg/errors"
+)
+
+// DefaultJSONHeader is the default header carrying a JSON object of overwrites.
+const DefaultJSONHeader = "X-Variable-Overrides"
+
+// Config configures the middleware returned by New.
+type Config struct {
+	// JSONHeader is the header carrying a JSON object of overwrites. Defaults to
+	// DefaultJSONHeader if empty.
+	JSONHeader string
+	// HeaderPrefix is the prefix of headers each carrying one overwrite as a string,
+	// e.g. "X-Override-" for "X-Override-Surge_multiplier: 1.2". These headers are
+	// ignored if empty.
+	HeaderPrefix string
+	// AllowedNames is the list of variables that clients are allowed to overwrite.
+	// All variables are allowed if empty, which is NOT recommended for public APIs.
+	//
+	// Since HTTP header names are case-insensitive, names extracted using the
+	// HeaderPrefix are matched against this list case-insensitively. If this list
+	// is empty, such names will be lower-cased.
+	AllowedNames []string
+	// Options are passed to dvow.WithOverwrittenVariables. Consider including
+	// dvow.LenientConversion as all values from prefixed headers are strings.
+	Options []dvow.Option
+	// ErrorHandler is invoked instead of the next handler when the overwrites are
+	// invalid. By default, it responds with 400 Bad Request.
+	ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
+	// OnUsage, if not nil, is invoked after the next handler returns with a report
+	// of which overwrites were set and read while handling the request. It enables
+	// the access log of dvow on every request.
+	OnUsage func(r *http.Request, report dvow.UsageReport)
+}
+
+// New returns a middleware that reads overwritten variables from the request headers
+// as specified in the given Config, validates their names and calls
+// dvow.WithOverwrittenVariables on the request context.
+func New(cfg Config) func(http.Handler) http.Handler {
+	e := newExtractor(cfg)
+
+	errorHandler := cfg.ErrorHandler
+	if errorHandler == nil {
+		errorHandler = defaultErrorHandler
+	}
+
+	opts := append([]dvow.Option{dvow.WithSource(dvow.SourceHeader)}, cfg.Options...)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				overwrittenVariables, err := e.extract(r.Header)
+				if err != nil {
+					errorHandler(w, r, err)
+					return
+				}
+
+				if len(overwrittenVariables) == 0 && cfg.OnUsage == nil {
+					next.ServeHTTP(w, r)
+					return
+				}
+
+				ctx := r.Context()
+				if cfg.OnUsage != nil {
+					ctx = dvow.WithAccessLog(ctx)
+				}
+
+				ctx = dvow.Ops.WithOverwrittenVariables(ctx, overwrittenVariables, opts...)
+				r = r.WithContext(ctx)
+
+				next.ServeHTTP(w, r)
+
+				if cfg.OnUsage != nil {
+					cfg.OnUsage(r, dvow.ReportUsage(ctx))
+				}
+			},
+		)
+	}
+}
+
+// Extract returns the overwritten variables found in the given headers as specified
+// in the given Config. It is exposed so that other middlewares can reuse the same
+// extraction logic.
+func Extract(cfg Config, header http.Header) (map[string]interface{}, error) {
+	return newExtractor(cfg).extract(header)
+}
+
+type extractor struct {
+	jsonHeader   string
+	headerPrefix string
+	// allowedNames maps lower-cased names to their original names.
+	allowedNames map[string]string
+}
+
+func newExtractor(cfg Config) extractor {
+	e := extractor{
+		jsonHeader:   cfg.JSONHeader,
+		headerPrefix: strings.ToLower(cfg.HeaderPrefix),
+		allowedNames: make(map[string]string, len(cfg.AllowedNames)),
+	}
+
+	if e.jsonHeader == "" {
+		e.jsonHeader = DefaultJSONHeader
+	}
+
+	for _, name := range cfg.AllowedNames {
+		e.allowedNames[strings.ToLower(name)] = name
+	}
+
+	return e
+}
+
+// resolveName returns the allowed name matching the given name case-insensitively,
+// or the lower-cased name if all names are allowed.
+func (e extractor) resolveName(name string) (string, error) {
+	if len(e.allowedNames) == 0 {
+		return strings.ToLower(name), nil
+	}
+
+	allowedName, ok := e.allowedNames[strings.ToLower(name)]
+	if !ok {
+		return "", errors.Wrap(ErrNameNotAllowed, name)
+	}
+
+	return allowedName, nil
+}
+
+func (e extractor) extract(header http.Header) (map[string]interface{}, error) {
+	overwrittenVariables := make(map[string]interface{})
+
+	if payload := header.Get(e.jsonHeader); payload != "" {
+		decoder := json.NewDecoder(bytes.NewReader([]byte(payload)))
+		decoder.UseNumber()
+
+		var decoded map[string]interface{}
+		if err := decoder.Decode(&decoded); err != nil {
+			return nil, errors.Wrap(ErrInvalidPayload, err.Error())
+		}
+
+		// A null payload decodes into a nil map without error
+		if decoded == nil {
+			return nil, errors.Wrap(ErrInvalidPayload, "payload must be a JSON object")
+		}
+
+		// Names in JSON payloads must match the allowed names exactly
+		for name, value := range decoded {
+			if len(e.allowedNames) > 0 && e.allowedNames[strings.ToLower(name)] != name {
+				return nil, errors.Wrap(ErrNameNotAllowed, name)
+			}
+
+			overwrittenVariables[name] = value
+		}
+	}
+
+	if e.headerPrefix == "" {
+		return overwrittenVariables, nil
+	}
+
+	for key, values := range header {
+		if len(values) == 0 || !strings.HasPrefix(strings.ToLower(key), e.headerPrefix) {
+			continue
+		}
+
+		// A header that is exactly the prefix does not name any variable
+		suffix := key[len(e.headerPrefix):]
+		if suffix == "" {
+			continue
+		}
+
+		name, err := e.resolveName(suffix)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(values) == 1 {
+			overwrittenVariables[name] = values[0]
+			continue
+		}
+
+		overwrittenVariables[name] = values
+	}
+
+	return overwrittenVariables, nil
+}
+
+func defaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	http.Error(w, fmt.Sprintf("bad request: %v", err), http.StatusBadRequest)
+}
@@ -0,0 +1,274 @@
+package httpmw
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	scenarios := []struct {
+		desc         string
+		cfg          Config
+		header       http.Header
+		wantStatus   int
+		wantSnapshot map[string]interface{}
+	}{
+		{
+			desc:         "no overwrites",
+			cfg:          Config{},
+			header:       http.Header{},
+			wantStatus:   http.StatusOK,
+			wantSnapshot: nil,
+		},
+		{
+			desc: "JSON header",
+			cfg:  Config{},
+			header: http.Header{
+				DefaultJSONHeader: []string{`{"surge_multiplier": 1.2, "enabled": true}`},
+			},
+			wantStatus: http.StatusOK,
+			wantSnapshot: map[string]interface{}{
+				"surge_multiplier": json.Number("1.2"),
+				"enabled":          true,
+			},
+		},
+		{
+			desc: "custom JSON header with allowed names",
+			cfg: Config{
+				JSONHeader:   "X-Custom",
+				AllowedNames: []string{"surge_multiplier"},
+			},
+			header: http.Header{
+				"X-Custom": []string{`{"surge_multiplier": 1}`},
+			},
+			wantStatus: http.StatusOK,
+			wantSnapshot: map[string]interface{}{
+				"surge_multiplier": json.Number("1"),
+			},
+		},
+		{
+			desc: "invalid JSON header",
+			cfg:  Config{},
+			header: http.Header{
+				DefaultJSONHeader: []string{`{`},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "null JSON header with prefixed headers",
+			cfg: Config{
+				HeaderPrefix: "X-Override-",
+			},
+			header: func() http.Header {
+				h := http.Header{}
+				h.Set(DefaultJSONHeader, "null")
+				h.Set("X-Override-surge_multiplier", "1.2")
+				return h
+			}(),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "non-object JSON header",
+			cfg:  Config{},
+			header: http.Header{
+				DefaultJSONHeader: []string{`[1, 2]`},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "JSON header with disallowed name",
+			cfg: Config{
+				AllowedNames: []string{"surge_multiplier"},
+			},
+			header: http.Header{
+				DefaultJSONHeader: []string{`{"Surge_multiplier": 1}`},
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			desc: "prefixed headers with allowed names",
+			cfg: Config{
+				HeaderPrefix: "X-Override-",
+				AllowedNames: []string{"surgeMultiplier", "tiers"},
+			},
+			header: func() http.Header {
+				h := http.Header{}
+				h.Set("X-Override-surgeMultiplier", "1.2")
+				h.Add("X-Override-Tiers", "1")
+				h.Add("X-Override-Tiers", "2")
+				h.Set("X-Other", "ignored")
+				return h
+			}(),
+			wantStatus: http.StatusOK,
+			wantSnapshot: map[string]interface{}{
+				"surgeMultiplier": "1.2",
+				"tiers":           []string{"1", "2"},
+			},
+		},
+		{
+			desc: "prefixed headers without allowed names",
+			cfg: Config{
+				HeaderPrefix: "X-Override-",
+			},
+			header: func() http.Header {
+				h := http.Header{}
+				h.Set("X-Override-surge_multiplier", "1.2")
+				return h
+			}(),
+			wantStatus: http.StatusOK,
+			wantSnapshot: map[string]interface{}{
+				"surge_multiplier": "1.2",
+			},
+		},
+		{
+			desc: "header that is exactly the prefix",
+			cfg: Config{
+				HeaderPrefix: "X-Override-",
+			},
+			header: func() http.Header {
+				h := http.Header{}
+				h.Set("X-Override-", "1.2")
+				h.Set("X-Override-surge_multiplier", "1.5")
+				return h
+			}(),
+			wantStatus: http.StatusOK,
+			wantSnapshot: map[string]interface{}{
+				"surge_multiplier": "1.5",
+			},
+		},
+		{
+			desc: "prefixed headers with disallowed name",
+			cfg: Config{
+				HeaderPrefix: "X-Override-",
+				AllowedNames: []string{"tiers"},
+			},
+			header: func() http.Header {
+				h := http.Header{}
+				h.Set("X-Override-surge_multiplier", "1.2")
+				return h
+			}(),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			var actualSnapshot map[string]interface{}
+			handler := New(sc.cfg)(
+				http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						actualSnapshot = dvow.Snapshot(r.Context())
+					},
+				),
+			)
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header = sc.header
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			assert.Equal(t, sc.wantStatus, w.Code)
+			assert.Equal(t, sc.wantSnapshot, actualSnapshot)
+		})
+	}
+}
+
+func TestExtractor_InvalidPayload(t *testing.T) {
+	e := newExtractor(Config{HeaderPrefix: "X-Override-"})
+
+	for _, payload := range []string{"null", "[1, 2]", `"text"`, "{"} {
+		header := http.Header{}
+		header.Set(DefaultJSONHeader, payload)
+		header.Set("X-Override-surge_multiplier", "1.2")
+
+		assert.NotPanics(t, func() {
+			actual, err := e.extract(header)
+			assert.Nil(t, actual, payload)
+			assert.True(t, errors.Is(err, ErrInvalidPayload), payload)
+		})
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	var actual int64
+	var source dvow.Source
+	var errorHandled error
+
+	handler := New(
+		Config{
+			HeaderPrefix: "X-Override-",
+			AllowedNames: []string{"count"},
+			Options:      []dvow.Option{dvow.LenientConversion()},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				errorHandled = err
+				w.WriteHeader(http.StatusUnprocessableEntity)
+			},
+		},
+	)(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				value := dvow.GetOverwrittenValue(r.Context(), "count")
+				actual = value.AsInt()
+				source = dvow.SourceOf(value)
+			},
+		),
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Override-Count", "3")
+
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, int64(3), actual)
+	assert.Equal(t, dvow.SourceHeader, source)
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Override-Other", "3")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.True(t, errors.Is(errorHandled, ErrNameNotAllowed))
+}
+
+func TestNew_OnUsage(t *testing.T) {
+	var reports []dvow.UsageReport
+
+	handler := New(
+		Config{
+			OnUsage: func(r *http.Request, report dvow.UsageReport) {
+				reports = append(reports, report)
+			},
+		},
+	)(
+		http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				dvow.GetOverwrittenValue(r.Context(), "a")
+			},
+		),
+	)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(DefaultJSONHeader, `{"a": 1, "b": 2}`)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(
+		t, []dvow.UsageReport{
+			{
+				Set:    []string{"a", "b"},
+				Read:   []string{"a"},
+				Unused: []string{"b"},
+			},
+			{},
+		}, reports,
+	)
+}
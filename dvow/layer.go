@@ -0,0 +1,189 @@
+package dvow
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Priority decides the precedence of a layer of overwritten variables. Layers with a
+// higher Priority take precedence regardless of the order in which they were added.
+type Priority int
+
+// Common priorities of layers, from lowest to highest.
+const (
+	PriorityStaticConfig Priority = 100
+	PriorityExperiment   Priority = 200
+	PriorityRequest      Priority = 300
+)
+
+// WithOverwrittenLayer returns a new context.Context in which the given overwritten
+// variables are added as a layer with the given Priority. Lookups consult layers from
+// the highest to the lowest Priority, and the most recently added layer first among
+// those with the same Priority, before falling back to the Storage that was in ctx
+// before the first layer was added.
+//
+// The layers of a context chain are kept together even if other overwrites, e.g. via
+// WithOverwrittenVariables, were added between them, so that precedence among layers
+// never depends on the order of the calls.
+func WithOverwrittenLayer(
+	ctx context.Context,
+	overwrittenVariables map[string]interface{},
+	priority Priority,
+	opts ...Option,
+) context.Context {
+	if len(overwrittenVariables) == 0 {
+		return ctx
+	}
+
+	o := newOptions(opts...)
+
+	clone := prepareVariables(overwrittenVariables, o)
+	if len(clone) == 0 {
+		return ctx
+	}
+
+	layer := priorityLayer{
+		priority: priority,
+		storage:  newDynamicOverwritingStorage(nil, clone, o, time.Time{}),
+	}
+
+	current := Ops.ExtractOverwritingStorage(ctx)
+
+	derivedStorage, ok := insertLayer(current, layer)
+	if !ok {
+		derivedStorage = layeredStorage{
+			layers: []priorityLayer{layer},
+			base:   current,
+		}
+	}
+
+	return context.WithValue(ctx, overwritingStorageKey, derivedStorage)
+}
+
+// insertLayer walks the parents of the given Storage to the nearest layeredStorage and
+// returns the given Storage rebuilt on top of a copy of this layeredStorage in which
+// the given layer was inserted. It returns false if there is no such layeredStorage or
+// if a Storage on the way cannot be rebuilt.
+func insertLayer(storage Storage, layer priorityLayer) (Storage, bool) {
+	switch s := storage.(type) {
+	case layeredStorage:
+		return s.withLayer(layer), true
+
+	case dynamicOverwritingStorage:
+		parent, ok := insertLayer(s.parent, layer)
+		if !ok {
+			return nil, false
+		}
+
+		s.parent = parent
+		return s, true
+
+	case chainedStorage:
+		parent, ok := insertLayer(s.parent, layer)
+		if !ok {
+			return nil, false
+		}
+
+		s.parent = parent
+		return s, true
+
+	default:
+		return nil, false
+	}
+}
+
+// withLayer returns a copy of this layeredStorage in which the given layer was inserted.
+func (s layeredStorage) withLayer(layer priorityLayer) layeredStorage {
+	layers := make([]priorityLayer, 0, len(s.layers)+1)
+	layers = append(layers, s.layers...)
+
+	// Keep layers sorted from the highest to the lowest priority, newer layers first
+	idx := sort.Search(
+		len(layers), func(i int) bool {
+			return layers[i].priority <= layer.priority
+		},
+	)
+
+	layers = append(layers, priorityLayer{})
+	copy(layers[idx+1:], layers[idx:])
+	layers[idx] = layer
+
+	return layeredStorage{
+		layers: layers,
+		base:   s.base,
+	}
+}
+
+type priorityLayer struct {
+	priority Priority
+	storage  Storage
+}
+
+// layeredStorage consults its layers in order before falling back to base.
+type layeredStorage struct {
+	layers []priorityLayer
+	base   Storage // from parent context.Context
+}
+
+// Get returns the Value of the variable under this name if it was overwritten
+func (s layeredStorage) Get(name string) Value {
+	value, _ := s.lookup(name)
+	return value
+}
+
+func (s layeredStorage) lookup(name string) (Value, bool) {
+	for _, layer := range s.layers {
+		if value, isMasked := lookup(layer.storage, name); value != nil || isMasked {
+			return value, isMasked
+		}
+	}
+
+	return lookup(s.base, name)
+}
+
+// Keys returns the sorted names of all variables that were overwritten in this
+// Storage, including those inherited from parent Storage.
+func (s layeredStorage) Keys() []string {
+	seen := make(map[string]struct{})
+
+	var keys []string
+	add := func(names []string) {
+		for _, name := range names {
+			if _, ok := seen[name]; !ok {
+				seen[name] = struct{}{}
+				keys = append(keys, name)
+			}
+		}
+	}
+
+	for _, layer := range s.layers {
+		add(keysOf(layer.storage))
+	}
+
+	if s.base != nil {
+		add(keysOf(s.base))
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// metricsHook returns the MetricsHook of the first layer that has one, or that of
+// base if none of the layers has its own MetricsHook.
+func (s layeredStorage) metricsHook() MetricsHook {
+	for _, layer := range s.layers {
+		if p, ok := layer.storage.(metricsHookProvider); ok {
+			if hook := p.metricsHook(); hook != nil {
+				return hook
+			}
+		}
+	}
+
+	if p, ok := s.base.(metricsHookProvider); ok {
+		return p.metricsHook()
+	}
+
+	return nil
+}
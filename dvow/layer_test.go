@@ -0,0 +1,97 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOverwrittenLayer(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "input map is empty/nil",
+			test: func(t *testing.T) {
+				ctx := context.Background()
+
+				actual := WithOverwrittenLayer(ctx, nil, PriorityRequest)
+
+				assert.Equal(t, ctx, actual)
+			},
+		},
+		{
+			desc: "precedence does not depend on call order",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": "base", "base": true})
+				ctx = WithOverwrittenLayer(ctx, map[string]interface{}{"a": "request", "b": "request"}, PriorityRequest)
+				ctx = WithOverwrittenLayer(ctx, map[string]interface{}{"a": "config", "c": "config"}, PriorityStaticConfig)
+				ctx = WithOverwrittenLayer(ctx, map[string]interface{}{"a": "experiment", "b": "experiment"}, PriorityExperiment)
+
+				assert.Equal(t, "request", GetOverwrittenValue(ctx, "a").AsIs())
+				assert.Equal(t, "request", GetOverwrittenValue(ctx, "b").AsIs())
+				assert.Equal(t, "config", GetOverwrittenValue(ctx, "c").AsIs())
+				assert.Equal(t, true, GetOverwrittenValue(ctx, "base").AsIs())
+				assert.Nil(t, GetOverwrittenValue(ctx, "d"))
+				assert.Equal(t, []string{"a", "b", "base", "c"}, ExtractOverwritingStorageV2(ctx).Keys())
+			},
+		},
+		{
+			desc: "precedence does not depend on overwrites added between layers",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenLayer(context.Background(), map[string]interface{}{"a": "request"}, PriorityRequest)
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"b": "overwrite"})
+				ctx = WithMutableOverwrittenVariables(ctx, map[string]interface{}{"c": "mutable"})
+				ctx = WithOverwrittenLayer(ctx, map[string]interface{}{"a": "config", "d": "config"}, PriorityStaticConfig)
+
+				assert.Equal(t, "request", GetOverwrittenValue(ctx, "a").AsIs())
+				assert.Equal(t, "overwrite", GetOverwrittenValue(ctx, "b").AsIs())
+				assert.Equal(t, "mutable", GetOverwrittenValue(ctx, "c").AsIs())
+				assert.Equal(t, "config", GetOverwrittenValue(ctx, "d").AsIs())
+				assert.Equal(t, []string{"a", "b", "c", "d"}, ExtractOverwritingStorageV2(ctx).Keys())
+
+				assert.NoError(t, SetOverwrittenValue(ctx, "c", "changed"))
+				assert.Equal(t, "changed", GetOverwrittenValue(ctx, "c").AsIs(), "mutable storage must be shared")
+			},
+		},
+		{
+			desc: "overwrites added between layers keep precedence over them",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenLayer(context.Background(), map[string]interface{}{"a": 1}, PriorityStaticConfig)
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"a": 2})
+				child := WithOverwrittenLayer(ctx, map[string]interface{}{"a": 3}, PriorityRequest)
+
+				assert.Equal(t, 2, GetOverwrittenValue(child, "a").AsIs())
+				assert.Equal(t, 2, GetOverwrittenValue(ctx, "a").AsIs(), "parent context must not be affected")
+			},
+		},
+		{
+			desc: "newer layer wins among the same priority",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenLayer(context.Background(), map[string]interface{}{"a": 1}, PriorityExperiment)
+				child := WithOverwrittenLayer(ctx, map[string]interface{}{"a": 2}, PriorityExperiment)
+
+				assert.Equal(t, 2, GetOverwrittenValue(child, "a").AsIs())
+				assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs(), "parent context must not be affected")
+			},
+		},
+		{
+			desc: "nested overwrites take precedence over all layers",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenLayer(context.Background(), map[string]interface{}{"a": 1}, PriorityRequest)
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"a": 2})
+
+				assert.Equal(t, 2, GetOverwrittenValue(ctx, "a").AsIs())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
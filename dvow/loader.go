@@ -0,0 +1,86 @@
+package dvow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithOverwrittenVariablesFromJSON parses the given JSON object and returns a new
+// context.Context that holds a reference to its fields as overwritten variables.
+// Numbers are preserved as json.Number so that integers do not lose precision by
+// being treated as float64. The variables are recorded as coming from SourceConfig
+// unless another Source is given.
+func WithOverwrittenVariablesFromJSON(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var overwrittenVariables map[string]interface{}
+	if err := decoder.Decode(&overwrittenVariables); err != nil {
+		return nil, err
+	}
+
+	opts = append([]Option{WithSource(SourceConfig)}, opts...)
+
+	return Ops.WithOverwrittenVariables(ctx, overwrittenVariables, opts...), nil
+}
+
+// WithOverwrittenVariablesFromYAML parses the given YAML document and returns a new
+// context.Context that holds a reference to its fields as overwritten variables.
+// Nested mappings are flattened into dot-paths, e.g.
+//
+//	pricing:
+//	  surge:
+//	    multiplier: 1.5
+//
+// is loaded as a variable named "pricing.surge.multiplier". This allows teams to
+// define per-environment overwrites in config files. The variables are recorded as
+// coming from SourceConfig unless another Source is given.
+func WithOverwrittenVariablesFromYAML(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+
+	overwrittenVariables := make(map[string]interface{}, len(document))
+	flattenVariables("", document, overwrittenVariables)
+
+	opts = append([]Option{WithSource(SourceConfig)}, opts...)
+
+	return Ops.WithOverwrittenVariables(ctx, overwrittenVariables, opts...), nil
+}
+
+// flattenVariables puts all leaves of the given nested mapping into result using
+// their dot-paths as names.
+func flattenVariables(prefix string, mapping map[string]interface{}, result map[string]interface{}) {
+	for key, value := range mapping {
+		name := key
+		if prefix != "" {
+			name = prefix + pathSeparator + key
+		}
+
+		switch nested := value.(type) {
+		case map[string]interface{}:
+			if len(nested) > 0 {
+				flattenVariables(name, nested, result)
+				continue
+			}
+
+		case map[interface{}]interface{}:
+			if len(nested) > 0 {
+				converted := make(map[string]interface{}, len(nested))
+				for k, v := range nested {
+					converted[fmt.Sprint(k)] = v
+				}
+
+				flattenVariables(name, converted, result)
+				continue
+			}
+		}
+
+		result[name] = value
+	}
+}
@@ -0,0 +1,130 @@
+package dvow
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOverwrittenVariablesFromJSON(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "valid JSON object",
+			test: func(t *testing.T) {
+				ctx, err := WithOverwrittenVariablesFromJSON(
+					context.Background(), []byte(`{"id": 9007199254740993, "ratio": 1.5, "name": "test", "list": [1, 2]}`),
+				)
+				assert.Nil(t, err)
+
+				assert.Equal(t, json.Number("9007199254740993"), GetOverwrittenValue(ctx, "id").AsIs())
+				assert.Equal(t, int64(9007199254740993), GetOverwrittenValue(ctx, "id").AsInt())
+				assert.Equal(t, 1.5, GetOverwrittenValue(ctx, "ratio").AsFloat())
+				assert.Equal(t, int64(1), GetOverwrittenValue(ctx, "ratio").AsInt())
+				assert.Equal(t, "test", GetOverwrittenValue(ctx, "name").AsString())
+				assert.Equal(t, []int64{1, 2}, GetOverwrittenValue(ctx, "list").AsIntSlice())
+			},
+		},
+		{
+			desc: "default source",
+			test: func(t *testing.T) {
+				ctx, err := WithOverwrittenVariablesFromJSON(context.Background(), []byte(`{"a": 1}`))
+				assert.Nil(t, err)
+				assert.Equal(t, SourceConfig, SourceOf(GetOverwrittenValue(ctx, "a")))
+
+				ctx, err = WithOverwrittenVariablesFromJSON(context.Background(), []byte(`{"a": 1}`), WithSource(SourceRemote))
+				assert.Nil(t, err)
+				assert.Equal(t, SourceRemote, SourceOf(GetOverwrittenValue(ctx, "a")))
+			},
+		},
+		{
+			desc: "empty JSON object",
+			test: func(t *testing.T) {
+				ctx, err := WithOverwrittenVariablesFromJSON(context.Background(), []byte(`{}`))
+				assert.Nil(t, err)
+				assert.Nil(t, ExtractOverwritingStorage(ctx))
+			},
+		},
+		{
+			desc: "not a JSON object",
+			test: func(t *testing.T) {
+				ctx, err := WithOverwrittenVariablesFromJSON(context.Background(), []byte(`[1]`))
+				assert.Nil(t, ctx)
+				assert.NotNil(t, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestWithOverwrittenVariablesFromYAML(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "nested mappings are flattened",
+			test: func(t *testing.T) {
+				data := []byte(`
+pricing:
+  surge:
+    multiplier: 1.5
+    enabled: true
+  tiers: [1, 2]
+  empty: {}
+timeout: 1500ms
+1: one
+`)
+
+				ctx, err := WithOverwrittenVariablesFromYAML(context.Background(), data)
+				assert.Nil(t, err)
+
+				assert.Equal(
+					t, map[string]interface{}{
+						"pricing.surge.multiplier": 1.5,
+						"pricing.surge.enabled":    true,
+						"pricing.tiers":            []interface{}{1, 2},
+						"pricing.empty":            map[string]interface{}{},
+						"timeout":                  "1500ms",
+						"1":                        "one",
+					}, Snapshot(ctx),
+				)
+			},
+		},
+		{
+			desc: "non-string keys in nested mappings",
+			test: func(t *testing.T) {
+				ctx, err := WithOverwrittenVariablesFromYAML(context.Background(), []byte("tiers:\n  1: 1.5\n  true: 2\n"))
+				assert.Nil(t, err)
+
+				assert.Equal(t, 1.5, GetOverwrittenValue(ctx, "tiers.1").AsFloat())
+				assert.Equal(t, int64(2), GetOverwrittenValue(ctx, "tiers.true").AsInt())
+			},
+		},
+		{
+			desc: "invalid YAML",
+			test: func(t *testing.T) {
+				ctx, err := WithOverwrittenVariablesFromYAML(context.Background(), []byte("- a"))
+				assert.Nil(t, ctx)
+				assert.NotNil(t, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package dvow
+
+// MergeStorages returns a single Storage combining the given ones. Storages that come
+// later in the list take precedence over those that come earlier. Nil storages are
+// ignored and nil is returned if none of the given storages is non-nil.
+//
+// This comes in handy when combining the overwrites carried on a message with those
+// configured on the consumer process, e.g.
+//
+//	storage := MergeStorages(processStorage, messageStorage)
+func MergeStorages(storages ...Storage) Storage {
+	var result Storage
+	for _, storage := range storages {
+		if storage == nil {
+			continue
+		}
+
+		if result == nil {
+			result = storage
+			continue
+		}
+
+		result = chainedStorage{
+			current: storage,
+			parent:  result,
+		}
+	}
+
+	return result
+}
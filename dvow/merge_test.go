@@ -0,0 +1,65 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeStorages(t *testing.T) {
+	first := dynamicOverwritingStorage{
+		variables: map[string]interface{}{"a": 1, "b": 1},
+	}
+
+	second := dynamicOverwritingStorage{
+		variables: map[string]interface{}{"b": 2, "c": 2},
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no storages",
+			test: func(t *testing.T) {
+				assert.Nil(t, MergeStorages())
+				assert.Nil(t, MergeStorages(nil, nil))
+			},
+		},
+		{
+			desc: "single storage",
+			test: func(t *testing.T) {
+				assert.Equal(t, first, MergeStorages(nil, first, nil))
+			},
+		},
+		{
+			desc: "later storages take precedence",
+			test: func(t *testing.T) {
+				merged := MergeStorages(first, nil, second)
+
+				assert.Equal(t, 1, merged.Get("a").AsIs())
+				assert.Equal(t, 2, merged.Get("b").AsIs())
+				assert.Equal(t, 2, merged.Get("c").AsIs())
+				assert.Nil(t, merged.Get("d"))
+				assert.Equal(t, []string{"a", "b", "c"}, UpgradeStorage(merged).Keys())
+			},
+		},
+		{
+			desc: "attach to context",
+			test: func(t *testing.T) {
+				ctx := WithOverwritingStorage(context.Background(), MergeStorages(second, first))
+
+				assert.Equal(t, 1, GetOverwrittenValue(ctx, "b").AsIs())
+				assert.Equal(t, context.Background(), WithOverwritingStorage(context.Background(), nil))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
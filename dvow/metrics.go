@@ -0,0 +1,115 @@
+package dvow
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/jamestrandung/go-context/instrument"
+)
+
+// Names of the metrics & attributes reported to an instrument.Instrumentation.
+const (
+	LookupsMetricName = "dvow.lookups"
+	VariableAttr      = "dvow.variable"
+	OverwrittenAttr   = "dvow.overwritten"
+)
+
+// MetricsHook receives the outcome of every lookup of an overwritten variable so that
+// experiment coverage can be measured and dead overwrites can be detected.
+//
+//go:generate mockery --name MetricsHook --case underscore --inpkg
+type MetricsHook interface {
+	// OnLookup is called after looking up the variable under this name with whether
+	// it was overwritten.
+	OnLookup(name string, overwritten bool)
+}
+
+// MetricsHookFunc is an adapter to allow the use of ordinary functions as MetricsHook.
+type MetricsHookFunc func(name string, overwritten bool)
+
+// OnLookup calls f(name, overwritten).
+func (f MetricsHookFunc) OnLookup(name string, overwritten bool) {
+	f(name, overwritten)
+}
+
+type metricsHookHolder struct {
+	hook MetricsHook
+}
+
+var globalMetricsHook atomic.Value
+
+// SetMetricsHook sets the MetricsHook used for lookups in contexts whose Storage does
+// not have its own MetricsHook. Passing nil removes the global MetricsHook, in which
+// case lookups are counted using the default instrument.Instrumentation, if any.
+func SetMetricsHook(hook MetricsHook) {
+	globalMetricsHook.Store(metricsHookHolder{hook: hook})
+}
+
+// WithMetricsHook makes lookups in the storage and its descendants report to the given
+// MetricsHook instead of the global one.
+func WithMetricsHook(hook MetricsHook) Option {
+	return func(o *options) {
+		o.metricsHook = hook
+	}
+}
+
+// WithInstrumentation makes lookups in the storage and its descendants count towards
+// the LookupsMetricName counter of the given Instrumentation. It replaces the MetricsHook
+// given to WithMetricsHook, if any.
+func WithInstrumentation(i instrument.Instrumentation) Option {
+	return func(o *options) {
+		o.metricsHook = instrumentationHook{
+			instrumentation: i,
+		}
+	}
+}
+
+// instrumentationHook is a MetricsHook counting lookups using an Instrumentation.
+type instrumentationHook struct {
+	instrumentation instrument.Instrumentation
+}
+
+// OnLookup increments the LookupsMetricName counter.
+func (h instrumentationHook) OnLookup(name string, overwritten bool) {
+	h.instrumentation.Count(
+		context.Background(),
+		LookupsMetricName,
+		1,
+		instrument.Attr{Key: VariableAttr, Value: name},
+		instrument.Attr{Key: OverwrittenAttr, Value: overwritten},
+	)
+}
+
+// metricsHookProvider is implemented by Storage that carry their own MetricsHook.
+type metricsHookProvider interface {
+	metricsHook() MetricsHook
+}
+
+// reportLookup reports the outcome of a lookup to the MetricsHook of the given Storage
+// if any, otherwise to the global one.
+func reportLookup(storage Storage, name string, overwritten bool) {
+	if hook := findMetricsHook(storage); hook != nil {
+		hook.OnLookup(name, overwritten)
+	}
+}
+
+func findMetricsHook(storage Storage) MetricsHook {
+	if p, ok := storage.(metricsHookProvider); ok {
+		if hook := p.metricsHook(); hook != nil {
+			return hook
+		}
+	}
+
+	holder, _ := globalMetricsHook.Load().(metricsHookHolder)
+	if holder.hook != nil {
+		return holder.hook
+	}
+
+	if i := instrument.Default(); !instrument.IsNoop(i) {
+		return instrumentationHook{
+			instrumentation: i,
+		}
+	}
+
+	return nil
+}
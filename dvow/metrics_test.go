@@ -0,0 +1,128 @@
+package dvow
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/jamestrandung/go-context/instrument"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type countingInstrumentation struct {
+	instrument.Noop
+	counts []string
+}
+
+func (i *countingInstrumentation) Count(ctx context.Context, name string, delta int64, attrs ...instrument.Attr) {
+	i.counts = append(i.counts, fmt.Sprintf("%v%v", name, attrs))
+}
+
+func TestMetricsHook(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no hook",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+				assert.NotPanics(t, func() {
+					GetOverwrittenValue(ctx, "a")
+				})
+			},
+		},
+		{
+			desc: "global hook",
+			test: func(t *testing.T) {
+				hookMock := &MockMetricsHook{}
+				hookMock.On("OnLookup", "a", true).Once()
+				hookMock.On("OnLookup", "b", false).Twice()
+
+				SetMetricsHook(hookMock)
+				defer SetMetricsHook(nil)
+
+				GetOverwrittenValue(context.Background(), "b")
+
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+				GetOverwrittenValue(ctx, "a")
+				GetOverwrittenValue(ctx, "b")
+
+				mock.AssertExpectationsForObjects(t, hookMock)
+			},
+		},
+		{
+			desc: "per-storage hook takes precedence and is inherited",
+			test: func(t *testing.T) {
+				globalHookMock := &MockMetricsHook{}
+
+				SetMetricsHook(globalHookMock)
+				defer SetMetricsHook(nil)
+
+				var lookups []string
+				hook := MetricsHookFunc(func(name string, overwritten bool) {
+					lookups = append(lookups, name)
+				})
+
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1}, WithMetricsHook(hook))
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"b": 1})
+				ctx = WithoutOverwrittenVariables(ctx, "a")
+
+				GetOverwrittenValue(ctx, "a")
+				GetOverwrittenValue(ctx, "b")
+
+				assert.Equal(t, []string{"a", "b"}, lookups)
+				mock.AssertExpectationsForObjects(t, globalHookMock)
+			},
+		},
+		{
+			desc: "instrumentation",
+			test: func(t *testing.T) {
+				i := &countingInstrumentation{}
+
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1}, WithInstrumentation(i))
+
+				GetOverwrittenValue(ctx, "a")
+				GetOverwrittenValue(ctx, "b")
+
+				assert.Equal(
+					t, []string{
+						"dvow.lookups[{dvow.variable a} {dvow.overwritten true}]",
+						"dvow.lookups[{dvow.variable b} {dvow.overwritten false}]",
+					}, i.counts,
+				)
+			},
+		},
+		{
+			desc: "default instrumentation is used without any hook",
+			test: func(t *testing.T) {
+				i := &countingInstrumentation{}
+
+				instrument.SetDefault(i)
+				defer instrument.SetDefault(nil)
+
+				GetOverwrittenValue(context.Background(), "a")
+				assert.Len(t, i.counts, 1)
+
+				hookMock := &MockMetricsHook{}
+				hookMock.On("OnLookup", "a", false).Once()
+
+				SetMetricsHook(hookMock)
+				defer SetMetricsHook(nil)
+
+				GetOverwrittenValue(context.Background(), "a")
+				assert.Len(t, i.counts, 1)
+				mock.AssertExpectationsForObjects(t, hookMock)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // MockIOverwritingOps is an autogenerated mock type for the IOverwritingOps type
@@ -44,3 +46,231 @@ func (_m *MockIOverwritingOps) GetOverwrittenValue(ctx context.Context, name str
 
 	return r0
 }
+
+// WithMutableOverwrittenVariables provides a mock function with given fields: ctx, overwrittenVariables, opts
+func (_m *MockIOverwritingOps) WithMutableOverwrittenVariables(ctx context.Context, overwrittenVariables map[string]interface{}, opts ...Option) context.Context {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, overwrittenVariables)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, ...Option) context.Context); ok {
+		r0 = rf(ctx, overwrittenVariables, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
+
+// WithOverwrittenLayer provides a mock function with given fields: ctx, overwrittenVariables, priority, opts
+func (_m *MockIOverwritingOps) WithOverwrittenLayer(ctx context.Context, overwrittenVariables map[string]interface{}, priority Priority, opts ...Option) context.Context {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, overwrittenVariables, priority)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, Priority, ...Option) context.Context); ok {
+		r0 = rf(ctx, overwrittenVariables, priority, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
+
+// WithOverwrittenVariables provides a mock function with given fields: ctx, overwrittenVariables, opts
+func (_m *MockIOverwritingOps) WithOverwrittenVariables(ctx context.Context, overwrittenVariables map[string]interface{}, opts ...Option) context.Context {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, overwrittenVariables)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, ...Option) context.Context); ok {
+		r0 = rf(ctx, overwrittenVariables, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
+
+// WithOverwrittenVariablesFromJSON provides a mock function with given fields: ctx, data, opts
+func (_m *MockIOverwritingOps) WithOverwrittenVariablesFromJSON(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, data)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, ...Option) context.Context); ok {
+		r0 = rf(ctx, data, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, ...Option) error); ok {
+		r1 = rf(ctx, data, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithOverwrittenVariablesFromWire provides a mock function with given fields: ctx, data, opts
+func (_m *MockIOverwritingOps) WithOverwrittenVariablesFromWire(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, data)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, ...Option) context.Context); ok {
+		r0 = rf(ctx, data, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, ...Option) error); ok {
+		r1 = rf(ctx, data, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithOverwrittenVariablesFromYAML provides a mock function with given fields: ctx, data, opts
+func (_m *MockIOverwritingOps) WithOverwrittenVariablesFromYAML(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, data)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, ...Option) context.Context); ok {
+		r0 = rf(ctx, data, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, ...Option) error); ok {
+		r1 = rf(ctx, data, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithOverwrittenVariablesTTL provides a mock function with given fields: ctx, overwrittenVariables, ttl, opts
+func (_m *MockIOverwritingOps) WithOverwrittenVariablesTTL(ctx context.Context, overwrittenVariables map[string]interface{}, ttl time.Duration, opts ...Option) context.Context {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, overwrittenVariables, ttl)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, time.Duration, ...Option) context.Context); ok {
+		r0 = rf(ctx, overwrittenVariables, ttl, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
+
+// WithTargeting provides a mock function with given fields: ctx, extractor, targetings, opts
+func (_m *MockIOverwritingOps) WithTargeting(ctx context.Context, extractor AttributesExtractor, targetings map[string]Targeting, opts ...Option) context.Context {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, extractor, targetings)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, AttributesExtractor, map[string]Targeting, ...Option) context.Context); ok {
+		r0 = rf(ctx, extractor, targetings, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
+
+// WithoutOverwrittenVariables provides a mock function with given fields: ctx, names
+func (_m *MockIOverwritingOps) WithoutOverwrittenVariables(ctx context.Context, names ...string) context.Context {
+	_va := make([]interface{}, len(names))
+	for _i := range names {
+		_va[_i] = names[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) context.Context); ok {
+		r0 = rf(ctx, names...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(context.Context)
+		}
+	}
+
+	return r0
+}
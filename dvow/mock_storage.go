@@ -24,3 +24,4 @@ func (_m *MockStorage) Get(name string) Value {
 
 	return r0
 }
+
@@ -2,7 +2,13 @@
 
 package dvow
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	reflect "reflect"
+
+	time "time"
+)
 
 // MockValue is an autogenerated mock type for the Value type
 type MockValue struct {
@@ -23,6 +29,62 @@ func (_m *MockValue) AsBool() bool {
 	return r0
 }
 
+// AsBoolE provides a mock function with given fields:
+func (_m *MockValue) AsBoolE() (bool, error) {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AsDuration provides a mock function with given fields:
+func (_m *MockValue) AsDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// AsDurationE provides a mock function with given fields:
+func (_m *MockValue) AsDurationE() (time.Duration, error) {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // AsFloat provides a mock function with given fields:
 func (_m *MockValue) AsFloat() float64 {
 	ret := _m.Called()
@@ -37,6 +99,43 @@ func (_m *MockValue) AsFloat() float64 {
 	return r0
 }
 
+// AsFloatE provides a mock function with given fields:
+func (_m *MockValue) AsFloatE() (float64, error) {
+	ret := _m.Called()
+
+	var r0 float64
+	if rf, ok := ret.Get(0).(func() float64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(float64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AsFloatSlice provides a mock function with given fields:
+func (_m *MockValue) AsFloatSlice() []float64 {
+	ret := _m.Called()
+
+	var r0 []float64
+	if rf, ok := ret.Get(0).(func() []float64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]float64)
+		}
+	}
+
+	return r0
+}
+
 // AsInt provides a mock function with given fields:
 func (_m *MockValue) AsInt() int64 {
 	ret := _m.Called()
@@ -51,6 +150,43 @@ func (_m *MockValue) AsInt() int64 {
 	return r0
 }
 
+// AsIntE provides a mock function with given fields:
+func (_m *MockValue) AsIntE() (int64, error) {
+	ret := _m.Called()
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func() int64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AsIntSlice provides a mock function with given fields:
+func (_m *MockValue) AsIntSlice() []int64 {
+	ret := _m.Called()
+
+	var r0 []int64
+	if rf, ok := ret.Get(0).(func() []int64); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	return r0
+}
+
 // AsIs provides a mock function with given fields:
 func (_m *MockValue) AsIs() interface{} {
 	ret := _m.Called()
@@ -67,6 +203,22 @@ func (_m *MockValue) AsIs() interface{} {
 	return r0
 }
 
+// AsMap provides a mock function with given fields:
+func (_m *MockValue) AsMap() map[string]interface{} {
+	ret := _m.Called()
+
+	var r0 map[string]interface{}
+	if rf, ok := ret.Get(0).(func() map[string]interface{}); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]interface{})
+		}
+	}
+
+	return r0
+}
+
 // AsString provides a mock function with given fields:
 func (_m *MockValue) AsString() string {
 	ret := _m.Called()
@@ -81,6 +233,195 @@ func (_m *MockValue) AsString() string {
 	return r0
 }
 
+// AsStringE provides a mock function with given fields:
+func (_m *MockValue) AsStringE() (string, error) {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AsStringSlice provides a mock function with given fields:
+func (_m *MockValue) AsStringSlice() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// AsTime provides a mock function with given fields: layouts
+func (_m *MockValue) AsTime(layouts ...string) time.Time {
+	_va := make([]interface{}, len(layouts))
+	for _i := range layouts {
+		_va[_i] = layouts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(...string) time.Time); ok {
+		r0 = rf(layouts...)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	return r0
+}
+
+// AsTimeE provides a mock function with given fields: layouts
+func (_m *MockValue) AsTimeE(layouts ...string) (time.Time, error) {
+	_va := make([]interface{}, len(layouts))
+	for _i := range layouts {
+		_va[_i] = layouts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 time.Time
+	if rf, ok := ret.Get(0).(func(...string) time.Time); ok {
+		r0 = rf(layouts...)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(...string) error); ok {
+		r1 = rf(layouts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AsUint provides a mock function with given fields:
+func (_m *MockValue) AsUint() uint64 {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// AsUintE provides a mock function with given fields:
+func (_m *MockValue) AsUintE() (uint64, error) {
+	ret := _m.Called()
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsNil provides a mock function with given fields:
+func (_m *MockValue) IsNil() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsPresent provides a mock function with given fields:
+func (_m *MockValue) IsPresent() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsZero provides a mock function with given fields:
+func (_m *MockValue) IsZero() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// Kind provides a mock function with given fields:
+func (_m *MockValue) Kind() reflect.Kind {
+	ret := _m.Called()
+
+	var r0 reflect.Kind
+	if rf, ok := ret.Get(0).(func() reflect.Kind); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(reflect.Kind)
+	}
+
+	return r0
+}
+
+// TypeName provides a mock function with given fields:
+func (_m *MockValue) TypeName() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // Unmarshal provides a mock function with given fields: t
 func (_m *MockValue) Unmarshal(t interface{}) error {
 	ret := _m.Called(t)
@@ -0,0 +1,63 @@
+package dvow
+
+import (
+	"fmt"
+
+	"github.com/jamestrandung/go-context/helper"
+	"github.com/pkg/errors"
+)
+
+// DetectMutations is a debug mode that hashes overwritten values when they are stored
+// and verifies them again on every lookup, so that consumers modifying supposedly
+// read-only values such as maps or slices in place get caught. Once a mutation is
+// detected, the given onMutation is invoked with the name of the mutated variable on
+// every lookup of this variable. If onMutation is nil, lookups panic with an error
+// wrapping ErrMutatedValue instead.
+//
+// Hashing is expensive, hence this mode should only be enabled in development and
+// tests. Values that cannot be hashed, e.g. functions or channels, are not verified.
+// Use DeepCopyValues to protect the stored values from changes made by the caller to
+// the values it passed in, which are reported by this mode as well.
+func DetectMutations(onMutation func(name string)) Option {
+	return func(o *options) {
+		o.detectMutations = true
+		o.onMutation = onMutation
+	}
+}
+
+// checksumVariables returns the hashes of all given variables that can be hashed.
+func checksumVariables(variables map[string]interface{}) map[string]uint64 {
+	checksums := make(map[string]uint64, len(variables))
+	for name, value := range variables {
+		if checksum, ok := checksumValue(value); ok {
+			checksums[name] = checksum
+		}
+	}
+
+	return checksums
+}
+
+func checksumValue(value interface{}) (uint64, bool) {
+	hash, err := helper.Hash(value)
+	return hash, err == nil
+}
+
+// verifyChecksum reports a mutation if the given value of the variable under this name
+// no longer matches the hash taken when it was stored.
+func (o options) verifyChecksum(checksums map[string]uint64, name string, value interface{}) {
+	expected, ok := checksums[name]
+	if !ok {
+		return
+	}
+
+	if actual, _ := checksumValue(value); actual == expected {
+		return
+	}
+
+	if o.onMutation != nil {
+		o.onMutation(name)
+		return
+	}
+
+	panic(errors.Wrap(ErrMutatedValue, fmt.Sprintf("variable %s", name)))
+}
@@ -0,0 +1,105 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectMutations(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "unmodified values",
+			test: func(t *testing.T) {
+				var mutated []string
+
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"map":   map[string]interface{}{"a": 1},
+						"slice": []int{1, 2},
+						"func":  func() {},
+					}, DetectMutations(func(name string) { mutated = append(mutated, name) }),
+				)
+
+				GetOverwrittenValue(ctx, "map").AsMap()["a"] = 1
+				GetOverwrittenValue(ctx, "slice")
+				GetOverwrittenValue(ctx, "func")
+
+				assert.Empty(t, mutated)
+			},
+		},
+		{
+			desc: "mutated values are reported",
+			test: func(t *testing.T) {
+				var mutated []string
+
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"map":   map[string]interface{}{"a": 1},
+						"slice": []int{1, 2},
+					}, DetectMutations(func(name string) { mutated = append(mutated, name) }),
+				)
+
+				GetOverwrittenValue(ctx, "map").AsMap()["a"] = 2
+				GetOverwrittenValue(ctx, "slice").AsIs().([]int)[0] = 2
+				assert.Empty(t, mutated)
+
+				GetOverwrittenValue(ctx, "map")
+				GetOverwrittenValue(ctx, "slice")
+				assert.Equal(t, []string{"map", "slice"}, mutated)
+			},
+		},
+		{
+			desc: "mutated values panic without handler",
+			test: func(t *testing.T) {
+				m := map[string]interface{}{"a": 1}
+
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"map": m,
+					}, DetectMutations(nil),
+				)
+
+				m["a"] = 2
+
+				assert.PanicsWithError(
+					t, "variable map: "+ErrMutatedValue.Error(), func() {
+						GetOverwrittenValue(ctx, "map")
+					},
+				)
+			},
+		},
+		{
+			desc: "verified after many derived contexts",
+			test: func(t *testing.T) {
+				var mutated []string
+
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"slice": []int{1, 2},
+					}, DetectMutations(func(name string) { mutated = append(mutated, name) }),
+				)
+
+				for i := 0; i < maxChainDepth*2; i++ {
+					ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"other": i})
+				}
+
+				GetOverwrittenValue(ctx, "slice").AsIs().([]int)[0] = 2
+				GetOverwrittenValue(ctx, "slice")
+
+				assert.Equal(t, []string{"slice"}, mutated)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,208 @@
+package dvow
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// The bounds of int64 and uint64 as float64. Both are powers of 2, hence exact.
+const (
+	minInt64AsFloat  = -(1 << 63)
+	maxInt64AsFloat  = 1 << 63
+	maxUint64AsFloat = 1 << 64
+)
+
+// convertInt converts the number v to int64. It returns false if v is not a number.
+// Otherwise, the error is ErrOverflow if v is outside the range of int64, in which case
+// the result is 0, or ErrPrecisionLoss if v has a fractional part that was truncated.
+func convertInt(v interface{}) (int64, bool, error) {
+	switch casted := v.(type) {
+	case int:
+		return int64(casted), true, nil
+	case int8:
+		return int64(casted), true, nil
+	case int16:
+		return int64(casted), true, nil
+	case int32:
+		return int64(casted), true, nil
+	case int64:
+		return casted, true, nil
+	case uint, uint8, uint16, uint32, uint64, uintptr:
+		result, _, _ := convertUint(casted)
+		if result > math.MaxInt64 {
+			return 0, true, ErrOverflow
+		}
+
+		return int64(result), true, nil
+	case float32:
+		return floatToInt(float64(casted))
+	case float64:
+		return floatToInt(casted)
+	case json.Number:
+		if result, err := casted.Int64(); err == nil {
+			return result, true, nil
+		}
+
+		result, err := casted.Float64()
+		if err != nil {
+			return 0, false, nil
+		}
+
+		return floatToInt(result)
+	default:
+		return 0, false, nil
+	}
+}
+
+// convertUint converts the number v to uint64. It returns false if v is not a number.
+// Otherwise, the error is ErrOverflow if v is outside the range of uint64, including
+// negative numbers, in which case the result is 0, or ErrPrecisionLoss if v has a
+// fractional part that was truncated.
+func convertUint(v interface{}) (uint64, bool, error) {
+	switch casted := v.(type) {
+	case uint:
+		return uint64(casted), true, nil
+	case uint8:
+		return uint64(casted), true, nil
+	case uint16:
+		return uint64(casted), true, nil
+	case uint32:
+		return uint64(casted), true, nil
+	case uint64:
+		return casted, true, nil
+	case uintptr:
+		return uint64(casted), true, nil
+	case float32:
+		return floatToUint(float64(casted))
+	case float64:
+		return floatToUint(casted)
+	case json.Number:
+		if result, err := strconv.ParseUint(string(casted), 10, 64); err == nil {
+			return result, true, nil
+		}
+
+		result, err := casted.Float64()
+		if err != nil {
+			return 0, false, nil
+		}
+
+		return floatToUint(result)
+	}
+
+	result, ok, err := convertInt(v)
+	if !ok || err != nil {
+		return 0, ok, err
+	}
+
+	if result < 0 {
+		return 0, true, ErrOverflow
+	}
+
+	return uint64(result), true, nil
+}
+
+// convertFloat converts the number v to float64. It returns false if v is not a number.
+// Otherwise, the error is ErrPrecisionLoss if v is an integer too large to be represented
+// exactly, in which case the result is the nearest float64.
+func convertFloat(v interface{}) (float64, bool, error) {
+	switch casted := v.(type) {
+	case float32:
+		return float64(casted), true, nil
+	case float64:
+		return casted, true, nil
+	case json.Number:
+		if result, err := casted.Int64(); err == nil {
+			return convertFloat(result)
+		}
+
+		if result, err := strconv.ParseUint(string(casted), 10, 64); err == nil {
+			return convertFloat(result)
+		}
+
+		result, err := casted.Float64()
+		return result, err == nil, nil
+	case uint, uint8, uint16, uint32, uint64, uintptr:
+		integer, _, _ := convertUint(casted)
+
+		result := float64(integer)
+		if result >= maxUint64AsFloat || uint64(result) != integer {
+			return result, true, ErrPrecisionLoss
+		}
+
+		return result, true, nil
+	}
+
+	integer, ok, _ := convertInt(v)
+	if !ok {
+		return 0, false, nil
+	}
+
+	result := float64(integer)
+	if result >= maxInt64AsFloat || int64(result) != integer {
+		return result, true, ErrPrecisionLoss
+	}
+
+	return result, true, nil
+}
+
+func floatToInt(f float64) (int64, bool, error) {
+	if math.IsNaN(f) || f < minInt64AsFloat || f >= maxInt64AsFloat {
+		return 0, true, ErrOverflow
+	}
+
+	result := int64(f)
+	if float64(result) != f {
+		return result, true, ErrPrecisionLoss
+	}
+
+	return result, true, nil
+}
+
+func floatToUint(f float64) (uint64, bool, error) {
+	if math.IsNaN(f) || f < 0 || f >= maxUint64AsFloat {
+		return 0, true, ErrOverflow
+	}
+
+	result := uint64(f)
+	if float64(result) != f {
+		return result, true, ErrPrecisionLoss
+	}
+
+	return result, true, nil
+}
+
+// convertNumber converts the number v to the given numeric type. It returns false if
+// v cannot be represented by this type without overflowing or losing precision.
+func convertNumber(v interface{}, targetType reflect.Type) (reflect.Value, bool) {
+	result := reflect.New(targetType).Elem()
+
+	switch targetType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		converted, ok, err := convertInt(v)
+		if !ok || err != nil || result.OverflowInt(converted) {
+			return reflect.Value{}, false
+		}
+
+		result.SetInt(converted)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		converted, ok, err := convertUint(v)
+		if !ok || err != nil || result.OverflowUint(converted) {
+			return reflect.Value{}, false
+		}
+
+		result.SetUint(converted)
+	case reflect.Float32, reflect.Float64:
+		converted, ok, err := convertFloat(v)
+		if !ok || err != nil || result.OverflowFloat(converted) {
+			return reflect.Value{}, false
+		}
+
+		result.SetFloat(converted)
+	default:
+		return reflect.Value{}, false
+	}
+
+	return result, true
+}
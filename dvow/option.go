@@ -0,0 +1,67 @@
+package dvow
+
+// Option configures how overwritten variables are stored and exposed.
+type Option func(*options)
+
+type options struct {
+	// lenient indicates whether scalar accessors should parse string representations.
+	lenient bool
+	// deepCopy indicates whether values should be deep-copied into the storage.
+	deepCopy bool
+	// source is where the overwritten variables came from.
+	source Source
+	// expressions evaluates overwritten values of type Expression, if not nil.
+	expressions *expressionConfig
+	// metricsHook receives the outcome of lookups, if not nil.
+	metricsHook MetricsHook
+	// schema validates overwritten variables before they are stored, if not nil.
+	schema *Schema
+	// validationMode decides what happens to invalid overwritten variables.
+	validationMode ValidationMode
+	// reportViolations receives the violations found by schema, if not nil.
+	reportViolations func([]Violation)
+	// detectMutations indicates whether stored values should be verified on lookups.
+	detectMutations bool
+	// onMutation receives the names of mutated variables, if not nil.
+	onMutation func(name string)
+}
+
+// wrap returns a Value wrapping the given raw value that behaves according to
+// these options.
+func (o options) wrap(value interface{}) overwriteValue {
+	return overwriteValue{
+		value:       value,
+		lenient:     o.lenient,
+		source:      o.source,
+		expressions: o.expressions,
+	}
+}
+
+func newOptions(opts ...Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// LenientConversion makes AsInt, AsFloat and AsBool (and the collection accessors built
+// on top of them) parse string representations such as "123" or "true" instead of
+// returning zero values. This is useful when overwrites arrive as strings from headers
+// or config UIs.
+func LenientConversion() Option {
+	return func(o *options) {
+		o.lenient = true
+	}
+}
+
+// DeepCopyValues makes the storage hold deep copies of the overwritten values instead
+// of the values themselves, so that later changes to maps, slices or pointers passed
+// in by the caller do not leak into the storage. This enforces the read-only contract
+// of overwritten variables at the cost of copying them once.
+func DeepCopyValues() Option {
+	return func(o *options) {
+		o.deepCopy = true
+	}
+}
@@ -0,0 +1,133 @@
+// Package otelbaggage propagates overwritten variables across service boundaries using
+// OpenTelemetry baggage, so that services already propagating baggage get dvow
+// propagation for free.
+package otelbaggage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// DefaultPrefix is the default prefix of baggage keys carrying overwrites.
+const DefaultPrefix = "dvow."
+
+// encodedValueMarker tags the baggage values written by Inject, which hold percent-encoded
+// JSON since JSON contains characters that are not allowed in baggage values.
+const encodedValueMarker = "dvow-json:"
+
+// Config configures Extract and Inject.
+type Config struct {
+	// Prefix is the prefix of baggage keys carrying overwrites, e.g. "dvow." for
+	// "dvow.surge_multiplier=1.5". Defaults to DefaultPrefix if empty.
+	Prefix string
+	// Options are passed to dvow.WithOverwrittenVariables by Extract.
+	Options []dvow.Option
+}
+
+func (cfg Config) prefix() string {
+	if cfg.Prefix == "" {
+		return DefaultPrefix
+	}
+
+	return cfg.Prefix
+}
+
+// Extract returns a context holding the overwrites found in the baggage of ctx. Each
+// baggage entry whose key starts with the configured prefix becomes a variable named
+// after the rest of the key. Values written by Inject keep their types. Other values are
+// taken as decoded by the baggage codec: those that are valid JSON, e.g. numbers and
+// booleans, are parsed as such while the others are kept as plain strings, in which case
+// consider including dvow.LenientConversion in the options.
+func Extract(ctx context.Context, cfg Config) context.Context {
+	prefix := cfg.prefix()
+
+	overwrittenVariables := make(map[string]interface{})
+	for _, member := range baggage.FromContext(ctx).Members() {
+		if !strings.HasPrefix(member.Key(), prefix) || len(member.Key()) == len(prefix) {
+			continue
+		}
+
+		overwrittenVariables[member.Key()[len(prefix):]] = decodeValue(member.Value())
+	}
+
+	if len(overwrittenVariables) == 0 {
+		return ctx
+	}
+
+	opts := append([]dvow.Option{dvow.WithSource(dvow.SourceRemote)}, cfg.Options...)
+
+	return dvow.Ops.WithOverwrittenVariables(ctx, overwrittenVariables, opts...)
+}
+
+// Inject returns a context whose baggage carries all variables effectively overwritten
+// in ctx, each under a key made of the configured prefix and its name. Values are encoded
+// as JSON so that Extract can restore their types. Existing baggage entries are preserved,
+// except those under the same keys which are replaced.
+func Inject(ctx context.Context, cfg Config) (context.Context, error) {
+	storage := dvow.ExtractOverwritingStorageV2(ctx)
+	if storage == nil || len(storage.Keys()) == 0 {
+		return ctx, nil
+	}
+
+	prefix := cfg.prefix()
+
+	bag := baggage.FromContext(ctx)
+	for _, name := range storage.Keys() {
+		value := storage.Get(name)
+		if value == nil {
+			continue
+		}
+
+		encoded, err := json.Marshal(value.AsIs())
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to encode variable %s", name))
+		}
+
+		// The stored value must be free of characters that are not allowed in baggage
+		// values, e.g. quotes and commas in JSON, hence the JSON is percent-encoded and
+		// tagged so that Extract knows to decode it. NewMember expects a percent-encoded
+		// value and decodes it once.
+		stored := encodedValueMarker + url.QueryEscape(string(encoded))
+
+		member, err := baggage.NewMember(prefix+name, url.QueryEscape(stored))
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to create baggage member for variable %s", name))
+		}
+
+		bag, err = bag.SetMember(member)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to add baggage member for variable %s", name))
+		}
+	}
+
+	return baggage.ContextWithBaggage(ctx, bag), nil
+}
+
+// decodeValue returns the JSON value held by the given baggage value, or the value itself
+// if it is not valid JSON. Only values tagged by Inject are percent-decoded since the
+// baggage codec already decoded the others.
+func decodeValue(raw string) interface{} {
+	if strings.HasPrefix(raw, encodedValueMarker) {
+		if unescaped, err := url.QueryUnescape(raw[len(encodedValueMarker):]); err == nil {
+			raw = unescaped
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(raw)))
+	decoder.UseNumber()
+
+	var result interface{}
+	if err := decoder.Decode(&result); err != nil || decoder.More() {
+		return raw
+	}
+
+	return result
+}
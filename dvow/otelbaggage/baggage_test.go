@@ -0,0 +1,113 @@
+package otelbaggage
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestInjectAndExtract(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no overwrites",
+			test: func(t *testing.T) {
+				ctx := context.Background()
+
+				injectedCtx, err := Inject(ctx, Config{})
+				assert.Nil(t, err)
+				assert.Equal(t, ctx, injectedCtx)
+				assert.Equal(t, ctx, Extract(ctx, Config{}))
+			},
+		},
+		{
+			desc: "overwrites follow the baggage",
+			test: func(t *testing.T) {
+				member, _ := baggage.NewMember("tenant", "acme")
+				bag, _ := baggage.New(member)
+
+				ctx := baggage.ContextWithBaggage(context.Background(), bag)
+				ctx = dvow.WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"surge":  1.5,
+						"name":   "a b;c",
+						"labels": map[string]interface{}{"k": "v"},
+					},
+				)
+
+				injectedCtx, err := Inject(ctx, Config{Prefix: "ow-"})
+				assert.Nil(t, err)
+
+				// Simulate sending the baggage over the wire
+				parsed, err := baggage.Parse(baggage.FromContext(injectedCtx).String())
+				assert.Nil(t, err)
+				assert.Equal(t, "acme", parsed.Member("tenant").Value())
+
+				incomingCtx := Extract(baggage.ContextWithBaggage(context.Background(), parsed), Config{Prefix: "ow-"})
+				assert.Equal(
+					t, map[string]interface{}{
+						"surge":  json.Number("1.5"),
+						"name":   "a b;c",
+						"labels": map[string]interface{}{"k": "v"},
+					}, dvow.Snapshot(incomingCtx),
+				)
+				assert.Equal(t, 1.5, dvow.GetOverwrittenValue(incomingCtx, "surge").AsFloat())
+				assert.Equal(t, dvow.SourceRemote, dvow.SourceOf(dvow.GetOverwrittenValue(incomingCtx, "surge")))
+			},
+		},
+		{
+			desc: "plain values set by other services",
+			test: func(t *testing.T) {
+				plain, _ := baggage.NewMember(DefaultPrefix+"enabled", "yes")
+				number, _ := baggage.NewMember(DefaultPrefix+"count", "3")
+				plus, _ := baggage.NewMember(DefaultPrefix+"plus", url.QueryEscape("a+b"))
+				percent, _ := baggage.NewMember(DefaultPrefix+"percent", url.QueryEscape("100%41"))
+				prefixOnly, _ := baggage.NewMember(DefaultPrefix, "ignored")
+				unrelated, _ := baggage.NewMember("tenant", "acme")
+				bag, _ := baggage.New(plain, number, plus, percent, prefixOnly, unrelated)
+
+				// Simulate receiving the baggage over the wire
+				parsed, err := baggage.Parse(bag.String())
+				assert.Nil(t, err)
+
+				ctx := Extract(
+					baggage.ContextWithBaggage(context.Background(), parsed),
+					Config{Options: []dvow.Option{dvow.WithSource(dvow.SourceTest)}},
+				)
+
+				assert.Equal(
+					t, map[string]interface{}{
+						"enabled": "yes",
+						"count":   json.Number("3"),
+						"plus":    "a+b",
+						"percent": "100%41",
+					}, dvow.Snapshot(ctx),
+				)
+				assert.Equal(t, dvow.SourceTest, dvow.SourceOf(dvow.GetOverwrittenValue(ctx, "count")))
+			},
+		},
+		{
+			desc: "invalid baggage key",
+			test: func(t *testing.T) {
+				ctx := dvow.WithOverwrittenVariables(context.Background(), map[string]interface{}{"a b": 1})
+
+				_, err := Inject(ctx, Config{})
+				assert.NotNil(t, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package dvow
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const pathSeparator = "."
+
+// getNestedValue resolves a dot-path such as "pricing.surge.multiplier" against the
+// given Storage. The longest prefix of the path that was overwritten is used as the
+// root, the remaining segments are used to traverse into nested maps, structs and
+// slices stored under this root. If they cannot be resolved under this root, the
+// next shorter overwritten prefix is tried. A masked prefix masks all paths below it.
+func getNestedValue(storage Storage, path string) Value {
+	for idx := strings.LastIndex(path, pathSeparator); idx > 0; idx = strings.LastIndex(path[:idx], pathSeparator) {
+		root, isMasked := lookup(storage, path[:idx])
+		if isMasked {
+			return nil
+		}
+
+		if root == nil {
+			continue
+		}
+
+		if leaf, ok := traversePath(root.AsIs(), strings.Split(path[idx+1:], pathSeparator)); ok {
+			return deriveValue(root, leaf)
+		}
+	}
+
+	return nil
+}
+
+// traversePath walks the given segments into v and returns the value at the end of
+// the path and true if every segment could be resolved.
+func traversePath(v interface{}, segments []string) (interface{}, bool) {
+	cur := reflect.ValueOf(v)
+	for _, segment := range segments {
+		next, ok := traverseSegment(cur, segment)
+		if !ok {
+			return nil, false
+		}
+
+		cur = next
+	}
+
+	if !cur.IsValid() {
+		return nil, true
+	}
+
+	return cur.Interface(), true
+}
+
+func traverseSegment(v reflect.Value, segment string) (reflect.Value, bool) {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}, false
+		}
+
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return reflect.Value{}, false
+		}
+
+		item := v.MapIndex(reflect.ValueOf(segment).Convert(v.Type().Key()))
+		if !item.IsValid() {
+			return reflect.Value{}, false
+		}
+
+		return item, true
+
+	case reflect.Struct:
+		return findField(v, segment)
+
+	case reflect.Slice, reflect.Array:
+		idx, err := strconv.Atoi(segment)
+		if err != nil || idx < 0 || idx >= v.Len() {
+			return reflect.Value{}, false
+		}
+
+		return v.Index(idx), true
+
+	default:
+		return reflect.Value{}, false
+	}
+}
+
+// findField returns the exported field whose name or JSON tag matches the given name.
+func findField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tagName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if field.Name == name || tagName == name {
+			return v.Field(i), true
+		}
+	}
+
+	return reflect.Value{}, false
+}
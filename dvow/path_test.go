@@ -0,0 +1,214 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetNestedValue(t *testing.T) {
+	type surge struct {
+		Multiplier float64 `json:"multiplier"`
+		Tiers      []int
+	}
+
+	type pricing struct {
+		Surge *surge `json:"surge,omitempty"`
+	}
+
+	scenarios := []struct {
+		desc      string
+		variables map[string]interface{}
+		path      string
+		want      Value
+	}{
+		{
+			desc: "not a path",
+			variables: map[string]interface{}{
+				"pricing": 1,
+			},
+			path: "pricing",
+			want: nil,
+		},
+		{
+			desc: "nested maps",
+			variables: map[string]interface{}{
+				"pricing": map[string]interface{}{
+					"surge": map[string]interface{}{
+						"multiplier": 1.5,
+					},
+				},
+			},
+			path: "pricing.surge.multiplier",
+			want: overwriteValue{value: 1.5},
+		},
+		{
+			desc: "nested structs using field names and JSON tags",
+			variables: map[string]interface{}{
+				"pricing": pricing{
+					Surge: &surge{
+						Multiplier: 2,
+						Tiers:      []int{1, 2, 3},
+					},
+				},
+			},
+			path: "pricing.surge.Tiers.1",
+			want: overwriteValue{value: 2},
+		},
+		{
+			desc: "longest prefix is used as root",
+			variables: map[string]interface{}{
+				"pricing": map[string]interface{}{
+					"surge": map[string]interface{}{
+						"multiplier": 1.5,
+					},
+				},
+				"pricing.surge": map[string]interface{}{
+					"multiplier": 3,
+				},
+			},
+			path: "pricing.surge.multiplier",
+			want: overwriteValue{value: 3},
+		},
+		{
+			desc: "shorter prefix is used if the longest one cannot be traversed",
+			variables: map[string]interface{}{
+				"a": map[string]interface{}{
+					"b": map[string]interface{}{
+						"c": 1,
+					},
+				},
+				"a.b": 2,
+			},
+			path: "a.b.c",
+			want: overwriteValue{value: 1},
+		},
+		{
+			desc: "missing leaf",
+			variables: map[string]interface{}{
+				"pricing": map[string]interface{}{
+					"surge": 1,
+				},
+			},
+			path: "pricing.surge.multiplier",
+			want: nil,
+		},
+		{
+			desc: "nil leaf",
+			variables: map[string]interface{}{
+				"pricing": map[string]interface{}{
+					"surge": nil,
+				},
+			},
+			path: "pricing.surge",
+			want: overwriteValue{value: nil},
+		},
+		{
+			desc: "index out of range",
+			variables: map[string]interface{}{
+				"tiers": []int{1},
+			},
+			path: "tiers.1",
+			want: nil,
+		},
+		{
+			desc: "nil pointer",
+			variables: map[string]interface{}{
+				"pricing": pricing{},
+			},
+			path: "pricing.surge.multiplier",
+			want: nil,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			storage := dynamicOverwritingStorage{
+				variables: sc.variables,
+			}
+
+			actual := getNestedValue(storage, sc.path)
+
+			assert.Equal(t, sc.want, actual)
+		})
+	}
+}
+
+func TestGetNestedValue_Masked(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "masked paths are not resolved from parent variables",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"pricing": map[string]interface{}{
+							"surge": map[string]interface{}{
+								"multiplier": 2,
+							},
+							"base": 1,
+						},
+					},
+				)
+
+				masked := WithoutOverwrittenVariables(ctx, "pricing.surge")
+
+				assert.Equal(t, int64(2), GetOverwrittenValue(ctx, "pricing.surge.multiplier").AsInt())
+				assert.Nil(t, GetOverwrittenValue(masked, "pricing.surge"))
+				assert.Nil(t, GetOverwrittenValue(masked, "pricing.surge.multiplier"))
+				assert.Equal(t, int64(1), GetOverwrittenValue(masked, "pricing.base").AsInt())
+
+				assert.Equal(t, []string{"pricing.base"}, sortedKeys(GetMany(masked, "pricing.surge", "pricing.surge.multiplier", "pricing.base")))
+			},
+		},
+		{
+			desc: "masking survives flattening",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"pricing": map[string]interface{}{
+							"surge": 2,
+						},
+					},
+				)
+
+				ctx = WithoutOverwrittenVariables(ctx, "pricing.surge")
+				for i := 0; i < maxChainDepth; i++ {
+					ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"other": i})
+				}
+
+				assert.Nil(t, GetOverwrittenValue(ctx, "pricing.surge"))
+				assert.Empty(t, GetMany(ctx, "pricing.surge"))
+			},
+		},
+		{
+			desc: "masked variables are not resolved through chained storages",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{
+						"pricing": map[string]interface{}{
+							"surge": 2,
+						},
+					},
+				)
+
+				ctx = WithoutOverwrittenVariables(ctx, "pricing.surge")
+				ctx = WithOverwrittenLayer(ctx, map[string]interface{}{"other": 1}, PriorityRequest)
+
+				assert.Nil(t, GetOverwrittenValue(ctx, "pricing.surge"))
+				assert.Empty(t, GetMany(ctx, "pricing.surge"))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
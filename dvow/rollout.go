@@ -0,0 +1,67 @@
+package dvow
+
+import (
+	"context"
+
+	"github.com/jamestrandung/go-context/helper"
+)
+
+// rolloutBuckets is the number of buckets units are hashed into, allowing percentages
+// with a precision of 2 decimal places.
+const rolloutBuckets = 10000
+
+// UnitIDExtractor returns the ID of the unit (e.g. user, session) the given context
+// belongs to and true, or false if no such ID could be found.
+type UnitIDExtractor func(ctx context.Context) (string, bool)
+
+// Rollout is an overwrite that applies only to a percentage of units.
+type Rollout struct {
+	// Name is the name of the variable to overwrite.
+	Name string
+	// Value is the value to overwrite the variable with.
+	Value interface{}
+	// Percentage is the percentage of units, from 0 to 100, the overwrite applies to.
+	Percentage float64
+	// Salt decides which units fall into the Percentage. Rollouts with the same Salt
+	// apply to overlapping sets of units. Defaults to Name so that different variables
+	// are rolled out to independent sets of units.
+	Salt string
+}
+
+// WithRollouts returns a new context.Context in which the variables of the given Rollout
+// are overwritten if the unit that ctx belongs to falls into their Percentage. Units are
+// assigned to buckets deterministically by hashing their ID, hence the same unit always
+// gets the same overwrites. If the unit ID cannot be extracted from ctx, no variables are
+// overwritten. The variables are recorded as coming from SourceFlag unless another Source
+// is given.
+func WithRollouts(ctx context.Context, extractor UnitIDExtractor, rollouts []Rollout, opts ...Option) context.Context {
+	unitID, ok := extractor(ctx)
+	if !ok {
+		return ctx
+	}
+
+	overwrittenVariables := make(map[string]interface{})
+	for _, rollout := range rollouts {
+		salt := rollout.Salt
+		if salt == "" {
+			salt = rollout.Name
+		}
+
+		if inRollout(salt, unitID, rollout.Percentage) {
+			overwrittenVariables[rollout.Name] = rollout.Value
+		}
+	}
+
+	opts = append([]Option{WithSource(SourceFlag)}, opts...)
+
+	return Ops.WithOverwrittenVariables(ctx, overwrittenVariables, opts...)
+}
+
+// inRollout returns whether the unit under the given ID falls into the given percentage
+// of units for the given salt.
+func inRollout(salt string, unitID string, percentage float64) bool {
+	hash, _ := helper.Hash(salt+"\x00"+unitID, helper.WithHasher(helper.FNV64aHasher))
+	bucket := hash % rolloutBuckets
+
+	return float64(bucket) < percentage*rolloutBuckets/100
+}
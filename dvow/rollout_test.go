@@ -0,0 +1,111 @@
+package dvow
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type unitIDKey struct{}
+
+func extractUnitID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(unitIDKey{}).(string)
+	return id, ok
+}
+
+func TestInRollout(t *testing.T) {
+	scenarios := []struct {
+		desc       string
+		percentage float64
+		min        int
+		max        int
+	}{
+		{
+			desc:       "0%",
+			percentage: 0,
+			min:        0,
+			max:        0,
+		},
+		{
+			desc:       "10%",
+			percentage: 10,
+			min:        800,
+			max:        1200,
+		},
+		{
+			desc:       "100%",
+			percentage: 100,
+			min:        10000,
+			max:        10000,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			count := 0
+			for i := 0; i < 10000; i++ {
+				if inRollout("salt", strconv.Itoa(i), sc.percentage) {
+					count++
+				}
+			}
+
+			assert.True(t, count >= sc.min && count <= sc.max, count)
+		})
+	}
+}
+
+func TestWithRollouts(t *testing.T) {
+	rollouts := []Rollout{
+		{Name: "a", Value: 1, Percentage: 100},
+		{Name: "b", Value: 2, Percentage: 0},
+		{Name: "c", Value: 3, Percentage: 50},
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "unit ID is missing",
+			test: func(t *testing.T) {
+				ctx := context.Background()
+
+				assert.Equal(t, ctx, WithRollouts(ctx, extractUnitID, rollouts))
+			},
+		},
+		{
+			desc: "overwrites are deterministic per unit",
+			test: func(t *testing.T) {
+				matched := 0
+				for i := 0; i < 100; i++ {
+					unitCtx := context.WithValue(context.Background(), unitIDKey{}, strconv.Itoa(i))
+
+					ctx := WithRollouts(unitCtx, extractUnitID, rollouts)
+
+					assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+					assert.Nil(t, GetOverwrittenValue(ctx, "b"))
+
+					c := GetOverwrittenValue(ctx, "c")
+					if c != nil {
+						matched++
+					}
+
+					again := WithRollouts(unitCtx, extractUnitID, rollouts)
+					assert.Equal(t, c, GetOverwrittenValue(again, "c"))
+				}
+
+				assert.True(t, matched > 0 && matched < 100, matched)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package dvow
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Rule describes what a valid overwrite of a variable looks like.
+type Rule struct {
+	// Kind is the expected kind of the overwritten value. Any numeric kind accepts
+	// all numbers, including json.Number. reflect.Invalid means any kind.
+	Kind reflect.Kind
+	// Min is the inclusive lower bound of numeric values, if not nil.
+	Min *float64
+	// Max is the inclusive upper bound of numeric values, if not nil.
+	Max *float64
+	// Enum lists the allowed values, if not empty.
+	Enum []interface{}
+}
+
+// Violation reports an overwrite that does not satisfy the Rule of its variable.
+type Violation struct {
+	Name  string
+	Value interface{}
+	Err   error
+}
+
+// Error implements the error interface.
+func (v Violation) Error() string {
+	return fmt.Sprintf("invalid overwrite of %s (%s): %v", v.Name, describeValue(v.Value), v.Err)
+}
+
+// Unwrap returns the underlying error of this Violation.
+func (v Violation) Unwrap() error {
+	return v.Err
+}
+
+// ValidationMode defines what happens to overwrites when some of them are invalid.
+type ValidationMode int
+
+const (
+	// DropInvalid drops only the invalid overwrites and keeps the valid ones.
+	DropInvalid ValidationMode = iota
+	// RejectInvalid drops all overwrites if any of them is invalid.
+	RejectInvalid
+)
+
+// Schema is a registry of the Rule of overwritable variables. Variables without
+// a registered Rule are not validated.
+type Schema struct {
+	mu    sync.RWMutex
+	rules map[string]Rule
+}
+
+// NewSchema returns an empty Schema.
+func NewSchema() *Schema {
+	return &Schema{
+		rules: make(map[string]Rule),
+	}
+}
+
+// Register sets the Rule of the variable under this name.
+func (s *Schema) Register(name string, rule Rule) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rules[name] = rule
+}
+
+// Validate returns the Violation of all overwritten variables that do not satisfy
+// their Rule.
+func (s *Schema) Validate(overwrittenVariables map[string]interface{}) []Violation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var violations []Violation
+	for name, value := range overwrittenVariables {
+		rule, ok := s.rules[name]
+		if !ok {
+			continue
+		}
+
+		if err := rule.check(value); err != nil {
+			violations = append(
+				violations, Violation{
+					Name:  name,
+					Value: value,
+					Err:   err,
+				},
+			)
+		}
+	}
+
+	return violations
+}
+
+func (r Rule) check(value interface{}) error {
+	number, isNumber := castNumber(value)
+
+	if r.Kind != reflect.Invalid {
+		if isNumericKind(r.Kind) {
+			if !isNumber {
+				return errors.Wrap(ErrUnexpectedKind, fmt.Sprintf("expected %v, got %T", r.Kind, value))
+			}
+		} else if value == nil || reflect.TypeOf(value).Kind() != r.Kind {
+			return errors.Wrap(ErrUnexpectedKind, fmt.Sprintf("expected %v, got %T", r.Kind, value))
+		}
+	}
+
+	if isNumber {
+		if r.Min != nil && number < *r.Min {
+			return errors.Wrap(ErrOutOfRange, fmt.Sprintf("%v is less than %v", number, *r.Min))
+		}
+
+		if r.Max != nil && number > *r.Max {
+			return errors.Wrap(ErrOutOfRange, fmt.Sprintf("%v is greater than %v", number, *r.Max))
+		}
+	}
+
+	if len(r.Enum) == 0 {
+		return nil
+	}
+
+	for _, allowed := range r.Enum {
+		if allowedNumber, ok := castNumber(allowed); ok && isNumber {
+			if allowedNumber == number {
+				return nil
+			}
+
+			continue
+		}
+
+		if reflect.DeepEqual(allowed, value) {
+			return nil
+		}
+	}
+
+	return ErrNotInEnum
+}
+
+// castNumber converts any numeric value, including json.Number, to float64.
+func castNumber(v interface{}) (float64, bool) {
+	if result, ok := castFloat(v); ok {
+		return result, true
+	}
+
+	if _, ok := v.(json.Number); ok || v == nil {
+		return 0, false
+	}
+
+	rv := reflect.ValueOf(v)
+	if !isNumericKind(rv.Kind()) {
+		return 0, false
+	}
+
+	return rv.Convert(reflect.TypeOf(float64(0))).Float(), true
+}
+
+// WithSchema validates overwritten variables against the given Schema before storing
+// them. Invalid overwrites are dropped according to the given ValidationMode and the
+// violations are passed to report if it is not nil.
+func WithSchema(schema *Schema, mode ValidationMode, report func([]Violation)) Option {
+	return func(o *options) {
+		o.schema = schema
+		o.validationMode = mode
+		o.reportViolations = report
+	}
+}
+
+// validate returns the overwritten variables that should be stored after validating
+// them against the configured Schema, if any.
+func (o options) validate(overwrittenVariables map[string]interface{}) map[string]interface{} {
+	if o.schema == nil {
+		return overwrittenVariables
+	}
+
+	violations := o.schema.Validate(overwrittenVariables)
+	if len(violations) == 0 {
+		return overwrittenVariables
+	}
+
+	if o.reportViolations != nil {
+		o.reportViolations(violations)
+	}
+
+	if o.validationMode == RejectInvalid {
+		return nil
+	}
+
+	for _, violation := range violations {
+		delete(overwrittenVariables, violation.Name)
+	}
+
+	return overwrittenVariables
+}
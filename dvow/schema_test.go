@@ -0,0 +1,159 @@
+package dvow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Check(t *testing.T) {
+	min, max := 1.0, 3.0
+
+	scenarios := []struct {
+		desc        string
+		rule        Rule
+		value       interface{}
+		expectedErr error
+	}{
+		{
+			desc:  "no constraint",
+			rule:  Rule{},
+			value: "anything",
+		},
+		{
+			desc:  "numeric kind accepts any number",
+			rule:  Rule{Kind: reflect.Float64},
+			value: json.Number("2"),
+		},
+		{
+			desc:  "numeric kind accepts unsigned number",
+			rule:  Rule{Kind: reflect.Int},
+			value: uint8(2),
+		},
+		{
+			desc:        "numeric kind rejects string",
+			rule:        Rule{Kind: reflect.Int},
+			value:       "2",
+			expectedErr: ErrUnexpectedKind,
+		},
+		{
+			desc:        "non-numeric kind",
+			rule:        Rule{Kind: reflect.String},
+			value:       true,
+			expectedErr: ErrUnexpectedKind,
+		},
+		{
+			desc:        "nil value",
+			rule:        Rule{Kind: reflect.String},
+			value:       nil,
+			expectedErr: ErrUnexpectedKind,
+		},
+		{
+			desc:        "below min",
+			rule:        Rule{Min: &min, Max: &max},
+			value:       0,
+			expectedErr: ErrOutOfRange,
+		},
+		{
+			desc:        "above max",
+			rule:        Rule{Min: &min, Max: &max},
+			value:       3.5,
+			expectedErr: ErrOutOfRange,
+		},
+		{
+			desc:  "within range",
+			rule:  Rule{Min: &min, Max: &max},
+			value: int64(3),
+		},
+		{
+			desc:  "in enum",
+			rule:  Rule{Enum: []interface{}{"a", 1}},
+			value: 1.0,
+		},
+		{
+			desc:        "not in enum",
+			rule:        Rule{Enum: []interface{}{"a", 1}},
+			value:       "b",
+			expectedErr: ErrNotInEnum,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			err := sc.rule.check(sc.value)
+			if sc.expectedErr == nil {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, sc.expectedErr), err)
+		})
+	}
+}
+
+func TestWithSchema(t *testing.T) {
+	schema := NewSchema()
+	schema.Register("name", Rule{Kind: reflect.String})
+	schema.Register("count", Rule{Kind: reflect.Int})
+
+	variables := map[string]interface{}{
+		"name":  1,
+		"count": 2,
+		"other": true,
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "drop invalid",
+			test: func(t *testing.T) {
+				var violations []Violation
+				ctx := WithOverwrittenVariables(
+					context.Background(), variables, WithSchema(
+						schema, DropInvalid, func(v []Violation) {
+							violations = v
+						},
+					),
+				)
+
+				assert.Equal(t, []string{"count", "other"}, ExtractOverwritingStorageV2(ctx).Keys())
+				assert.Equal(t, 1, len(violations))
+				assert.Equal(t, "name", violations[0].Name)
+				assert.True(t, errors.Is(violations[0], ErrUnexpectedKind))
+				assert.Equal(t, 3, len(variables))
+			},
+		},
+		{
+			desc: "reject invalid",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), variables, WithSchema(schema, RejectInvalid, nil))
+
+				assert.Nil(t, ExtractOverwritingStorage(ctx))
+			},
+		},
+		{
+			desc: "all valid",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{"name": "a"}, WithSchema(schema, RejectInvalid, nil),
+				)
+
+				assert.Equal(t, "a", GetOverwrittenValue(ctx, "name").AsString())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
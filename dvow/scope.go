@@ -0,0 +1,94 @@
+package dvow
+
+import (
+	"context"
+	"reflect"
+)
+
+// With applies the given overwritten variables and returns the context in which they
+// are visible along with a function restoring the previous state, which should be
+// deferred. This is primarily meant for table-driven tests exercising many combinations
+// of overwrites.
+//
+// If ctx was initialized using WithMutableOverwrittenVariables, the variables are set
+// in place in its mutable Storage so that all contexts sharing this Storage see them,
+// and restoring puts back the values they had before. Restores must then happen in the
+// reverse order of the calls to With. Otherwise, the variables are overwritten in a new
+// context derived from ctx, which is left untouched, and restoring does nothing.
+//
+// The given Option are applied as in WithOverwrittenVariables, except that values set
+// in a mutable Storage are exposed according to the options of that Storage.
+func With(ctx context.Context, overwrittenVariables map[string]interface{}, opts ...Option) (context.Context, func()) {
+	s, ok := ctx.Value(mutableStorageKey).(*mutableStorage)
+	if !ok {
+		return WithOverwrittenVariables(ctx, overwrittenVariables, opts...), func() {}
+	}
+
+	variables := prepareVariables(overwrittenVariables, newOptions(opts...))
+	if len(variables) == 0 {
+		return ctx, func() {}
+	}
+
+	return ctx, s.swap(variables)
+}
+
+// Scoped returns a function that runs fn with a context in which the given overwritten
+// variables are applied using With, restoring the previous state once fn returns.
+//
+//	dvow.Scoped(ctx, map[string]interface{}{"surge_multiplier": 2.0})(func(ctx context.Context) {
+//		// assertions
+//	})
+func Scoped(ctx context.Context, overwrittenVariables map[string]interface{}, opts ...Option) func(fn func(context.Context)) {
+	return func(fn func(context.Context)) {
+		scopedCtx, restore := With(ctx, overwrittenVariables, opts...)
+		defer restore()
+
+		fn(scopedCtx)
+	}
+}
+
+// swap sets the given variables in this Storage and returns a function putting back
+// their previous values. Watchers are notified of both changes.
+func (s *mutableStorage) swap(variables map[string]interface{}) func() {
+	previous := s.replace(variables)
+
+	return func() {
+		s.replace(previous)
+	}
+}
+
+// replace sets the given variables in this Storage, unsetting those whose values are
+// tombstones, and returns the replaced values in the same format.
+func (s *mutableStorage) replace(variables map[string]interface{}) map[string]interface{} {
+	s.mu.Lock()
+
+	var notifications []func()
+
+	previous := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		oldValue, wasPresent := s.variables[name]
+		if !wasPresent {
+			oldValue = tombstone{}
+		}
+
+		previous[name] = oldValue
+
+		var newValue Value
+		if _, isMasked := value.(tombstone); isMasked {
+			delete(s.variables, name)
+		} else {
+			s.variables[name] = value
+			newValue = s.options.wrap(value)
+		}
+
+		if !reflect.DeepEqual(oldValue, value) {
+			notifications = append(notifications, s.watchers.notifications(name, newValue)...)
+		}
+	}
+
+	s.mu.Unlock()
+
+	notify(notifications)
+
+	return previous
+}
@@ -0,0 +1,89 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWith(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "immutable storage",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+				scopedCtx, restore := With(ctx, map[string]interface{}{"a": 2})
+				assert.Equal(t, 2, GetOverwrittenValue(scopedCtx, "a").AsIs())
+				assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+
+				restore()
+				assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+			},
+		},
+		{
+			desc: "mutable storage",
+			test: func(t *testing.T) {
+				ctx := WithMutableOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+				var changes []Value
+				_, _ = OnChange(ctx, "b", func(v Value) { changes = append(changes, v) })
+
+				scopedCtx, restoreOuter := With(ctx, map[string]interface{}{"a": 2, "b": 2})
+				assert.Equal(t, ctx, scopedCtx)
+				assert.Equal(t, 2, GetOverwrittenValue(ctx, "a").AsIs())
+				assert.Equal(t, 2, GetOverwrittenValue(ctx, "b").AsIs())
+
+				_, restoreInner := With(ctx, map[string]interface{}{"b": 3})
+				assert.Equal(t, 3, GetOverwrittenValue(ctx, "b").AsIs())
+
+				restoreInner()
+				assert.Equal(t, 2, GetOverwrittenValue(ctx, "b").AsIs())
+
+				restoreOuter()
+				assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+				assert.Nil(t, GetOverwrittenValue(ctx, "b"))
+				assert.Equal(t, []string{"a"}, ExtractOverwritingStorageV2(ctx).Keys())
+
+				assert.Equal(t, 4, len(changes))
+				assert.Nil(t, changes[3])
+
+				scopedCtx, restore := With(ctx, nil)
+				assert.Equal(t, ctx, scopedCtx)
+				restore()
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestScoped(t *testing.T) {
+	ctx := WithMutableOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+	called := false
+	Scoped(ctx, map[string]interface{}{"a": 2})(
+		func(ctx context.Context) {
+			called = true
+			assert.Equal(t, 2, GetOverwrittenValue(ctx, "a").AsIs())
+		},
+	)
+
+	assert.True(t, called)
+	assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+
+	Scoped(context.Background(), map[string]interface{}{"a": 2})(
+		func(ctx context.Context) {
+			assert.Equal(t, 2, GetOverwrittenValue(ctx, "a").AsIs())
+		},
+	)
+}
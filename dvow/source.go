@@ -0,0 +1,66 @@
+package dvow
+
+import (
+	"context"
+)
+
+// Source describes where an overwrite came from.
+type Source string
+
+// Well-known sources of overwrites.
+const (
+	SourceUnknown Source = ""
+	SourceHeader  Source = "header"
+	SourceConfig  Source = "config"
+	SourceFlag    Source = "flag"
+	SourceRemote  Source = "remote"
+	SourceTest    Source = "test"
+)
+
+// WithSource records the given Source as the origin of the overwritten variables so
+// that audits can answer who set a value on a request. Loaders and middlewares in
+// this library record a suitable Source by default, which can be overridden using
+// this option.
+func WithSource(source Source) Option {
+	return func(o *options) {
+		o.source = source
+	}
+}
+
+// ValueWithSource is a Value that knows where it came from. All Value returned by the
+// storages in this library implement this interface.
+type ValueWithSource interface {
+	Value
+	// Source returns where the wrapped value came from.
+	Source() Source
+}
+
+// SourceOf returns the Source of the given Value, or SourceUnknown if it does not
+// implement ValueWithSource.
+func SourceOf(v Value) Source {
+	if vs, ok := v.(ValueWithSource); ok {
+		return vs.Source()
+	}
+
+	return SourceUnknown
+}
+
+// SnapshotSources returns the Source of all variables that are effectively overwritten
+// in the given context, complementing Snapshot.
+func SnapshotSources(ctx context.Context) map[string]Source {
+	storage := ExtractOverwritingStorageV2(ctx)
+	if storage == nil {
+		return nil
+	}
+
+	keys := storage.Keys()
+
+	sources := make(map[string]Source, len(keys))
+	for _, name := range keys {
+		if value := storage.Get(name); value != nil {
+			sources[name] = SourceOf(value)
+		}
+	}
+
+	return sources
+}
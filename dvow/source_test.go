@@ -0,0 +1,68 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSourceOf(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "Value without source",
+			test: func(t *testing.T) {
+				assert.Equal(t, SourceUnknown, SourceOf(&MockValue{}))
+			},
+		},
+		{
+			desc: "sources are recorded per storage",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1, "b": 1}, WithSource(SourceTest))
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"b": 2})
+
+				assert.Equal(t, SourceTest, SourceOf(GetOverwrittenValue(ctx, "a")))
+				assert.Equal(t, SourceUnknown, SourceOf(GetOverwrittenValue(ctx, "b")))
+				assert.Equal(t, map[string]Source{"a": SourceTest, "b": SourceUnknown}, SnapshotSources(ctx))
+			},
+		},
+		{
+			desc: "nested leaves keep the source of their root",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(
+					context.Background(), map[string]interface{}{"a": map[string]interface{}{"b": 1}}, WithSource(SourceHeader),
+				)
+
+				assert.Equal(t, SourceHeader, SourceOf(GetOverwrittenValue(ctx, "a.b")))
+			},
+		},
+		{
+			desc: "loaders record a default source",
+			test: func(t *testing.T) {
+				ctx, err := WithOverwrittenVariablesFromYAML(context.Background(), []byte("a: 1"))
+				assert.Nil(t, err)
+				assert.Equal(t, SourceConfig, SourceOf(GetOverwrittenValue(ctx, "a")))
+
+				ctx, err = WithOverwrittenVariablesFromYAML(context.Background(), []byte("a: 1"), WithSource(SourceTest))
+				assert.Nil(t, err)
+				assert.Equal(t, SourceTest, SourceOf(GetOverwrittenValue(ctx, "a")))
+			},
+		},
+		{
+			desc: "no storage",
+			test: func(t *testing.T) {
+				assert.Nil(t, SnapshotSources(context.Background()))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
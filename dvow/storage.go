@@ -1,5 +1,10 @@
 package dvow
 
+import (
+    "sort"
+    "time"
+)
+
 //go:generate mockery --name Storage --case underscore --inpkg
 // Storage is the container of all overwritten variables
 type Storage interface {
@@ -7,23 +12,237 @@ type Storage interface {
     Get(name string) Value
 }
 
+// KeyedStorage is implemented by Storage that can enumerate their overwritten
+// variables. All Storage provided by this package implement it, while third-party
+// Storage may opt in by adding the Keys method.
+type KeyedStorage interface {
+    // Keys returns the sorted names of all variables that were overwritten in this
+    // Storage, including those inherited from parent Storage.
+    Keys() []string
+}
+
+// keysOf returns the sorted names of all variables that were overwritten in the
+// given Storage, nil if it does not implement KeyedStorage.
+func keysOf(storage Storage) []string {
+    if ks, ok := storage.(KeyedStorage); ok {
+        return ks.Keys()
+    }
+
+    return nil
+}
+
+// tombstone masks a variable overwritten in a parent Storage.
+type tombstone struct{}
+
+// maskingStorage is implemented by Storage that can tell variables masked via
+// WithoutOverwrittenVariables apart from those that were never overwritten.
+type maskingStorage interface {
+    // lookup returns the Value of the variable under this name if it was overwritten,
+    // and true if it was masked instead.
+    lookup(name string) (Value, bool)
+}
+
+// lookup returns the Value of the variable under this name in the given Storage if it
+// was overwritten, and true if it was masked instead.
+func lookup(storage Storage, name string) (Value, bool) {
+    if storage == nil {
+        return nil, false
+    }
+
+    if ms, ok := storage.(maskingStorage); ok {
+        return ms.lookup(name)
+    }
+
+    return storage.Get(name), false
+}
+
+// timeNow can be replaced in tests to control the expiry of overwritten variables.
+var timeNow = time.Now
+
 type dynamicOverwritingStorage struct {
-    parent Storage // from parent context.Context
+    parent    Storage // from parent context.Context
     variables map[string]interface{}
+    options   options
+    // expiresAt is the time after which variables in this Storage behave
+    // as if they were absent, zero if they never expire.
+    expiresAt time.Time
+    // depth is the number of dynamicOverwritingStorage chained above this one
+    // that have not been flattened yet.
+    depth int
+    // inherited holds the variables flattened from ancestors, nil for those that
+    // were masked. It is consulted after variables and before parent.
+    inherited map[string]Value
+    // checksums holds the hashes of variables taken when they were stored, nil
+    // unless mutations should be detected.
+    checksums map[string]uint64
+}
+
+// maxChainDepth is the number of dynamicOverwritingStorage that can be chained
+// before their variables get flattened into a new Storage to keep lookups O(1).
+var maxChainDepth = 8
+
+// newDynamicOverwritingStorage returns a dynamicOverwritingStorage on top of the given
+// parent. If the chain of parents gets too deep, their variables are materialized into
+// the new Storage so that lookups don't need to walk the whole chain.
+func newDynamicOverwritingStorage(
+    parent Storage,
+    variables map[string]interface{},
+    options options,
+    expiresAt time.Time,
+) dynamicOverwritingStorage {
+    s := dynamicOverwritingStorage{
+        parent:    parent,
+        variables: variables,
+        options:   options,
+        expiresAt: expiresAt,
+    }
+
+    if options.detectMutations {
+        s.checksums = checksumVariables(variables)
+    }
+
+    p, ok := parent.(dynamicOverwritingStorage)
+    if !ok {
+        return s
+    }
+
+    s.depth = p.depth + 1
+    if s.depth < maxChainDepth {
+        return s
+    }
+
+    if s.options.metricsHook == nil {
+        s.options.metricsHook = p.metricsHook()
+    }
+
+    s.inherited, s.parent = p.flatten()
+    s.depth = 0
+    if remaining, ok := s.parent.(dynamicOverwritingStorage); ok {
+        s.depth = remaining.depth + 1
+    }
+
+    return s
+}
+
+// flatten materializes the variables of this Storage and its ancestors into one map in
+// which masked variables map to nil. It stops at the first ancestor that cannot be
+// flattened, i.e. one that is not a dynamicOverwritingStorage or that has an expiry,
+// and returns it as the remaining parent. Ancestors detecting mutations are not flattened
+// either so that their variables keep being verified on every lookup.
+func (s dynamicOverwritingStorage) flatten() (map[string]Value, Storage) {
+    flattened := make(map[string]Value)
+
+    var current Storage = s
+    for {
+        d, ok := current.(dynamicOverwritingStorage)
+        if !ok || !d.expiresAt.IsZero() || d.checksums != nil {
+            return flattened, current
+        }
+
+        for name, value := range d.variables {
+            if _, exists := flattened[name]; !exists {
+                flattened[name] = d.wrap(value)
+            }
+        }
+
+        for name, value := range d.inherited {
+            if _, exists := flattened[name]; !exists {
+                flattened[name] = value
+            }
+        }
+
+        current = d.parent
+    }
+}
+
+// wrap returns the Value of the given raw value, nil if it is a tombstone.
+func (s dynamicOverwritingStorage) wrap(value interface{}) Value {
+    if _, isMasked := value.(tombstone); isMasked {
+        return nil
+    }
+
+    return s.options.wrap(value)
+}
+
+// isExpired returns whether variables in this Storage have expired.
+func (s dynamicOverwritingStorage) isExpired() bool {
+    return !s.expiresAt.IsZero() && !timeNow().Before(s.expiresAt)
 }
 
 // Get returns the Value of the variable under this name if it was overwritten
 func (s dynamicOverwritingStorage) Get(name string) Value {
-    if value, isPresent := s.variables[name]; isPresent {
-        return overwriteValue{
-            value: value,
+    value, _ := s.lookup(name)
+    return value
+}
+
+func (s dynamicOverwritingStorage) lookup(name string) (Value, bool) {
+    if value, isPresent := s.variables[name]; isPresent && !s.isExpired() {
+        s.options.verifyChecksum(s.checksums, name, value)
+        if _, isMasked := value.(tombstone); isMasked {
+            return nil, true
         }
+
+        return s.options.wrap(value), false
     }
 
+    if value, isPresent := s.inherited[name]; isPresent {
+        // Masked variables are flattened to nil
+        return value, value == nil
+    }
+
+    return lookup(s.parent, name)
+}
+
+// Keys returns the sorted names of all variables that were overwritten in this
+// Storage, including those inherited from parent Storage.
+func (s dynamicOverwritingStorage) Keys() []string {
+    var parentKeys []string
     if s.parent != nil {
-        return s.parent.Get(name)
+        parentKeys = keysOf(s.parent)
     }
 
-    return nil
+    isExpired := s.isExpired()
+    isShadowed := func(name string) bool {
+        _, isPresent := s.variables[name]
+        return isPresent && !isExpired
+    }
+
+    keys := make([]string, 0, len(parentKeys)+len(s.inherited)+len(s.variables))
+    for _, name := range parentKeys {
+        if _, isInherited := s.inherited[name]; !isInherited && !isShadowed(name) {
+            keys = append(keys, name)
+        }
+    }
+
+    for name, value := range s.inherited {
+        if value != nil && !isShadowed(name) {
+            keys = append(keys, name)
+        }
+    }
+
+    if !isExpired {
+        for name, value := range s.variables {
+            if _, isMasked := value.(tombstone); !isMasked {
+                keys = append(keys, name)
+            }
+        }
+    }
+
+    sort.Strings(keys)
+
+    return keys
 }
 
+// metricsHook returns the MetricsHook of this Storage, or that of its parent if this
+// Storage does not have its own MetricsHook.
+func (s dynamicOverwritingStorage) metricsHook() MetricsHook {
+    if s.options.metricsHook != nil {
+        return s.options.metricsHook
+    }
+
+    if p, ok := s.parent.(metricsHookProvider); ok {
+        return p.metricsHook()
+    }
+
+    return nil
+}
@@ -0,0 +1,232 @@
+package dvow
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Provider supplies overwritten variables from an external source such as a config
+// service or a file.
+//
+//go:generate mockery --name Provider --case underscore --inpkg
+type Provider interface {
+	// Load returns the latest overwritten variables.
+	Load(ctx context.Context) (map[string]interface{}, error)
+}
+
+// DynamicStorage is a Storage backed by a Provider. Its variables can be refreshed at
+// runtime so that long-lived contexts (consumers, streaming handlers) see overwrite
+// changes without rebuilding the context.
+type DynamicStorage struct {
+	provider Provider
+	options  options
+
+	mu        sync.RWMutex
+	variables map[string]interface{}
+	watchers  watcherSet
+}
+
+// NewDynamicStorage returns a DynamicStorage after loading the initial variables from
+// the given Provider.
+func NewDynamicStorage(ctx context.Context, provider Provider, opts ...Option) (*DynamicStorage, error) {
+	s := &DynamicStorage{
+		provider: provider,
+		options:  newOptions(opts...),
+	}
+
+	if err := s.Refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WithDynamicStorage returns a new context.Context in which the variables provided by
+// the given DynamicStorage are overwritten on top of those already in ctx.
+func WithDynamicStorage(ctx context.Context, s *DynamicStorage) context.Context {
+	derivedStorage := chainedStorage{
+		current: s,
+		parent:  Ops.ExtractOverwritingStorage(ctx),
+	}
+
+	ctx = context.WithValue(ctx, watchableStorageKey, s)
+	return context.WithValue(ctx, overwritingStorageKey, derivedStorage)
+}
+
+// Get returns the Value of the variable under this name if it was overwritten
+func (s *DynamicStorage) Get(name string) Value {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.get(name)
+}
+
+func (s *DynamicStorage) get(name string) Value {
+	value, isPresent := s.variables[name]
+	if !isPresent {
+		return nil
+	}
+
+	return s.options.wrap(value)
+}
+
+// Keys returns the sorted names of all variables that were overwritten in this Storage.
+func (s *DynamicStorage) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.variables))
+	for name := range s.variables {
+		keys = append(keys, name)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Watch registers fn to be called with the new Value of the variable under this name
+// whenever it changes after a Refresh. The Value is nil if the variable is no longer
+// overwritten. The returned function cancels this subscription.
+func (s *DynamicStorage) Watch(name string, fn func(Value)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.watchers.add(name, fn)
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.watchers.remove(name, id)
+	}
+}
+
+// Refresh loads the latest variables from the Provider and notifies the watchers of
+// variables that have changed. If loading fails, the current variables are kept.
+func (s *DynamicStorage) Refresh(ctx context.Context) error {
+	variables, err := s.provider.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Make a copy so that our storage wouldn't be affected by changes to the loaded map
+	clone := make(map[string]interface{}, len(variables))
+	for name, value := range variables {
+		clone[name] = value
+	}
+
+	s.mu.Lock()
+
+	var notifications []func()
+	for _, name := range s.watchers.names() {
+		oldValue, wasPresent := s.variables[name]
+		newValue, isPresent := clone[name]
+		if wasPresent == isPresent && reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+
+		var value Value
+		if isPresent {
+			value = s.options.wrap(newValue)
+		}
+
+		notifications = append(notifications, s.watchers.notifications(name, value)...)
+	}
+
+	s.variables = clone
+	s.mu.Unlock()
+
+	// Notify outside the lock so that watchers can safely read from this Storage
+	notify(notifications)
+
+	return nil
+}
+
+// Poll calls Refresh at every interval until ctx is done. Errors from Refresh are
+// passed to onError if it is not nil. This function blocks, hence it should be run
+// in a separate goroutine.
+func (s *DynamicStorage) Poll(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// metricsHook returns the MetricsHook of this Storage, if any.
+func (s *DynamicStorage) metricsHook() MetricsHook {
+	return s.options.metricsHook
+}
+
+// chainedStorage overwrites the variables of parent with those of current.
+type chainedStorage struct {
+	current Storage
+	parent  Storage // from parent context.Context
+}
+
+// Get returns the Value of the variable under this name if it was overwritten
+func (s chainedStorage) Get(name string) Value {
+	value, _ := s.lookup(name)
+	return value
+}
+
+func (s chainedStorage) lookup(name string) (Value, bool) {
+	if value, isMasked := lookup(s.current, name); value != nil || isMasked {
+		return value, isMasked
+	}
+
+	return lookup(s.parent, name)
+}
+
+// Keys returns the sorted names of all variables that were overwritten in this
+// Storage, including those inherited from parent Storage.
+func (s chainedStorage) Keys() []string {
+	keys := keysOf(s.current)
+	if s.parent == nil {
+		return keys
+	}
+
+	seen := make(map[string]struct{}, len(keys))
+	for _, name := range keys {
+		seen[name] = struct{}{}
+	}
+
+	for _, name := range keysOf(s.parent) {
+		if _, ok := seen[name]; !ok {
+			keys = append(keys, name)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// metricsHook returns the MetricsHook of current, or that of parent if current does
+// not have its own MetricsHook.
+func (s chainedStorage) metricsHook() MetricsHook {
+	if p, ok := s.current.(metricsHookProvider); ok {
+		if hook := p.metricsHook(); hook != nil {
+			return hook
+		}
+	}
+
+	if p, ok := s.parent.(metricsHookProvider); ok {
+		return p.metricsHook()
+	}
+
+	return nil
+}
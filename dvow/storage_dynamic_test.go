@@ -0,0 +1,104 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewDynamicStorage(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "initial variables are loaded",
+			test: func(t *testing.T) {
+				providerMock := &MockProvider{}
+				providerMock.On("Load", mock.Anything).Return(map[string]interface{}{"a": 1}, nil).Once()
+
+				s, err := NewDynamicStorage(context.Background(), providerMock)
+
+				assert.Nil(t, err)
+				assert.Equal(t, 1, s.Get("a").AsIs())
+				assert.Nil(t, s.Get("b"))
+				assert.Equal(t, []string{"a"}, s.Keys())
+				mock.AssertExpectationsForObjects(t, providerMock)
+			},
+		},
+		{
+			desc: "load error",
+			test: func(t *testing.T) {
+				providerMock := &MockProvider{}
+				providerMock.On("Load", mock.Anything).Return(nil, assert.AnError).Once()
+
+				s, err := NewDynamicStorage(context.Background(), providerMock)
+
+				assert.Nil(t, s)
+				assert.Equal(t, assert.AnError, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestDynamicStorage_Refresh(t *testing.T) {
+	providerMock := &MockProvider{}
+	providerMock.On("Load", mock.Anything).Return(map[string]interface{}{"a": 1, "b": 2}, nil).Once()
+	providerMock.On("Load", mock.Anything).Return(map[string]interface{}{"a": 1, "c": "3"}, nil).Once()
+	providerMock.On("Load", mock.Anything).Return(nil, assert.AnError).Once()
+
+	s, err := NewDynamicStorage(context.Background(), providerMock, LenientConversion())
+	assert.Nil(t, err)
+
+	var aCalls, bCalls int
+	var bValue Value = overwriteValue{}
+	s.Watch("a", func(Value) { aCalls++ })
+	s.Watch("b", func(v Value) {
+		bCalls++
+		bValue = v
+	})
+
+	var cValue Value
+	cancel := s.Watch("c", func(v Value) { cValue = v })
+
+	assert.Nil(t, s.Refresh(context.Background()))
+	assert.Equal(t, 0, aCalls)
+	assert.Equal(t, 1, bCalls)
+	assert.Nil(t, bValue)
+	assert.Equal(t, int64(3), cValue.AsInt())
+	assert.Equal(t, []string{"a", "c"}, s.Keys())
+
+	cancel()
+
+	assert.Equal(t, assert.AnError, s.Refresh(context.Background()))
+	assert.Equal(t, []string{"a", "c"}, s.Keys())
+	mock.AssertExpectationsForObjects(t, providerMock)
+}
+
+func TestWithDynamicStorage(t *testing.T) {
+	providerMock := &MockProvider{}
+	providerMock.On("Load", mock.Anything).Return(map[string]interface{}{"a": 1}, nil).Once()
+	providerMock.On("Load", mock.Anything).Return(map[string]interface{}{"a": 2}, nil).Once()
+
+	s, err := NewDynamicStorage(context.Background(), providerMock)
+	assert.Nil(t, err)
+
+	ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 0, "b": 0})
+	ctx = WithDynamicStorage(ctx, s)
+
+	assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+	assert.Equal(t, 0, GetOverwrittenValue(ctx, "b").AsIs())
+	assert.Equal(t, []string{"a", "b"}, ExtractOverwritingStorageV2(ctx).Keys())
+
+	assert.Nil(t, s.Refresh(context.Background()))
+	assert.Equal(t, 2, GetOverwrittenValue(ctx, "a").AsIs())
+}
@@ -0,0 +1,182 @@
+package dvow
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/jamestrandung/go-context/helper"
+)
+
+type mutableStorageKeyType struct{}
+
+var mutableStorageKey = mutableStorageKeyType{}
+
+// WithMutableOverwrittenVariables returns a new context.Context that holds a reference
+// to a thread-safe mutable Storage initialized with the given overwritten variables.
+// Variables in this Storage can be changed at runtime via SetOverwrittenValue and
+// UnsetOverwrittenValue without rebuilding the context, which is useful for long-lived
+// daemon contexts where operators flip overwrites at runtime. Use OnChange to react to
+// such changes.
+func WithMutableOverwrittenVariables(
+	ctx context.Context,
+	overwrittenVariables map[string]interface{},
+	opts ...Option,
+) context.Context {
+	o := newOptions(opts...)
+
+	variables := prepareVariables(overwrittenVariables, o)
+	if variables == nil {
+		variables = make(map[string]interface{})
+	}
+
+	s := &mutableStorage{
+		variables: variables,
+		options:   o,
+	}
+
+	derivedStorage := chainedStorage{
+		current: s,
+		parent:  Ops.ExtractOverwritingStorage(ctx),
+	}
+
+	ctx = context.WithValue(ctx, mutableStorageKey, s)
+	ctx = context.WithValue(ctx, watchableStorageKey, s)
+	return context.WithValue(ctx, overwritingStorageKey, derivedStorage)
+}
+
+// SetOverwrittenValue overwrites the variable under this name with the given value in
+// the nearest mutable Storage of ctx. All contexts sharing this Storage see the change
+// immediately. It returns ErrImmutableStorage if ctx was not initialized using
+// WithMutableOverwrittenVariables, or a Violation if the value does not satisfy the
+// Schema given to WithMutableOverwrittenVariables.
+func SetOverwrittenValue(ctx context.Context, name string, value interface{}) error {
+	s, ok := ctx.Value(mutableStorageKey).(*mutableStorage)
+	if !ok {
+		return ErrImmutableStorage
+	}
+
+	return s.set(name, value)
+}
+
+// UnsetOverwrittenValue removes the variable under this name from the nearest mutable
+// Storage of ctx. It returns ErrImmutableStorage if ctx was not initialized using
+// WithMutableOverwrittenVariables.
+func UnsetOverwrittenValue(ctx context.Context, name string) error {
+	s, ok := ctx.Value(mutableStorageKey).(*mutableStorage)
+	if !ok {
+		return ErrImmutableStorage
+	}
+
+	s.unset(name)
+
+	return nil
+}
+
+// mutableStorage is a Storage whose variables can be changed concurrently.
+type mutableStorage struct {
+	mu        sync.RWMutex
+	variables map[string]interface{}
+	options   options
+	watchers  watcherSet
+}
+
+// Get returns the Value of the variable under this name if it was overwritten
+func (s *mutableStorage) Get(name string) Value {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, isPresent := s.variables[name]
+	if !isPresent {
+		return nil
+	}
+
+	return s.options.wrap(value)
+}
+
+// Keys returns the sorted names of all variables that were overwritten in this Storage.
+func (s *mutableStorage) Keys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.variables))
+	for name := range s.variables {
+		keys = append(keys, name)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+func (s *mutableStorage) set(name string, value interface{}) error {
+	if s.options.schema != nil {
+		if violations := s.options.schema.Validate(map[string]interface{}{name: value}); len(violations) > 0 {
+			if s.options.reportViolations != nil {
+				s.options.reportViolations(violations)
+			}
+
+			return violations[0]
+		}
+	}
+
+	if s.options.deepCopy {
+		value = helper.DeepCopy(value)
+	}
+
+	s.mu.Lock()
+
+	oldValue, wasPresent := s.variables[name]
+	s.variables[name] = value
+
+	var notifications []func()
+	if !wasPresent || !reflect.DeepEqual(oldValue, value) {
+		notifications = s.watchers.notifications(name, s.options.wrap(value))
+	}
+
+	s.mu.Unlock()
+
+	notify(notifications)
+
+	return nil
+}
+
+func (s *mutableStorage) unset(name string) {
+	s.mu.Lock()
+
+	_, wasPresent := s.variables[name]
+	delete(s.variables, name)
+
+	var notifications []func()
+	if wasPresent {
+		notifications = s.watchers.notifications(name, nil)
+	}
+
+	s.mu.Unlock()
+
+	notify(notifications)
+}
+
+// Watch registers fn to be called with the new Value of the variable under this name
+// whenever it changes via SetOverwrittenValue or UnsetOverwrittenValue. The Value is nil
+// if the variable is no longer overwritten. The returned function cancels this
+// subscription.
+func (s *mutableStorage) Watch(name string, fn func(Value)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.watchers.add(name, fn)
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		s.watchers.remove(name, id)
+	}
+}
+
+// metricsHook returns the MetricsHook of this Storage, if any.
+func (s *mutableStorage) metricsHook() MetricsHook {
+	return s.options.metricsHook
+}
@@ -0,0 +1,93 @@
+package dvow
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMutableOverwrittenVariables(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "context without mutable storage",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+				assert.Equal(t, ErrImmutableStorage, SetOverwrittenValue(ctx, "a", 2))
+				assert.Equal(t, ErrImmutableStorage, UnsetOverwrittenValue(ctx, "a"))
+			},
+		},
+		{
+			desc: "changes are visible in all derived contexts",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 0, "b": 0})
+				ctx = WithMutableOverwrittenVariables(ctx, map[string]interface{}{"a": 1}, LenientConversion())
+				child := WithOverwrittenVariables(ctx, map[string]interface{}{"c": 3})
+
+				assert.Equal(t, 1, GetOverwrittenValue(child, "a").AsIs())
+
+				assert.Nil(t, SetOverwrittenValue(ctx, "a", "2"))
+				assert.Nil(t, SetOverwrittenValue(child, "b", 2))
+
+				assert.Equal(t, int64(2), GetOverwrittenValue(child, "a").AsInt())
+				assert.Equal(t, 2, GetOverwrittenValue(ctx, "b").AsIs())
+				assert.Equal(t, []string{"a", "b", "c"}, ExtractOverwritingStorageV2(child).Keys())
+
+				assert.Nil(t, UnsetOverwrittenValue(child, "b"))
+				assert.Equal(t, 0, GetOverwrittenValue(child, "b").AsIs())
+			},
+		},
+		{
+			desc: "schema is enforced",
+			test: func(t *testing.T) {
+				schema := NewSchema()
+				schema.Register("a", Rule{Kind: reflect.String})
+
+				ctx := WithMutableOverwrittenVariables(context.Background(), nil, WithSchema(schema, DropInvalid, nil))
+
+				err := SetOverwrittenValue(ctx, "a", 1)
+				assert.True(t, errors.Is(err, ErrUnexpectedKind))
+				assert.Nil(t, GetOverwrittenValue(ctx, "a"))
+			},
+		},
+		{
+			desc: "concurrent access",
+			test: func(t *testing.T) {
+				ctx := WithMutableOverwrittenVariables(context.Background(), nil)
+
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(2)
+
+					go func(i int) {
+						defer wg.Done()
+						assert.Nil(t, SetOverwrittenValue(ctx, "a", i))
+					}(i)
+
+					go func() {
+						defer wg.Done()
+						GetOverwrittenValue(ctx, "a")
+					}()
+				}
+
+				wg.Wait()
+
+				assert.NotNil(t, GetOverwrittenValue(ctx, "a"))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package dvow
 
 import (
+    "context"
     "github.com/stretchr/testify/assert"
     "github.com/stretchr/testify/mock"
     "testing"
+    "time"
 )
 
 func TestDynamicOverwritingStorage_Get(t *testing.T) {
@@ -35,5 +37,79 @@ func TestDynamicOverwritingStorage_Get(t *testing.T) {
 
     assert.Nil(t, value3)
 
+    storage.variables["nil_value"] = nil
+    value4 := storage.Get("nil_value")
+
+    assert.NotNil(t, value4, "explicitly-nil overwrites must be distinguishable from absent ones")
+    assert.True(t, value4.IsPresent())
+    assert.True(t, value4.IsNil())
+
     mock.AssertExpectationsForObjects(t, storageMock)
-}
\ No newline at end of file
+}
+
+func TestDynamicOverwritingStorage_Keys(t *testing.T) {
+    storageMock := &MockStorageV2{}
+    storageMock.On("Keys").Return([]string{"a", "b"}).Once()
+
+    storage := dynamicOverwritingStorage{
+        parent: storageMock,
+        variables: map[string]interface{}{
+            "b": 1,
+            "c": 2,
+        },
+    }
+
+    assert.Equal(t, []string{"a", "b", "c"}, storage.Keys())
+    mock.AssertExpectationsForObjects(t, storageMock)
+
+    storage = dynamicOverwritingStorage{
+        variables: map[string]interface{}{
+            "b": 1,
+        },
+    }
+
+    assert.Equal(t, []string{"b"}, storage.Keys())
+}
+
+func TestNewDynamicOverwritingStorage_Flatten(t *testing.T) {
+    defer func(original int) {
+        maxChainDepth = original
+    }(maxChainDepth)
+
+    maxChainDepth = 3
+
+    ctx := context.Background()
+    ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"a": "1", "b": "1"}, LenientConversion())
+    ctx = WithOverwrittenVariablesTTL(ctx, map[string]interface{}{"ttl": 1}, time.Hour)
+    ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"c": 1})
+    ctx = WithoutOverwrittenVariables(ctx, "c")
+    ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"d": 1})
+    ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"b": 2})
+    ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"e": 1})
+
+    storage := ExtractOverwritingStorage(ctx).(dynamicOverwritingStorage)
+
+    chainLength := 0
+    for current := Storage(storage); current != nil; current = current.(dynamicOverwritingStorage).parent {
+        chainLength++
+    }
+
+    assert.True(t, chainLength < 7, "chain must have been flattened")
+    assert.Equal(t, int64(1), storage.Get("a").AsInt(), "lenient option must be preserved")
+    assert.Equal(t, 2, storage.Get("b").AsIs())
+    assert.Nil(t, storage.Get("c"))
+    assert.Equal(t, 1, storage.Get("d").AsIs())
+    assert.Equal(t, 1, storage.Get("ttl").AsIs())
+    assert.Equal(t, []string{"a", "b", "d", "e", "ttl"}, storage.Keys())
+
+    defer func(original func() time.Time) {
+        timeNow = original
+    }(timeNow)
+
+    timeNow = func() time.Time {
+        return time.Now().Add(2 * time.Hour)
+    }
+
+    assert.Nil(t, storage.Get("ttl"), "expiry of unflattened ancestors must be respected")
+    assert.Equal(t, []string{"a", "b", "d", "e"}, storage.Keys())
+}
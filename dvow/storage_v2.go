@@ -0,0 +1,103 @@
+package dvow
+
+import (
+	"context"
+)
+
+// StorageV2 extends Storage with capabilities that newer APIs of this package rely on.
+// Third-party Storage implementations do not need to implement it as the package wraps
+// them using UpgradeStorage whenever these capabilities are needed. Such Storage only
+// get enumerated by Keys and Snapshot if they implement KeyedStorage.
+//
+//go:generate mockery --name StorageV2 --case underscore --inpkg
+type StorageV2 interface {
+	Storage
+	KeyedStorage
+	// Has returns whether the variable under this name was overwritten.
+	Has(name string) bool
+	// Snapshot returns all variables that were overwritten in this Storage, including
+	// those inherited from parent Storage, keyed by their names.
+	Snapshot() map[string]interface{}
+}
+
+// UpgradeStorage returns the given Storage as a StorageV2. Storage that already
+// implement StorageV2 are returned as is, others are wrapped in an adapter implementing
+// the new methods on top of Get, and of Keys if they implement KeyedStorage. It returns
+// nil if storage is nil.
+func UpgradeStorage(storage Storage) StorageV2 {
+	if storage == nil {
+		return nil
+	}
+
+	if v2, ok := storage.(StorageV2); ok {
+		return v2
+	}
+
+	return storageV2Adapter{
+		Storage: storage,
+	}
+}
+
+// ExtractOverwritingStorageV2 works like ExtractOverwritingStorage except that the
+// Storage is upgraded using UpgradeStorage.
+func ExtractOverwritingStorageV2(ctx context.Context) StorageV2 {
+	return UpgradeStorage(Ops.ExtractOverwritingStorage(ctx))
+}
+
+// storageV2Adapter implements StorageV2 on top of a legacy Storage.
+type storageV2Adapter struct {
+	Storage
+}
+
+// Keys returns the sorted names of all variables that were overwritten in this Storage,
+// nil if it does not implement KeyedStorage since they cannot be enumerated.
+func (a storageV2Adapter) Keys() []string {
+	return keysOf(a.Storage)
+}
+
+// Has returns whether the variable under this name was overwritten.
+func (a storageV2Adapter) Has(name string) bool {
+	return a.Get(name) != nil
+}
+
+// Snapshot returns all variables that were overwritten in this Storage.
+func (a storageV2Adapter) Snapshot() map[string]interface{} {
+	return snapshotStorage(a)
+}
+
+// Has returns whether the variable under this name was overwritten.
+func (s dynamicOverwritingStorage) Has(name string) bool {
+	if value, isPresent := s.variables[name]; isPresent && !s.isExpired() {
+		_, isMasked := value.(tombstone)
+		return !isMasked
+	}
+
+	if value, isPresent := s.inherited[name]; isPresent {
+		return value != nil
+	}
+
+	if s.parent != nil {
+		return UpgradeStorage(s.parent).Has(name)
+	}
+
+	return false
+}
+
+// Snapshot returns all variables that were overwritten in this Storage, including
+// those inherited from parent Storage.
+func (s dynamicOverwritingStorage) Snapshot() map[string]interface{} {
+	return snapshotStorage(s)
+}
+
+func snapshotStorage(storage StorageV2) map[string]interface{} {
+	keys := storage.Keys()
+
+	snapshot := make(map[string]interface{}, len(keys))
+	for _, name := range keys {
+		if value := storage.Get(name); value != nil {
+			snapshot[name] = value.AsIs()
+		}
+	}
+
+	return snapshot
+}
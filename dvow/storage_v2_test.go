@@ -0,0 +1,102 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type keyedTestStorage struct {
+	*MockStorage
+	keys []string
+}
+
+func (s keyedTestStorage) Keys() []string {
+	return s.keys
+}
+
+func TestUpgradeStorage(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "nil storage",
+			test: func(t *testing.T) {
+				assert.Nil(t, UpgradeStorage(nil))
+				assert.Nil(t, ExtractOverwritingStorageV2(context.Background()))
+			},
+		},
+		{
+			desc: "storage implementing StorageV2",
+			test: func(t *testing.T) {
+				storageMock := &MockStorageV2{}
+				assert.Equal(t, storageMock, UpgradeStorage(storageMock))
+			},
+		},
+		{
+			desc: "legacy storage",
+			test: func(t *testing.T) {
+				storageMock := &MockStorage{}
+				storageMock.On("Get", "a").Return(NewStorage(map[string]interface{}{"a": 1}).Get("a"))
+				storageMock.On("Get", "b").Return(nil)
+
+				upgraded := UpgradeStorage(storageMock)
+				assert.Equal(t, storageV2Adapter{Storage: storageMock}, upgraded)
+				assert.True(t, upgraded.Has("a"))
+				assert.False(t, upgraded.Has("b"))
+				assert.Nil(t, upgraded.Keys(), "storage that cannot enumerate variables have no keys")
+				assert.Equal(t, map[string]interface{}{}, upgraded.Snapshot())
+			},
+		},
+		{
+			desc: "legacy storage implementing KeyedStorage",
+			test: func(t *testing.T) {
+				storageMock := &MockStorage{}
+				storageMock.On("Get", "a").Return(NewStorage(map[string]interface{}{"a": 1}).Get("a"))
+
+				storage := keyedTestStorage{MockStorage: storageMock, keys: []string{"a"}}
+				ctx := WithOverwritingStorage(context.Background(), storage)
+
+				upgraded := UpgradeStorage(storage)
+				assert.Equal(t, []string{"a"}, upgraded.Keys())
+				assert.Equal(t, map[string]interface{}{"a": 1}, upgraded.Snapshot())
+				assert.Equal(t, map[string]interface{}{"a": 1}, Snapshot(ctx))
+				assert.Equal(t, []string{"a"}, ReportUsage(ctx).Set)
+			},
+		},
+		{
+			desc: "dynamic overwriting storage",
+			test: func(t *testing.T) {
+				storageMock := &MockStorageV2{}
+				storageMock.On("Get", "root").Return(NewStorage(map[string]interface{}{"root": 1}).Get("root"))
+				storageMock.On("Get", "masked").Return(NewStorage(map[string]interface{}{"masked": 1}).Get("masked"))
+				storageMock.On("Get", "missing").Return(nil)
+				storageMock.On("Keys").Return([]string{"masked", "root"})
+				storageMock.On("Has", "root").Return(true)
+				storageMock.On("Has", "missing").Return(false)
+
+				ctx := WithOverwritingStorage(context.Background(), storageMock)
+				ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"a": 1})
+				ctx = WithoutOverwrittenVariables(ctx, "masked")
+
+				storage := ExtractOverwritingStorageV2(ctx)
+				assert.IsType(t, dynamicOverwritingStorage{}, storage)
+				assert.True(t, storage.Has("a"))
+				assert.True(t, storage.Has("root"))
+				assert.False(t, storage.Has("masked"))
+				assert.False(t, storage.Has("missing"))
+				assert.Equal(t, map[string]interface{}{"a": 1, "root": 1}, storage.Snapshot())
+				assert.Equal(t, storage.Snapshot(), Snapshot(ctx))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
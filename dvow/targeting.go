@@ -0,0 +1,180 @@
+package dvow
+
+import (
+	"context"
+	"sort"
+)
+
+// Attributes describe the context a request runs in, e.g. tenant, country or app version.
+type Attributes map[string]string
+
+// AttributesExtractor returns the Attributes of the given context.
+type AttributesExtractor func(ctx context.Context) Attributes
+
+// Matcher decides whether a TargetingRule applies to the given Attributes.
+type Matcher func(attributes Attributes) bool
+
+// MatchAttribute returns a Matcher that matches Attributes in which the attribute under
+// this name equals any of the given values.
+func MatchAttribute(name string, values ...string) Matcher {
+	return func(attributes Attributes) bool {
+		actual, ok := attributes[name]
+		if !ok {
+			return false
+		}
+
+		for _, value := range values {
+			if actual == value {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchAll returns a Matcher that matches Attributes matched by all given matchers.
+func MatchAll(matchers ...Matcher) Matcher {
+	return func(attributes Attributes) bool {
+		for _, matcher := range matchers {
+			if !matcher(attributes) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// MatchAny returns a Matcher that matches Attributes matched by any of the given matchers.
+func MatchAny(matchers ...Matcher) Matcher {
+	return func(attributes Attributes) bool {
+		for _, matcher := range matchers {
+			if matcher(attributes) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// TargetingRule overwrites a variable with Value if Matcher matches the Attributes
+// of the context.
+type TargetingRule struct {
+	Matcher Matcher
+	Value   interface{}
+}
+
+// Targeting lists the TargetingRule of a variable, which are evaluated in order.
+type Targeting struct {
+	Rules []TargetingRule
+	// Default is used if none of the Rules matches. If it is nil, the variable is
+	// not overwritten in this case.
+	Default interface{}
+}
+
+// evaluate returns the value of the first TargetingRule matching the given Attributes,
+// or Default if none of them matches.
+func (t Targeting) evaluate(attributes Attributes) (interface{}, bool) {
+	for _, rule := range t.Rules {
+		if rule.Matcher(attributes) {
+			return rule.Value, true
+		}
+	}
+
+	return t.Default, t.Default != nil
+}
+
+// WithTargeting returns a new context.Context in which the variables under the keys of
+// the given map are overwritten according to their Targeting, evaluated against the
+// Attributes extracted from ctx. This is effectively a lightweight targeting engine,
+// e.g. to roll out per-tenant overwrites. The variables are recorded as coming from
+// SourceFlag unless another Source is given.
+//
+// The given map is copied, hence it can be reused or modified afterward. Rules without
+// a Matcher are skipped since they could never be evaluated.
+func WithTargeting(
+	ctx context.Context,
+	extractor AttributesExtractor,
+	targetings map[string]Targeting,
+	opts ...Option,
+) context.Context {
+	if len(targetings) == 0 {
+		return ctx
+	}
+
+	derivedStorage := chainedStorage{
+		current: targetingStorage{
+			attributes: extractor(ctx),
+			targetings: cloneTargetings(targetings),
+			options:    newOptions(append([]Option{WithSource(SourceFlag)}, opts...)...),
+		},
+		parent: Ops.ExtractOverwritingStorage(ctx),
+	}
+
+	return context.WithValue(ctx, overwritingStorageKey, derivedStorage)
+}
+
+// cloneTargetings returns a copy of the given map, in which rules without a Matcher
+// were dropped.
+func cloneTargetings(targetings map[string]Targeting) map[string]Targeting {
+	clone := make(map[string]Targeting, len(targetings))
+	for name, targeting := range targetings {
+		rules := make([]TargetingRule, 0, len(targeting.Rules))
+		for _, rule := range targeting.Rules {
+			if rule.Matcher != nil {
+				rules = append(rules, rule)
+			}
+		}
+
+		clone[name] = Targeting{
+			Rules:   rules,
+			Default: targeting.Default,
+		}
+	}
+
+	return clone
+}
+
+// targetingStorage evaluates the Targeting of its variables against the same
+// Attributes on every lookup.
+type targetingStorage struct {
+	attributes Attributes
+	targetings map[string]Targeting
+	options    options
+}
+
+// Get returns the Value of the variable under this name if it was overwritten
+func (s targetingStorage) Get(name string) Value {
+	targeting, ok := s.targetings[name]
+	if !ok {
+		return nil
+	}
+
+	value, ok := targeting.evaluate(s.attributes)
+	if !ok {
+		return nil
+	}
+
+	return s.options.wrap(value)
+}
+
+// Keys returns the sorted names of all variables that were overwritten in this Storage.
+func (s targetingStorage) Keys() []string {
+	keys := make([]string, 0, len(s.targetings))
+	for name, targeting := range s.targetings {
+		if _, ok := targeting.evaluate(s.attributes); ok {
+			keys = append(keys, name)
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// metricsHook returns the MetricsHook of this Storage, if any.
+func (s targetingStorage) metricsHook() MetricsHook {
+	return s.options.metricsHook
+}
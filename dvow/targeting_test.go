@@ -0,0 +1,120 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantKey struct{}
+
+func TestMatchers(t *testing.T) {
+	attributes := Attributes{"tenant": "a", "country": "sg"}
+
+	assert.True(t, MatchAttribute("tenant", "b", "a")(attributes))
+	assert.False(t, MatchAttribute("tenant", "b")(attributes))
+	assert.False(t, MatchAttribute("missing", "")(attributes))
+
+	assert.True(t, MatchAll(MatchAttribute("tenant", "a"), MatchAttribute("country", "sg"))(attributes))
+	assert.False(t, MatchAll(MatchAttribute("tenant", "a"), MatchAttribute("country", "vn"))(attributes))
+	assert.True(t, MatchAll()(attributes))
+
+	assert.True(t, MatchAny(MatchAttribute("tenant", "b"), MatchAttribute("country", "sg"))(attributes))
+	assert.False(t, MatchAny(MatchAttribute("tenant", "b"), MatchAttribute("country", "vn"))(attributes))
+	assert.False(t, MatchAny()(attributes))
+}
+
+func TestWithTargeting(t *testing.T) {
+	extractor := func(ctx context.Context) Attributes {
+		tenant, _ := ctx.Value(tenantKey{}).(string)
+		return Attributes{"tenant": tenant}
+	}
+
+	targetings := map[string]Targeting{
+		"fee": {
+			Rules: []TargetingRule{
+				{Matcher: MatchAttribute("tenant", "a"), Value: 1},
+				{Matcher: MatchAttribute("tenant", "a", "b"), Value: 2},
+			},
+			Default: 3,
+		},
+		"beta": {
+			Rules: []TargetingRule{
+				{Matcher: MatchAttribute("tenant", "b"), Value: true},
+			},
+		},
+	}
+
+	scenarios := []struct {
+		desc         string
+		tenant       string
+		expectedFee  interface{}
+		expectedBeta Value
+		expectedKeys []string
+	}{
+		{
+			desc:         "first matching rule wins",
+			tenant:       "a",
+			expectedFee:  1,
+			expectedBeta: nil,
+			expectedKeys: []string{"fee"},
+		},
+		{
+			desc:         "second rule",
+			tenant:       "b",
+			expectedFee:  2,
+			expectedBeta: overwriteValue{value: true, source: SourceFlag},
+			expectedKeys: []string{"beta", "fee"},
+		},
+		{
+			desc:         "default",
+			tenant:       "c",
+			expectedFee:  3,
+			expectedBeta: nil,
+			expectedKeys: []string{"fee"},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			ctx := context.WithValue(context.Background(), tenantKey{}, sc.tenant)
+			ctx = WithOverwrittenVariables(ctx, map[string]interface{}{"parent": 0, "beta": false})
+			ctx = WithTargeting(ctx, extractor, targetings)
+
+			assert.Equal(t, sc.expectedFee, GetOverwrittenValue(ctx, "fee").AsIs())
+			assert.Equal(t, 0, GetOverwrittenValue(ctx, "parent").AsIs())
+			assert.Equal(t, sc.expectedKeys, UpgradeStorage(ExtractOverwritingStorage(ctx).(chainedStorage).current).Keys())
+			assert.Equal(t, []string{"beta", "fee", "parent"}, ExtractOverwritingStorageV2(ctx).Keys())
+			if sc.expectedBeta == nil {
+				assert.Equal(t, false, GetOverwrittenValue(ctx, "beta").AsIs(), "parent must be consulted")
+			} else {
+				assert.Equal(t, sc.expectedBeta, GetOverwrittenValue(ctx, "beta"))
+			}
+		})
+	}
+}
+
+func TestWithTargeting_Isolation(t *testing.T) {
+	extractor := func(ctx context.Context) Attributes {
+		return Attributes{"tenant": "a"}
+	}
+
+	targetings := map[string]Targeting{
+		"fee": {
+			Rules: []TargetingRule{
+				{Matcher: nil, Value: 0},
+				{Matcher: MatchAttribute("tenant", "a"), Value: 1},
+			},
+		},
+	}
+
+	ctx := WithTargeting(context.Background(), extractor, targetings)
+
+	targetings["fee"].Rules[1] = TargetingRule{Matcher: MatchAttribute("tenant", "a"), Value: 2}
+	targetings["beta"] = Targeting{Default: true}
+
+	assert.Equal(t, 1, GetOverwrittenValue(ctx, "fee").AsIs(), "rules without Matcher must be skipped")
+	assert.Nil(t, GetOverwrittenValue(ctx, "beta"), "later changes to the map must not be visible")
+}
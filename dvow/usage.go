@@ -0,0 +1,64 @@
+package dvow
+
+import (
+	"context"
+	"strings"
+)
+
+// UsageReport summarizes how the overwrites of a request were used, e.g. to feed
+// experiment hygiene dashboards.
+type UsageReport struct {
+	// Set holds the sorted names of all variables effectively overwritten.
+	Set []string
+	// Read holds the sorted names of the variables in Set that were looked up.
+	Read []string
+	// Unused holds the sorted names of the variables in Set that were never looked up.
+	Unused []string
+}
+
+// ReportUsage returns which overwrites are set in the given context and which of them
+// were looked up so far. It should be called when the request finishes, e.g. in a
+// middleware, and requires the access log to have been enabled using WithAccessLog
+// before any lookup. Otherwise, all overwrites are reported as unused.
+//
+// Looking up a dot-path such as "pricing.surge" counts as reading the variable that
+// holds the whole object, e.g. "pricing".
+func ReportUsage(ctx context.Context) UsageReport {
+	var set []string
+	if storage := ExtractOverwritingStorageV2(ctx); storage != nil {
+		set = storage.Keys()
+	}
+
+	isRead := make(map[string]bool)
+	for _, access := range AccessLog(ctx) {
+		if access.Overwritten {
+			isRead[access.Name] = true
+		}
+	}
+
+	report := UsageReport{
+		Set: set,
+	}
+
+	for _, name := range set {
+		if isRead[name] || isPathRead(isRead, name) {
+			report.Read = append(report.Read, name)
+			continue
+		}
+
+		report.Unused = append(report.Unused, name)
+	}
+
+	return report
+}
+
+// isPathRead returns whether a dot-path inside the variable under this name was read.
+func isPathRead(isRead map[string]bool, name string) bool {
+	for path := range isRead {
+		if strings.HasPrefix(path, name+pathSeparator) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportUsage(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no overwrites",
+			test: func(t *testing.T) {
+				assert.Equal(t, UsageReport{}, ReportUsage(WithAccessLog(context.Background())))
+			},
+		},
+		{
+			desc: "without access log",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+				GetOverwrittenValue(ctx, "a")
+
+				assert.Equal(
+					t, UsageReport{
+						Set:    []string{"a"},
+						Unused: []string{"a"},
+					}, ReportUsage(ctx),
+				)
+			},
+		},
+		{
+			desc: "with access log",
+			test: func(t *testing.T) {
+				ctx := WithAccessLog(context.Background())
+				ctx = WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"a":       1,
+						"b":       2,
+						"pricing": map[string]interface{}{"surge": 1.5},
+						"unused":  3,
+					},
+				)
+
+				GetOverwrittenValue(ctx, "a")
+				GetOverwrittenValue(WithOverwrittenVariables(ctx, map[string]interface{}{"b": 3}), "b")
+				GetOverwrittenValue(ctx, "pricing.surge")
+				GetOverwrittenValue(ctx, "missing")
+
+				assert.Equal(
+					t, UsageReport{
+						Set:    []string{"a", "b", "pricing", "unused"},
+						Read:   []string{"a", "b", "pricing"},
+						Unused: []string{"unused"},
+					}, ReportUsage(ctx),
+				)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
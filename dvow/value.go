@@ -2,10 +2,19 @@ package dvow
 
 import (
 	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jamestrandung/go-context/helper"
+	"github.com/pkg/errors"
 )
 
-//go:generate mockery --name Value --case underscore --inpkg
 // Value wraps a raw interface{} value
+//
+//go:generate mockery --name Value --case underscore --inpkg
 type Value interface {
 	// AsIs returns the wrapped value as-is.
 	AsIs() interface{}
@@ -21,10 +30,91 @@ type Value interface {
 	// NOTE: JSON by default unmarshal to numbers which are treated as float.
 	// Using this method, your float will lose precision as an int64.
 	AsInt() int64
+	// AsUint typecast to uint64. Returns zero value if not possible to cast, including
+	// negative numbers.
+	AsUint() uint64
+	// AsStringSlice typecast to []string. Returns nil if not possible to cast.
+	AsStringSlice() []string
+	// AsIntSlice typecast to []int64. Returns nil if not possible to cast.
+	AsIntSlice() []int64
+	// AsFloatSlice typecast to []float64. Returns nil if not possible to cast.
+	AsFloatSlice() []float64
+	// AsMap typecast to map[string]interface{}. Returns nil if not possible to cast.
+	AsMap() map[string]interface{}
+	// AsDuration typecast to time.Duration. Strings are parsed using time.ParseDuration
+	// (e.g. "1500ms") while numbers are treated as milliseconds. Returns zero value if
+	// not possible to cast.
+	AsDuration() time.Duration
+	// AsTime typecast to time.Time. Strings are parsed using the given layouts or RFC3339
+	// if none is given while numbers are treated as Unix timestamps in seconds, or in
+	// milliseconds if they are too large to be seconds. Returns zero value if not possible
+	// to cast.
+	AsTime(layouts ...string) time.Time
+	// AsStringE typecast to string. Returns ErrTypeMismatch if not possible to cast.
+	AsStringE() (string, error)
+	// AsBoolE typecast to bool. Returns ErrTypeMismatch if not possible to cast.
+	AsBoolE() (bool, error)
+	// AsFloatE typecast to float64. Returns ErrTypeMismatch if not possible to cast, or
+	// ErrPrecisionLoss along with the nearest float64 if the wrapped value is an integer
+	// too large to be represented exactly.
+	AsFloatE() (float64, error)
+	// AsIntE typecast to int64. Returns ErrTypeMismatch if not possible to cast,
+	// ErrOverflow if the wrapped value is outside the range of int64, or ErrPrecisionLoss
+	// along with the truncated result if it has a fractional part.
+	AsIntE() (int64, error)
+	// AsUintE typecast to uint64. Returns ErrTypeMismatch if not possible to cast,
+	// ErrOverflow if the wrapped value is outside the range of uint64, or ErrPrecisionLoss
+	// along with the truncated result if it has a fractional part.
+	AsUintE() (uint64, error)
+	// AsDurationE typecast to time.Duration similar to AsDuration. Returns ErrTypeMismatch
+	// if not possible to cast.
+	AsDurationE() (time.Duration, error)
+	// AsTimeE typecast to time.Time similar to AsTime. Returns ErrTypeMismatch if not
+	// possible to cast.
+	AsTimeE(layouts ...string) (time.Time, error)
+	// IsPresent returns whether the variable was overwritten. It is false only for the
+	// Value wrapping the fallback given to GetOrDefault when the variable was absent.
+	IsPresent() bool
+	// IsNil returns whether the wrapped value is nil, e.g. when the variable was
+	// explicitly overwritten to null.
+	IsNil() bool
+	// IsZero returns whether the wrapped value is nil or the zero value of its type,
+	// e.g. when the variable was explicitly overwritten to 0 or "".
+	IsZero() bool
+	// Kind returns the reflect.Kind of the wrapped value, reflect.Invalid if it is nil.
+	Kind() reflect.Kind
+	// TypeName returns the name of the type of the wrapped value, e.g. "int64" or
+	// "map[string]interface {}", or "nil" if it is nil.
+	TypeName() string
 }
 
 type overwriteValue struct {
 	value interface{}
+	// lenient indicates whether string representations should be parsed.
+	lenient bool
+	// isFallback indicates whether this Value wraps a fallback instead of an
+	// overwritten value.
+	isFallback bool
+	// source is where the wrapped value came from.
+	source Source
+	// expressions evaluates the wrapped value if it is an Expression, if not nil.
+	expressions *expressionConfig
+}
+
+// deriveValue returns a Value wrapping the given raw value that behaves the same
+// way as the given Value, e.g. for a leaf nested inside an overwritten variable.
+func deriveValue(from Value, value interface{}) Value {
+	derived := overwriteValue{
+		value: value,
+	}
+
+	if ov, ok := from.(overwriteValue); ok {
+		derived.lenient = ov.lenient
+		derived.source = ov.source
+		derived.expressions = ov.expressions
+	}
+
+	return derived
 }
 
 // AsIs returns the wrapped value as-is.
@@ -32,80 +122,424 @@ func (v overwriteValue) AsIs() interface{} {
 	return v.value
 }
 
-// AsString typecast to string. Returns zero value if not possible to cast.
-func (v overwriteValue) AsString() (result string) {
-	if castedValue, ok := (v.value).(string); ok {
-		result = castedValue
-	}
+// IsPresent returns whether the variable was overwritten. It is false only for the
+// Value wrapping the fallback given to GetOrDefault when the variable was absent.
+func (v overwriteValue) IsPresent() bool {
+	return !v.isFallback
+}
 
-	return
+// IsNil returns whether the wrapped value is nil, e.g. when the variable was
+// explicitly overwritten to null.
+func (v overwriteValue) IsNil() bool {
+	return helper.IsNil(v.value)
 }
 
-// AsBool typecast to bool. Returns zero value if not possible to cast.
-func (v overwriteValue) AsBool() (result bool) {
-	if castedValue, ok := (v.value).(bool); ok {
-		result = castedValue
-	}
+// IsZero returns whether the wrapped value is nil or the zero value of its type,
+// e.g. when the variable was explicitly overwritten to 0 or "".
+func (v overwriteValue) IsZero() bool {
+	return helper.IsZeroValue(v.value)
+}
+
+// Source returns where the wrapped value came from.
+func (v overwriteValue) Source() Source {
+	return v.source
+}
+
+// Kind returns the reflect.Kind of the wrapped value, reflect.Invalid if it is nil.
+func (v overwriteValue) Kind() reflect.Kind {
+	return reflect.ValueOf(v.value).Kind()
+}
+
+// TypeName returns the name of the type of the wrapped value, e.g. "int64" or
+// "map[string]interface {}", or "nil" if it is nil.
+func (v overwriteValue) TypeName() string {
+	return helper.NameOf(v.value)
+}
 
-	return
+// AsString typecast to string. Returns zero value if not possible to cast.
+func (v overwriteValue) AsString() string {
+	result, _ := castString(v.value)
+	return result
+}
+
+// AsBool typecast to bool. Returns zero value if not possible to cast.
+func (v overwriteValue) AsBool() bool {
+	result, _ := v.castBool(v.value)
+	return result
 }
 
 // AsFloat typecast to float64. Returns zero value if not possible to cast.
 // Note: Try not to use a raw value of type float32 if possible.
 // https://stackoverflow.com/questions/67145364/golang-losing-precision-while-converting-float32-to-float64
-func (v overwriteValue) AsFloat() (result float64) {
-	switch v.value.(type) {
-	case int:
-		result = float64(v.value.(int))
-	case int8:
-		result = float64(v.value.(int8))
-	case int16:
-		result = float64(v.value.(int16))
-	case int32:
-		result = float64(v.value.(int32))
-	case int64:
-		result = float64(v.value.(int64))
-	case float32:
-		result = float64(v.value.(float32))
-	case float64:
-		result = v.value.(float64)
-	}
-
-	return
+func (v overwriteValue) AsFloat() float64 {
+	result, _ := v.castFloat(v.value)
+	return result
 }
 
 // AsInt typecast to int64. Returns zero value if not possible to cast.
 // NOTE: JSON by default unmarshal to numbers which are treated as float.
 // Using this method, your float will lose precision as an int64.
-func (v overwriteValue) AsInt() (result int64) {
-	switch v.value.(type) {
-	case int:
-		result = int64(v.value.(int))
-	case int8:
-		result = int64(v.value.(int8))
-	case int16:
-		result = int64(v.value.(int16))
-	case int32:
-		result = int64(v.value.(int32))
-	case int64:
-		result = v.value.(int64)
-	case float32:
-		result = int64(v.value.(float32))
-	case float64:
-		result = int64(v.value.(float64))
-	}
-
-	return
-}
-
-// Unmarshal into the given type
+func (v overwriteValue) AsInt() int64 {
+	result, _ := v.castInt(v.value)
+	return result
+}
+
+// AsUint typecast to uint64. Returns zero value if not possible to cast, including
+// negative numbers.
+// NOTE: floats will lose precision as an uint64 similar to AsInt.
+func (v overwriteValue) AsUint() uint64 {
+	result, _ := v.castUint(v.value)
+	return result
+}
+
+// AsStringSlice typecast to []string. Returns nil if not possible to cast.
+func (v overwriteValue) AsStringSlice() []string {
+	return castSlice(v.value, castString)
+}
+
+// AsIntSlice typecast to []int64. Returns nil if not possible to cast.
+func (v overwriteValue) AsIntSlice() []int64 {
+	return castSlice(v.value, v.castInt)
+}
+
+// AsFloatSlice typecast to []float64. Returns nil if not possible to cast.
+func (v overwriteValue) AsFloatSlice() []float64 {
+	return castSlice(v.value, v.castFloat)
+}
+
+// AsMap typecast to map[string]interface{}. Returns nil if not possible to cast.
+func (v overwriteValue) AsMap() map[string]interface{} {
+	if m, ok := v.value.(map[string]interface{}); ok {
+		return m
+	}
+
+	rv := reflect.ValueOf(v.value)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	result := make(map[string]interface{}, rv.Len())
+
+	iter := rv.MapRange()
+	for iter.Next() {
+		result[iter.Key().String()] = iter.Value().Interface()
+	}
+
+	return result
+}
+
+// AsDuration typecast to time.Duration. Strings are parsed using time.ParseDuration
+// (e.g. "1500ms") while numbers are treated as milliseconds. Returns zero value if
+// not possible to cast.
+func (v overwriteValue) AsDuration() time.Duration {
+	result, _ := castDuration(v.value)
+	return result
+}
+
+// AsTime typecast to time.Time. Strings are parsed using the given layouts or RFC3339
+// if none is given while numbers are treated as Unix timestamps in seconds, or in
+// milliseconds if they are too large to be seconds. Returns zero value if not possible
+// to cast.
+func (v overwriteValue) AsTime(layouts ...string) time.Time {
+	result, _ := castTime(v.value, layouts...)
+	return result
+}
+
+// AsStringE typecast to string. Returns ErrTypeMismatch if not possible to cast.
+func (v overwriteValue) AsStringE() (string, error) {
+	result, ok := castString(v.value)
+	return result, v.mismatch(ok, "string")
+}
+
+// AsBoolE typecast to bool. Returns ErrTypeMismatch if not possible to cast.
+func (v overwriteValue) AsBoolE() (bool, error) {
+	result, ok := v.castBool(v.value)
+	return result, v.mismatch(ok, "bool")
+}
+
+// AsFloatE typecast to float64. Returns ErrTypeMismatch if not possible to cast, or
+// ErrPrecisionLoss along with the nearest float64 if the wrapped value is an integer
+// too large to be represented exactly.
+func (v overwriteValue) AsFloatE() (float64, error) {
+	result, ok, err := v.convertFloat(v.value)
+	return result, v.castError(ok, err, "float64")
+}
+
+// AsIntE typecast to int64. Returns ErrTypeMismatch if not possible to cast,
+// ErrOverflow if the wrapped value is outside the range of int64, or ErrPrecisionLoss
+// along with the truncated result if it has a fractional part.
+func (v overwriteValue) AsIntE() (int64, error) {
+	result, ok, err := v.convertInt(v.value)
+	return result, v.castError(ok, err, "int64")
+}
+
+// AsUintE typecast to uint64. Returns ErrTypeMismatch if not possible to cast,
+// ErrOverflow if the wrapped value is outside the range of uint64, or ErrPrecisionLoss
+// along with the truncated result if it has a fractional part.
+func (v overwriteValue) AsUintE() (uint64, error) {
+	result, ok, err := v.convertUint(v.value)
+	return result, v.castError(ok, err, "uint64")
+}
+
+// AsDurationE typecast to time.Duration similar to AsDuration. Returns ErrTypeMismatch
+// if not possible to cast.
+func (v overwriteValue) AsDurationE() (time.Duration, error) {
+	result, ok := castDuration(v.value)
+	return result, v.mismatch(ok, "time.Duration")
+}
+
+// AsTimeE typecast to time.Time similar to AsTime. Returns ErrTypeMismatch if not
+// possible to cast.
+func (v overwriteValue) AsTimeE(layouts ...string) (time.Time, error) {
+	result, ok := castTime(v.value, layouts...)
+	return result, v.mismatch(ok, "time.Time")
+}
+
+// maxDescribedValueLen is the maximum length of values rendered in error messages.
+const maxDescribedValueLen = 128
+
+// describeValue returns a bounded rendering of the given value for error messages.
+func describeValue(value interface{}) string {
+	return helper.Stringify(value, maxDescribedValueLen)
+}
+
+// mismatch returns an error describing why the wrapped value could not be cast to
+// the target type, or nil if the cast succeeded.
+func (v overwriteValue) mismatch(ok bool, target string) error {
+	if ok {
+		return nil
+	}
+
+	return errors.Wrap(ErrTypeMismatch, fmt.Sprintf("cannot cast %s (%s) to %s", helper.NameOf(v.value), describeValue(v.value), target))
+}
+
+// castError returns an error describing why the wrapped value could not be cast to
+// the target type, either because of a type mismatch or because the given error
+// occurred during the conversion, or nil if the cast succeeded.
+func (v overwriteValue) castError(ok bool, err error, target string) error {
+	if !ok {
+		return v.mismatch(ok, target)
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	return errors.Wrap(err, fmt.Sprintf("cannot cast %s (%s) to %s", helper.NameOf(v.value), describeValue(v.value), target))
+}
+
+// castBool converts raw to bool, parsing string representations in lenient mode.
+func (v overwriteValue) castBool(raw interface{}) (bool, bool) {
+	if result, ok := castBool(raw); ok || !v.lenient {
+		return result, ok
+	}
+
+	if str, ok := raw.(string); ok {
+		result, err := strconv.ParseBool(strings.TrimSpace(str))
+		return result, err == nil
+	}
+
+	return false, false
+}
+
+// convertFloat converts raw to float64, parsing string representations in lenient mode.
+func (v overwriteValue) convertFloat(raw interface{}) (float64, bool, error) {
+	if result, ok, err := convertFloat(raw); ok || !v.lenient {
+		return result, ok, err
+	}
+
+	if str, ok := raw.(string); ok {
+		result, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+		return result, err == nil, nil
+	}
+
+	return 0, false, nil
+}
+
+// convertInt converts raw to int64, parsing string representations in lenient mode.
+func (v overwriteValue) convertInt(raw interface{}) (int64, bool, error) {
+	if result, ok, err := convertInt(raw); ok || !v.lenient {
+		return result, ok, err
+	}
+
+	str, ok := raw.(string)
+	if !ok {
+		return 0, false, nil
+	}
+
+	if result, err := strconv.ParseInt(strings.TrimSpace(str), 10, 64); err == nil {
+		return result, true, nil
+	}
+
+	if result, ok, _ := v.convertFloat(str); ok {
+		return convertInt(result)
+	}
+
+	return 0, false, nil
+}
+
+// convertUint converts raw to uint64, parsing string representations in lenient mode.
+func (v overwriteValue) convertUint(raw interface{}) (uint64, bool, error) {
+	if result, ok, err := convertUint(raw); ok || !v.lenient {
+		return result, ok, err
+	}
+
+	str, ok := raw.(string)
+	if !ok {
+		return 0, false, nil
+	}
+
+	if result, err := strconv.ParseUint(strings.TrimSpace(str), 10, 64); err == nil {
+		return result, true, nil
+	}
+
+	if result, ok, _ := v.convertFloat(str); ok {
+		return convertUint(result)
+	}
+
+	return 0, false, nil
+}
+
+// castFloat converts raw to float64 like convertFloat, ignoring any precision loss.
+func (v overwriteValue) castFloat(raw interface{}) (float64, bool) {
+	result, ok, _ := v.convertFloat(raw)
+	return result, ok
+}
+
+// castInt converts raw to int64 like convertInt, truncating its fractional part.
+// Numbers outside the range of int64 cannot be cast.
+func (v overwriteValue) castInt(raw interface{}) (int64, bool) {
+	result, ok, err := v.convertInt(raw)
+	return result, ok && !errors.Is(err, ErrOverflow)
+}
+
+// castUint converts raw to uint64 like convertUint, truncating its fractional part.
+// Numbers outside the range of uint64 cannot be cast.
+func (v overwriteValue) castUint(raw interface{}) (uint64, bool) {
+	result, ok, err := v.convertUint(raw)
+	return result, ok && !errors.Is(err, ErrOverflow)
+}
+
+func castString(v interface{}) (string, bool) {
+	result, ok := v.(string)
+	return result, ok
+}
+
+func castBool(v interface{}) (bool, bool) {
+	result, ok := v.(bool)
+	return result, ok
+}
+
+// castFloat converts the number v to float64, ignoring any precision loss.
+func castFloat(v interface{}) (float64, bool) {
+	result, ok, _ := convertFloat(v)
+	return result, ok
+}
+
+// castInt converts the number v to int64, truncating its fractional part. Numbers
+// outside the range of int64 cannot be cast.
+func castInt(v interface{}) (int64, bool) {
+	result, ok, err := convertInt(v)
+	return result, ok && !errors.Is(err, ErrOverflow)
+}
+
+// castUint converts the number v to uint64, truncating its fractional part. Numbers
+// outside the range of uint64 cannot be cast.
+func castUint(v interface{}) (uint64, bool) {
+	result, ok, err := convertUint(v)
+	return result, ok && !errors.Is(err, ErrOverflow)
+}
+
+func castDuration(v interface{}) (time.Duration, bool) {
+	switch casted := v.(type) {
+	case time.Duration:
+		return casted, true
+	case string:
+		d, err := time.ParseDuration(casted)
+		return d, err == nil
+	}
+
+	if millis, ok := castFloat(v); ok {
+		return time.Duration(millis * float64(time.Millisecond)), true
+	}
+
+	return 0, false
+}
+
+// maxUnixSeconds is the largest number that will be treated as a Unix timestamp
+// in seconds, anything larger is treated as a Unix timestamp in milliseconds.
+const maxUnixSeconds = 1e11
+
+func castTime(v interface{}, layouts ...string) (time.Time, bool) {
+	switch casted := v.(type) {
+	case time.Time:
+		return casted, true
+	case string:
+		if len(layouts) == 0 {
+			layouts = []string{time.RFC3339Nano}
+		}
+
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, casted); err == nil {
+				return t, true
+			}
+		}
+
+		return time.Time{}, false
+	}
+
+	if ts, ok := castInt(v); ok {
+		if ts > maxUnixSeconds || ts < -maxUnixSeconds {
+			return time.UnixMilli(ts), true
+		}
+
+		return time.Unix(ts, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// castSlice converts every element of the given slice using castFn. Returns nil
+// if v is not a slice or any of its elements cannot be converted.
+func castSlice[T any](v interface{}, castFn func(interface{}) (T, bool)) []T {
+	if casted, ok := v.([]T); ok {
+		return casted
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+
+	result := make([]T, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item, ok := castFn(rv.Index(i).Interface())
+		if !ok {
+			return nil
+		}
+
+		result[i] = item
+	}
+
+	return result
+}
+
+// Unmarshal converts the raw value wrapped inside v to T. The raw value is decoded
+// directly via mapstructure, matching struct fields by their JSON tags and parsing
+// strings into time.Duration and time.Time (RFC3339) along the way. If that fails,
+// it falls back to a JSON round trip.
 func Unmarshal[T any](v Value) (*T, error) {
+	result := new(T)
+	if err := decode(v.AsIs(), result, decodeOptions{}); err == nil {
+		return result, nil
+	}
+
 	str, err := json.Marshal(v.AsIs())
 	if err != nil {
 		return nil, err
 	}
 
-	result := new(T)
+	result = new(T)
 	err = json.Unmarshal(str, result)
 	if err != nil {
 		return nil, err
@@ -113,3 +547,42 @@ func Unmarshal[T any](v Value) (*T, error) {
 
 	return result, nil
 }
+
+// convertValue converts the raw value wrapped inside v to T via a direct type
+// assertion, a numeric coercion or Unmarshal, whichever succeeds first.
+func convertValue[T any](v Value) (T, bool) {
+	raw := v.AsIs()
+	if casted, ok := helper.As[T](raw); ok {
+		return casted, true
+	}
+
+	var zero T
+
+	targetType := reflect.TypeOf((*T)(nil)).Elem()
+	if raw != nil && isNumericKind(reflect.TypeOf(raw).Kind()) && isNumericKind(targetType.Kind()) {
+		converted, ok := convertNumber(raw, targetType)
+		if !ok {
+			return zero, false
+		}
+
+		return converted.Interface().(T), true
+	}
+
+	result, err := Unmarshal[T](v)
+	if err != nil {
+		return zero, false
+	}
+
+	return *result, true
+}
+
+func isNumericKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
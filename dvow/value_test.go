@@ -1,7 +1,12 @@
 package dvow
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -385,6 +390,26 @@ func TestOverwriteValue_AsInt(t *testing.T) {
 			value: float64(123.45),
 			want:  123,
 		},
+		{
+			desc:  "uint64",
+			value: uint64(123),
+			want:  123,
+		},
+		{
+			desc:  "uint64 overflow",
+			value: uint64(math.MaxUint64),
+			want:  0,
+		},
+		{
+			desc:  "float64 overflow",
+			value: 1e20,
+			want:  0,
+		},
+		{
+			desc:  "json.Number overflow",
+			value: json.Number("18446744073709551615"),
+			want:  0,
+		},
 		{
 			desc:  "struct",
 			value: struct{}{},
@@ -416,20 +441,423 @@ func TestOverwriteValue_AsInt(t *testing.T) {
 	}
 }
 
+func TestOverwriteValue_AsUint(t *testing.T) {
+	scenarios := []struct {
+		desc  string
+		value interface{}
+		want  uint64
+	}{
+		{
+			desc:  "string",
+			value: "text",
+			want:  0,
+		},
+		{
+			desc:  "int",
+			value: int(123),
+			want:  123,
+		},
+		{
+			desc:  "negative int",
+			value: int(-1),
+			want:  0,
+		},
+		{
+			desc:  "uint8",
+			value: uint8(123),
+			want:  123,
+		},
+		{
+			desc:  "uint64",
+			value: uint64(math.MaxUint64),
+			want:  math.MaxUint64,
+		},
+		{
+			desc:  "float64",
+			value: float64(123.45),
+			want:  123,
+		},
+		{
+			desc:  "float64 overflow",
+			value: 1e20,
+			want:  0,
+		},
+		{
+			desc:  "json.Number",
+			value: json.Number("18446744073709551615"),
+			want:  math.MaxUint64,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sv := overwriteValue{
+				value: sc.value,
+			}
+
+			actual := sv.AsUint()
+
+			assert.Equal(t, sc.want, actual)
+		})
+	}
+}
+
+func TestOverwriteValue_AsStringSlice(t *testing.T) {
+	scenarios := []struct {
+		desc  string
+		value interface{}
+		want  []string
+	}{
+		{
+			desc:  "string slice",
+			value: []string{"a", "b"},
+			want:  []string{"a", "b"},
+		},
+		{
+			desc:  "interface slice from JSON decoding",
+			value: []interface{}{"a", "b"},
+			want:  []string{"a", "b"},
+		},
+		{
+			desc:  "interface slice containing non-string",
+			value: []interface{}{"a", 1},
+			want:  nil,
+		},
+		{
+			desc:  "not a slice",
+			value: "a",
+			want:  nil,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sv := overwriteValue{
+				value: sc.value,
+			}
+
+			actual := sv.AsStringSlice()
+
+			assert.Equal(t, sc.want, actual)
+		})
+	}
+}
+
+func TestOverwriteValue_AsIntSlice(t *testing.T) {
+	scenarios := []struct {
+		desc  string
+		value interface{}
+		want  []int64
+	}{
+		{
+			desc:  "int64 slice",
+			value: []int64{1, 2},
+			want:  []int64{1, 2},
+		},
+		{
+			desc:  "int slice",
+			value: []int{1, 2},
+			want:  []int64{1, 2},
+		},
+		{
+			desc:  "interface slice from JSON decoding",
+			value: []interface{}{float64(1), float64(2)},
+			want:  []int64{1, 2},
+		},
+		{
+			desc:  "interface slice containing non-number",
+			value: []interface{}{float64(1), "2"},
+			want:  nil,
+		},
+		{
+			desc:  "not a slice",
+			value: 1,
+			want:  nil,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sv := overwriteValue{
+				value: sc.value,
+			}
+
+			actual := sv.AsIntSlice()
+
+			assert.Equal(t, sc.want, actual)
+		})
+	}
+}
+
+func TestOverwriteValue_AsFloatSlice(t *testing.T) {
+	scenarios := []struct {
+		desc  string
+		value interface{}
+		want  []float64
+	}{
+		{
+			desc:  "float64 slice",
+			value: []float64{1.5, 2.5},
+			want:  []float64{1.5, 2.5},
+		},
+		{
+			desc:  "int array",
+			value: [2]int{1, 2},
+			want:  []float64{1, 2},
+		},
+		{
+			desc:  "interface slice from JSON decoding",
+			value: []interface{}{1.5, 2.5},
+			want:  []float64{1.5, 2.5},
+		},
+		{
+			desc:  "interface slice containing non-number",
+			value: []interface{}{1.5, true},
+			want:  nil,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sv := overwriteValue{
+				value: sc.value,
+			}
+
+			actual := sv.AsFloatSlice()
+
+			assert.Equal(t, sc.want, actual)
+		})
+	}
+}
+
+func TestOverwriteValue_AsMap(t *testing.T) {
+	type key string
+
+	scenarios := []struct {
+		desc  string
+		value interface{}
+		want  map[string]interface{}
+	}{
+		{
+			desc:  "map from JSON decoding",
+			value: map[string]interface{}{"a": 1.5},
+			want:  map[string]interface{}{"a": 1.5},
+		},
+		{
+			desc:  "map with string-like keys",
+			value: map[key]int{"a": 1},
+			want:  map[string]interface{}{"a": 1},
+		},
+		{
+			desc:  "map with non-string keys",
+			value: map[int]int{1: 1},
+			want:  nil,
+		},
+		{
+			desc:  "not a map",
+			value: []string{"a"},
+			want:  nil,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sv := overwriteValue{
+				value: sc.value,
+			}
+
+			actual := sv.AsMap()
+
+			assert.Equal(t, sc.want, actual)
+		})
+	}
+}
+
+func TestOverwriteValue_AsDuration(t *testing.T) {
+	scenarios := []struct {
+		desc  string
+		value interface{}
+		want  time.Duration
+	}{
+		{
+			desc:  "duration",
+			value: time.Second,
+			want:  time.Second,
+		},
+		{
+			desc:  "string",
+			value: "1500ms",
+			want:  1500 * time.Millisecond,
+		},
+		{
+			desc:  "invalid string",
+			value: "1500",
+			want:  0,
+		},
+		{
+			desc:  "number as millis",
+			value: float64(1500),
+			want:  1500 * time.Millisecond,
+		},
+		{
+			desc:  "bool",
+			value: true,
+			want:  0,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sv := overwriteValue{
+				value: sc.value,
+			}
+
+			actual := sv.AsDuration()
+
+			assert.Equal(t, sc.want, actual)
+		})
+	}
+}
+
+func TestOverwriteValue_AsTime(t *testing.T) {
+	expected := time.Date(2023, 8, 8, 10, 30, 0, 0, time.UTC)
+
+	scenarios := []struct {
+		desc    string
+		value   interface{}
+		layouts []string
+		want    time.Time
+	}{
+		{
+			desc:  "time",
+			value: expected,
+			want:  expected,
+		},
+		{
+			desc:  "RFC3339 string",
+			value: "2023-08-08T10:30:00Z",
+			want:  expected,
+		},
+		{
+			desc:    "string with custom layouts",
+			value:   "2023-08-08 10:30",
+			layouts: []string{time.RFC3339, "2006-01-02 15:04"},
+			want:    expected,
+		},
+		{
+			desc:  "invalid string",
+			value: "yesterday",
+			want:  time.Time{},
+		},
+		{
+			desc:  "Unix seconds",
+			value: float64(expected.Unix()),
+			want:  expected,
+		},
+		{
+			desc:  "Unix millis",
+			value: expected.UnixMilli(),
+			want:  expected,
+		},
+		{
+			desc:  "bool",
+			value: true,
+			want:  time.Time{},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sv := overwriteValue{
+				value: sc.value,
+			}
+
+			actual := sv.AsTime(sc.layouts...)
+
+			assert.True(t, sc.want.Equal(actual), "got %v, want %v", actual, sc.want)
+		})
+	}
+}
+
+func TestOverwriteValue_Lenient(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "strings are NOT parsed by default",
+			test: func(t *testing.T) {
+				sv := overwriteValue{
+					value: "123",
+				}
+
+				assert.Equal(t, int64(0), sv.AsInt())
+				assert.Equal(t, float64(0), sv.AsFloat())
+				assert.Nil(t, overwriteValue{value: []interface{}{"1"}}.AsIntSlice())
+				assert.False(t, overwriteValue{value: "true"}.AsBool())
+			},
+		},
+		{
+			desc: "strings are parsed in lenient mode",
+			test: func(t *testing.T) {
+				sv := overwriteValue{
+					value:   " 123 ",
+					lenient: true,
+				}
+
+				assert.Equal(t, int64(123), sv.AsInt())
+				assert.Equal(t, float64(123), sv.AsFloat())
+				assert.Equal(t, int64(12), overwriteValue{value: "12.5", lenient: true}.AsInt())
+				assert.Equal(t, 12.5, overwriteValue{value: "12.5", lenient: true}.AsFloat())
+				assert.True(t, overwriteValue{value: "true", lenient: true}.AsBool())
+				assert.Equal(t, []int64{1, 2}, overwriteValue{value: []interface{}{"1", 2}, lenient: true}.AsIntSlice())
+			},
+		},
+		{
+			desc: "invalid strings in lenient mode",
+			test: func(t *testing.T) {
+				sv := overwriteValue{
+					value:   "abc",
+					lenient: true,
+				}
+
+				assert.Equal(t, int64(0), sv.AsInt())
+				assert.Equal(t, float64(0), sv.AsFloat())
+				assert.False(t, sv.AsBool())
+				assert.Equal(t, int64(0), overwriteValue{value: struct{}{}, lenient: true}.AsInt())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
 func TestUnmarshal(t *testing.T) {
 	scenarios := []struct {
 		desc string
 		test func(t *testing.T)
 	}{
 		{
-			desc: "input containing field that cannot be marshalled",
+			desc: "input that can neither be decoded nor marshalled",
 			test: func(t *testing.T) {
 				type dummy struct {
-					Fn func()
+					Text string
 				}
 
 				sv := overwriteValue{
-					value: dummy{},
+					value: func() {},
 				}
 
 				result, err := Unmarshal[dummy](sv)
@@ -438,6 +866,57 @@ func TestUnmarshal(t *testing.T) {
 				assert.Equal(t, "json: unsupported type: func()", err.Error())
 			},
 		},
+		{
+			desc: "input containing field that cannot be marshalled is decoded directly",
+			test: func(t *testing.T) {
+				type dummy struct {
+					Fn func()
+				}
+
+				sv := overwriteValue{
+					value: dummy{},
+				}
+
+				result, err := Unmarshal[dummy](sv)
+
+				assert.Equal(t, &dummy{}, result)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			desc: "types are preserved without JSON round trip",
+			test: func(t *testing.T) {
+				type dummy struct {
+					Count     int64         `json:"count"`
+					Timeout   time.Duration `json:"timeout"`
+					CreatedAt time.Time     `json:"created_at"`
+					UpdatedAt time.Time     `json:"updated_at"`
+				}
+
+				now := time.Now()
+
+				sv := overwriteValue{
+					value: map[string]interface{}{
+						"count":      json.Number("9007199254740993"),
+						"timeout":    "1500ms",
+						"created_at": now,
+						"updated_at": "2022-01-02T03:04:05Z",
+					},
+				}
+
+				result, err := Unmarshal[dummy](sv)
+
+				expected := &dummy{
+					Count:     9007199254740993,
+					Timeout:   1500 * time.Millisecond,
+					CreatedAt: now,
+					UpdatedAt: time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+				}
+
+				assert.Equal(t, expected, result)
+				assert.Nil(t, err)
+			},
+		},
 		{
 			desc: "valid input",
 			test: func(t *testing.T) {
@@ -466,3 +945,278 @@ func TestUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertValue(t *testing.T) {
+	type dummy struct {
+		Text string `json:"text"`
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "direct assertion",
+			test: func(t *testing.T) {
+				actual, ok := convertValue[string](overwriteValue{value: "text"})
+				assert.True(t, ok)
+				assert.Equal(t, "text", actual)
+			},
+		},
+		{
+			desc: "numeric coercion",
+			test: func(t *testing.T) {
+				actual, ok := convertValue[int](overwriteValue{value: float64(12)})
+				assert.True(t, ok)
+				assert.Equal(t, 12, actual)
+
+				actualFloat, ok := convertValue[float32](overwriteValue{value: int8(3)})
+				assert.True(t, ok)
+				assert.Equal(t, float32(3), actualFloat)
+			},
+		},
+		{
+			desc: "unmarshal fallback",
+			test: func(t *testing.T) {
+				actual, ok := convertValue[dummy](overwriteValue{value: map[string]interface{}{"text": "test"}})
+				assert.True(t, ok)
+				assert.Equal(t, dummy{Text: "test"}, actual)
+			},
+		},
+		{
+			desc: "not convertible",
+			test: func(t *testing.T) {
+				actual, ok := convertValue[int](overwriteValue{value: "text"})
+				assert.False(t, ok)
+				assert.Equal(t, 0, actual)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestOverwriteValue_Strict(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "castable values",
+			test: func(t *testing.T) {
+				str, err := overwriteValue{value: "text"}.AsStringE()
+				assert.Nil(t, err)
+				assert.Equal(t, "text", str)
+
+				b, err := overwriteValue{value: true}.AsBoolE()
+				assert.Nil(t, err)
+				assert.True(t, b)
+
+				f, err := overwriteValue{value: 1}.AsFloatE()
+				assert.Nil(t, err)
+				assert.Equal(t, float64(1), f)
+
+				i, err := overwriteValue{value: "12", lenient: true}.AsIntE()
+				assert.Nil(t, err)
+				assert.Equal(t, int64(12), i)
+
+				d, err := overwriteValue{value: "1s"}.AsDurationE()
+				assert.Nil(t, err)
+				assert.Equal(t, time.Second, d)
+
+				tm, err := overwriteValue{value: int64(0)}.AsTimeE()
+				assert.Nil(t, err)
+				assert.Equal(t, time.Unix(0, 0), tm)
+			},
+		},
+		{
+			desc: "non-castable values",
+			test: func(t *testing.T) {
+				_, err := overwriteValue{value: 1}.AsStringE()
+				assert.True(t, errors.Is(err, ErrTypeMismatch))
+
+				_, err = overwriteValue{value: "true"}.AsBoolE()
+				assert.True(t, errors.Is(err, ErrTypeMismatch))
+
+				_, err = overwriteValue{value: "1.5"}.AsFloatE()
+				assert.True(t, errors.Is(err, ErrTypeMismatch))
+
+				i, err := overwriteValue{value: "12"}.AsIntE()
+				assert.Equal(t, int64(0), i)
+				assert.True(t, errors.Is(err, ErrTypeMismatch))
+				assert.Contains(t, err.Error(), `cannot cast string ("12") to int64`)
+
+				_, err = overwriteValue{value: true}.AsDurationE()
+				assert.True(t, errors.Is(err, ErrTypeMismatch))
+
+				_, err = overwriteValue{value: "yesterday"}.AsTimeE()
+				assert.True(t, errors.Is(err, ErrTypeMismatch))
+			},
+		},
+		{
+			desc: "overflow and precision loss",
+			test: func(t *testing.T) {
+				i, err := overwriteValue{value: uint64(math.MaxUint64)}.AsIntE()
+				assert.Equal(t, int64(0), i)
+				assert.True(t, errors.Is(err, ErrOverflow))
+				assert.Contains(t, err.Error(), "cannot cast uint64 (18446744073709551615) to int64")
+
+				_, err = overwriteValue{value: math.Inf(1)}.AsIntE()
+				assert.True(t, errors.Is(err, ErrOverflow))
+
+				i, err = overwriteValue{value: 12.5}.AsIntE()
+				assert.Equal(t, int64(12), i)
+				assert.True(t, errors.Is(err, ErrPrecisionLoss))
+
+				i, err = overwriteValue{value: "12.5", lenient: true}.AsIntE()
+				assert.Equal(t, int64(12), i)
+				assert.True(t, errors.Is(err, ErrPrecisionLoss))
+
+				i, err = overwriteValue{value: 12.0}.AsIntE()
+				assert.Equal(t, int64(12), i)
+				assert.Nil(t, err)
+
+				u, err := overwriteValue{value: -1}.AsUintE()
+				assert.Equal(t, uint64(0), u)
+				assert.True(t, errors.Is(err, ErrOverflow))
+
+				u, err = overwriteValue{value: "18446744073709551615", lenient: true}.AsUintE()
+				assert.Equal(t, uint64(math.MaxUint64), u)
+				assert.Nil(t, err)
+
+				_, err = overwriteValue{value: "1"}.AsUintE()
+				assert.True(t, errors.Is(err, ErrTypeMismatch))
+
+				f, err := overwriteValue{value: int64(1<<53 + 1)}.AsFloatE()
+				assert.Equal(t, float64(1<<53), f)
+				assert.True(t, errors.Is(err, ErrPrecisionLoss))
+
+				_, err = overwriteValue{value: uint64(math.MaxUint64)}.AsFloatE()
+				assert.True(t, errors.Is(err, ErrPrecisionLoss))
+
+				_, err = overwriteValue{value: int64(math.MaxInt64)}.AsFloatE()
+				assert.True(t, errors.Is(err, ErrPrecisionLoss))
+
+				_, err = overwriteValue{value: json.Number("9007199254740993")}.AsFloatE()
+				assert.True(t, errors.Is(err, ErrPrecisionLoss))
+
+				f, err = overwriteValue{value: int64(1 << 53)}.AsFloatE()
+				assert.Equal(t, float64(1<<53), f)
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestOverwriteValue_Presence(t *testing.T) {
+	var nilMap map[string]interface{}
+
+	scenarios := []struct {
+		desc              string
+		value             overwriteValue
+		expectedIsPresent bool
+		expectedIsNil     bool
+		expectedIsZero    bool
+	}{
+		{
+			desc:              "overwritten to null",
+			value:             overwriteValue{value: nil},
+			expectedIsPresent: true,
+			expectedIsNil:     true,
+			expectedIsZero:    true,
+		},
+		{
+			desc:              "overwritten to typed nil",
+			value:             overwriteValue{value: nilMap},
+			expectedIsPresent: true,
+			expectedIsNil:     true,
+			expectedIsZero:    true,
+		},
+		{
+			desc:              "overwritten to zero value",
+			value:             overwriteValue{value: 0},
+			expectedIsPresent: true,
+			expectedIsNil:     false,
+			expectedIsZero:    true,
+		},
+		{
+			desc:              "overwritten to non-zero value",
+			value:             overwriteValue{value: map[string]interface{}{}},
+			expectedIsPresent: true,
+			expectedIsNil:     false,
+			expectedIsZero:    false,
+		},
+		{
+			desc:              "fallback",
+			value:             overwriteValue{value: nil, isFallback: true},
+			expectedIsPresent: false,
+			expectedIsNil:     true,
+			expectedIsZero:    true,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			assert.Equal(t, sc.expectedIsPresent, sc.value.IsPresent())
+			assert.Equal(t, sc.expectedIsNil, sc.value.IsNil())
+			assert.Equal(t, sc.expectedIsZero, sc.value.IsZero())
+		})
+	}
+}
+
+func TestOverwriteValue_Kind(t *testing.T) {
+	scenarios := []struct {
+		desc             string
+		value            interface{}
+		expectedKind     reflect.Kind
+		expectedTypeName string
+	}{
+		{
+			desc:             "nil",
+			value:            nil,
+			expectedKind:     reflect.Invalid,
+			expectedTypeName: "nil",
+		},
+		{
+			desc:             "int64",
+			value:            int64(1),
+			expectedKind:     reflect.Int64,
+			expectedTypeName: "int64",
+		},
+		{
+			desc:             "json.Number",
+			value:            json.Number("1"),
+			expectedKind:     reflect.String,
+			expectedTypeName: "json.Number",
+		},
+		{
+			desc:             "map",
+			value:            map[string]interface{}{},
+			expectedKind:     reflect.Map,
+			expectedTypeName: "map[string]interface {}",
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			v := overwriteValue{value: sc.value}
+
+			assert.Equal(t, sc.expectedKind, v.Kind())
+			assert.Equal(t, sc.expectedTypeName, v.TypeName())
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package dvow
+
+import (
+	"context"
+)
+
+type watchableStorageKeyType struct{}
+
+var watchableStorageKey = watchableStorageKeyType{}
+
+// watchableStorage is implemented by Storage whose variables can change at runtime.
+type watchableStorage interface {
+	Watch(name string, fn func(Value)) func()
+}
+
+// OnChange registers fn to be called with the new Value of the variable under this name
+// whenever it changes in the nearest Storage of ctx that can change at runtime, i.e. one
+// attached via WithMutableOverwrittenVariables or WithDynamicStorage. The Value is nil if
+// the variable is no longer overwritten. This allows components to react to overwrites
+// flipped at runtime (e.g. resize a worker pool) without polling.
+//
+// It returns a function cancelling this subscription, or ErrImmutableStorage if ctx does
+// not have such a Storage.
+func OnChange(ctx context.Context, name string, fn func(Value)) (func(), error) {
+	s, ok := ctx.Value(watchableStorageKey).(watchableStorage)
+	if !ok {
+		return nil, ErrImmutableStorage
+	}
+
+	return s.Watch(name, fn), nil
+}
+
+// watcherSet holds the functions watching the variables of a Storage. It is not safe
+// for concurrent use, hence it must be guarded by the lock of its Storage.
+type watcherSet struct {
+	fns    map[string]map[int]func(Value)
+	lastID int
+}
+
+// add registers fn to watch the variable under this name and returns its ID.
+func (w *watcherSet) add(name string, fn func(Value)) int {
+	if w.fns == nil {
+		w.fns = make(map[string]map[int]func(Value))
+	}
+
+	if w.fns[name] == nil {
+		w.fns[name] = make(map[int]func(Value))
+	}
+
+	w.lastID++
+	w.fns[name][w.lastID] = fn
+
+	return w.lastID
+}
+
+// remove unregisters the function with the given ID from the variable under this name.
+func (w *watcherSet) remove(name string, id int) {
+	delete(w.fns[name], id)
+
+	if len(w.fns[name]) == 0 {
+		delete(w.fns, name)
+	}
+}
+
+// names returns the names of all watched variables.
+func (w *watcherSet) names() []string {
+	names := make([]string, 0, len(w.fns))
+	for name := range w.fns {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// notifications returns the functions notifying the watchers of the variable under
+// this name about the given Value. They should be invoked outside the lock of the
+// Storage so that watchers can safely read from it.
+func (w *watcherSet) notifications(name string, value Value) []func() {
+	fns := w.fns[name]
+	if len(fns) == 0 {
+		return nil
+	}
+
+	notifications := make([]func(), 0, len(fns))
+	for _, fn := range fns {
+		fn := fn
+		notifications = append(
+			notifications, func() {
+				fn(value)
+			},
+		)
+	}
+
+	return notifications
+}
+
+// notify invokes all given notifications in order.
+func notify(notifications []func()) {
+	for _, n := range notifications {
+		n()
+	}
+}
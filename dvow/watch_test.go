@@ -0,0 +1,88 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOnChange(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "immutable storage",
+			test: func(t *testing.T) {
+				ctx := WithOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+				cancel, err := OnChange(ctx, "a", func(Value) {})
+				assert.Nil(t, cancel)
+				assert.Equal(t, ErrImmutableStorage, err)
+			},
+		},
+		{
+			desc: "mutable storage",
+			test: func(t *testing.T) {
+				ctx := WithMutableOverwrittenVariables(context.Background(), map[string]interface{}{"a": 1})
+
+				var values []Value
+				cancel, err := OnChange(
+					ctx, "a", func(v Value) {
+						// Watchers can safely read from the Storage
+						assert.Equal(t, v, GetOverwrittenValue(ctx, "a"))
+						values = append(values, v)
+					},
+				)
+				assert.Nil(t, err)
+
+				var otherCalls int
+				_, _ = OnChange(ctx, "b", func(Value) { otherCalls++ })
+
+				assert.Nil(t, SetOverwrittenValue(ctx, "a", 1))
+				assert.Nil(t, SetOverwrittenValue(ctx, "a", 2))
+				assert.Nil(t, UnsetOverwrittenValue(ctx, "a"))
+				assert.Nil(t, UnsetOverwrittenValue(ctx, "a"))
+
+				assert.Equal(t, 2, len(values))
+				assert.Equal(t, 2, values[0].AsIs())
+				assert.Nil(t, values[1])
+				assert.Equal(t, 0, otherCalls)
+
+				cancel()
+
+				assert.Nil(t, SetOverwrittenValue(ctx, "a", 3))
+				assert.Equal(t, 2, len(values))
+			},
+		},
+		{
+			desc: "dynamic storage",
+			test: func(t *testing.T) {
+				providerMock := &MockProvider{}
+				providerMock.On("Load", mock.Anything).Return(map[string]interface{}{"a": 1}, nil).Once()
+				providerMock.On("Load", mock.Anything).Return(map[string]interface{}{"a": 2}, nil).Once()
+
+				s, err := NewDynamicStorage(context.Background(), providerMock)
+				assert.Nil(t, err)
+
+				ctx := WithDynamicStorage(context.Background(), s)
+
+				var value Value
+				_, err = OnChange(ctx, "a", func(v Value) { value = v })
+				assert.Nil(t, err)
+
+				assert.Nil(t, s.Refresh(context.Background()))
+				assert.Equal(t, 2, value.AsIs())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package dvow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// wireVersion is the current version of the wire format produced by MarshalStorage.
+const wireVersion = 1
+
+// Type hints of values in the wire format.
+const (
+	hintNull     = "null"
+	hintString   = "string"
+	hintBool     = "bool"
+	hintInt      = "int"
+	hintInt8     = "int8"
+	hintInt16    = "int16"
+	hintInt32    = "int32"
+	hintInt64    = "int64"
+	hintUint     = "uint"
+	hintUint8    = "uint8"
+	hintUint16   = "uint16"
+	hintUint32   = "uint32"
+	hintUint64   = "uint64"
+	hintFloat32  = "float32"
+	hintFloat64  = "float64"
+	hintNumber   = "number"
+	hintDuration = "duration"
+	hintTime     = "time"
+	hintJSON     = "json"
+)
+
+type wireStorage struct {
+	Version   int                     `json:"v"`
+	Variables map[string]wireVariable `json:"vars,omitempty"`
+}
+
+type wireVariable struct {
+	Hint  string          `json:"t"`
+	Value json.RawMessage `json:"v,omitempty"`
+}
+
+// MarshalStorage serializes all variables effectively overwritten in the given Storage
+// into a compact, versioned wire format. Each value carries a type hint so that scalar
+// values keep their types (e.g. ints do not become float64) after UnmarshalStorage.
+// Composite values such as maps, slices and structs are encoded as plain JSON.
+//
+// This format is meant to be used by propagation layers (HTTP, gRPC, async messaging).
+func MarshalStorage(storage Storage) ([]byte, error) {
+	ws := wireStorage{
+		Version: wireVersion,
+	}
+
+	if storage != nil {
+		keys := UpgradeStorage(storage).Keys()
+
+		ws.Variables = make(map[string]wireVariable, len(keys))
+		for _, name := range keys {
+			value := storage.Get(name)
+			if value == nil {
+				continue
+			}
+
+			wv, err := encodeWireVariable(value.AsIs())
+			if err != nil {
+				return nil, errors.Wrap(err, fmt.Sprintf("failed to encode variable %s", name))
+			}
+
+			ws.Variables[name] = wv
+		}
+	}
+
+	return json.Marshal(ws)
+}
+
+// UnmarshalStorage deserializes the data produced by MarshalStorage into a Storage.
+func UnmarshalStorage(data []byte) (Storage, error) {
+	overwrittenVariables, err := unmarshalVariables(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return dynamicOverwritingStorage{
+		variables: overwrittenVariables,
+	}, nil
+}
+
+// WithOverwrittenVariablesFromWire deserializes the data produced by MarshalStorage and
+// returns a new context.Context that holds a reference to these overwritten variables.
+func WithOverwrittenVariablesFromWire(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+	overwrittenVariables, err := unmarshalVariables(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return Ops.WithOverwrittenVariables(ctx, overwrittenVariables, opts...), nil
+}
+
+func unmarshalVariables(data []byte) (map[string]interface{}, error) {
+	var ws wireStorage
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, err
+	}
+
+	if ws.Version != wireVersion {
+		return nil, errors.Wrap(ErrUnsupportedWireVersion, fmt.Sprint(ws.Version))
+	}
+
+	overwrittenVariables := make(map[string]interface{}, len(ws.Variables))
+	for name, wv := range ws.Variables {
+		value, err := decodeWireVariable(wv)
+		if err != nil {
+			return nil, errors.Wrap(err, fmt.Sprintf("failed to decode variable %s", name))
+		}
+
+		overwrittenVariables[name] = value
+	}
+
+	return overwrittenVariables, nil
+}
+
+func encodeWireVariable(value interface{}) (wireVariable, error) {
+	hint := func() string {
+		switch value.(type) {
+		case nil:
+			return hintNull
+		case string:
+			return hintString
+		case bool:
+			return hintBool
+		case int:
+			return hintInt
+		case int8:
+			return hintInt8
+		case int16:
+			return hintInt16
+		case int32:
+			return hintInt32
+		case int64:
+			return hintInt64
+		case uint:
+			return hintUint
+		case uint8:
+			return hintUint8
+		case uint16:
+			return hintUint16
+		case uint32:
+			return hintUint32
+		case uint64:
+			return hintUint64
+		case float32:
+			return hintFloat32
+		case float64:
+			return hintFloat64
+		case json.Number:
+			return hintNumber
+		case time.Duration:
+			return hintDuration
+		case time.Time:
+			return hintTime
+		default:
+			return hintJSON
+		}
+	}()
+
+	if hint == hintNull {
+		return wireVariable{
+			Hint: hint,
+		}, nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return wireVariable{}, err
+	}
+
+	return wireVariable{
+		Hint:  hint,
+		Value: raw,
+	}, nil
+}
+
+func decodeWireVariable(wv wireVariable) (interface{}, error) {
+	switch wv.Hint {
+	case hintNull:
+		return nil, nil
+	case hintString:
+		return decodeWireValue[string](wv.Value)
+	case hintBool:
+		return decodeWireValue[bool](wv.Value)
+	case hintInt:
+		return decodeWireValue[int](wv.Value)
+	case hintInt8:
+		return decodeWireValue[int8](wv.Value)
+	case hintInt16:
+		return decodeWireValue[int16](wv.Value)
+	case hintInt32:
+		return decodeWireValue[int32](wv.Value)
+	case hintInt64:
+		return decodeWireValue[int64](wv.Value)
+	case hintUint:
+		return decodeWireValue[uint](wv.Value)
+	case hintUint8:
+		return decodeWireValue[uint8](wv.Value)
+	case hintUint16:
+		return decodeWireValue[uint16](wv.Value)
+	case hintUint32:
+		return decodeWireValue[uint32](wv.Value)
+	case hintUint64:
+		return decodeWireValue[uint64](wv.Value)
+	case hintFloat32:
+		return decodeWireValue[float32](wv.Value)
+	case hintFloat64:
+		return decodeWireValue[float64](wv.Value)
+	case hintNumber:
+		return decodeWireValue[json.Number](wv.Value)
+	case hintDuration:
+		return decodeWireValue[time.Duration](wv.Value)
+	case hintTime:
+		return decodeWireValue[time.Time](wv.Value)
+	case hintJSON:
+		return decodeWireValue[interface{}](wv.Value)
+	default:
+		return nil, errors.Wrap(ErrUnknownWireTypeHint, wv.Hint)
+	}
+}
+
+func decodeWireValue[T any](raw json.RawMessage) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var result T
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,160 @@
+package dvow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalStorage(t *testing.T) {
+	now := time.Date(2023, 8, 8, 10, 30, 0, 0, time.UTC)
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "nil storage",
+			test: func(t *testing.T) {
+				data, err := MarshalStorage(nil)
+				assert.Nil(t, err)
+				assert.JSONEq(t, `{"v":1}`, string(data))
+			},
+		},
+		{
+			desc: "round trip keeps scalar types",
+			test: func(t *testing.T) {
+				variables := map[string]interface{}{
+					"null":     nil,
+					"string":   "text",
+					"bool":     true,
+					"int":      1,
+					"int8":     int8(2),
+					"int16":    int16(3),
+					"int32":    int32(4),
+					"int64":    int64(9007199254740993),
+					"uint":     uint(5),
+					"uint8":    uint8(6),
+					"uint16":   uint16(7),
+					"uint32":   uint32(8),
+					"uint64":   uint64(18446744073709551615),
+					"float32":  float32(1.5),
+					"float64":  2.5,
+					"number":   json.Number("3.5"),
+					"duration": 1500 * time.Millisecond,
+					"time":     now,
+					"json": map[string]interface{}{
+						"list": []interface{}{1, "a"},
+					},
+				}
+
+				storage := dynamicOverwritingStorage{
+					parent: dynamicOverwritingStorage{
+						variables: map[string]interface{}{
+							"masked": 1,
+						},
+					},
+					variables: map[string]interface{}{
+						"masked": tombstone{},
+					},
+				}
+
+				for name, value := range variables {
+					storage.variables[name] = value
+				}
+
+				data, err := MarshalStorage(storage)
+				assert.Nil(t, err)
+
+				actual, err := UnmarshalStorage(data)
+				assert.Nil(t, err)
+
+				expected := make(map[string]interface{}, len(variables))
+				for name, value := range variables {
+					expected[name] = value
+				}
+
+				expected["json"] = map[string]interface{}{
+					"list": []interface{}{json.Number("1"), "a"},
+				}
+
+				assert.Equal(t, expected, actual.(dynamicOverwritingStorage).variables)
+			},
+		},
+		{
+			desc: "value cannot be marshalled",
+			test: func(t *testing.T) {
+				storage := dynamicOverwritingStorage{
+					variables: map[string]interface{}{
+						"fn": func() {},
+					},
+				}
+
+				data, err := MarshalStorage(storage)
+				assert.Nil(t, data)
+				assert.NotNil(t, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestUnmarshalStorage(t *testing.T) {
+	scenarios := []struct {
+		desc    string
+		data    string
+		wantErr error
+	}{
+		{
+			desc: "invalid JSON",
+			data: `{`,
+		},
+		{
+			desc:    "unsupported version",
+			data:    `{"v":2}`,
+			wantErr: ErrUnsupportedWireVersion,
+		},
+		{
+			desc:    "unknown type hint",
+			data:    `{"v":1,"vars":{"a":{"t":"complex128","v":1}}}`,
+			wantErr: ErrUnknownWireTypeHint,
+		},
+		{
+			desc: "value does not match type hint",
+			data: `{"v":1,"vars":{"a":{"t":"int8","v":1000}}}`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			storage, err := UnmarshalStorage([]byte(sc.data))
+			assert.Nil(t, storage)
+			assert.NotNil(t, err)
+
+			if sc.wantErr != nil {
+				assert.True(t, errors.Is(err, sc.wantErr))
+			}
+		})
+	}
+}
+
+func TestWithOverwrittenVariablesFromWire(t *testing.T) {
+	ctx, err := WithOverwrittenVariablesFromWire(context.Background(), []byte(`{"v":1,"vars":{"a":{"t":"int","v":1}}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, GetOverwrittenValue(ctx, "a").AsIs())
+
+	ctx, err = WithOverwrittenVariablesFromWire(context.Background(), []byte(`{"v":0}`))
+	assert.Nil(t, ctx)
+	assert.True(t, errors.Is(err, ErrUnsupportedWireVersion))
+}
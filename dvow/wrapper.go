@@ -2,22 +2,50 @@ package dvow
 
 import (
     "context"
+    "time"
 )
 
 //go:generate mockery --name IOverwritingOps --case underscore --inpkg
 // IOverwritingOps ...
 type IOverwritingOps interface {
     // ExtractOverwritingStorage returns the Storage currently associated with ctx, or
-    // nil if no such Storage could be found.
+    // the default Storage set via SetDefaultStorage if no such Storage could be found.
     ExtractOverwritingStorage(ctx context.Context) Storage
     // GetOverwrittenValue returns the Value of the variable under this name if it was overwritten
     GetOverwrittenValue(ctx context.Context, name string) Value
+    // WithOverwrittenVariables returns a new context.Context that holds a reference to
+    // the given overwritten variables.
+    WithOverwrittenVariables(ctx context.Context, overwrittenVariables map[string]interface{}, opts ...Option) context.Context
+    // WithOverwrittenVariablesTTL works like WithOverwrittenVariables except that the
+    // given variables behave as if they were absent once the given TTL elapses.
+    WithOverwrittenVariablesTTL(ctx context.Context, overwrittenVariables map[string]interface{}, ttl time.Duration, opts ...Option) context.Context
+    // WithMutableOverwrittenVariables returns a new context.Context that holds a reference
+    // to the given overwritten variables, which can be modified later on.
+    WithMutableOverwrittenVariables(ctx context.Context, overwrittenVariables map[string]interface{}, opts ...Option) context.Context
+    // WithoutOverwrittenVariables returns a new context.Context in which the variables
+    // under the given names are no longer overwritten.
+    WithoutOverwrittenVariables(ctx context.Context, names ...string) context.Context
+    // WithOverwrittenLayer returns a new context.Context in which the given variables
+    // are overwritten with the given Priority.
+    WithOverwrittenLayer(ctx context.Context, overwrittenVariables map[string]interface{}, priority Priority, opts ...Option) context.Context
+    // WithTargeting returns a new context.Context in which variables are overwritten
+    // according to their Targeting.
+    WithTargeting(ctx context.Context, extractor AttributesExtractor, targetings map[string]Targeting, opts ...Option) context.Context
+    // WithOverwrittenVariablesFromJSON parses the given JSON object and returns a new
+    // context.Context that holds a reference to its fields as overwritten variables.
+    WithOverwrittenVariablesFromJSON(ctx context.Context, data []byte, opts ...Option) (context.Context, error)
+    // WithOverwrittenVariablesFromYAML parses the given YAML document and returns a new
+    // context.Context that holds a reference to its fields as overwritten variables.
+    WithOverwrittenVariablesFromYAML(ctx context.Context, data []byte, opts ...Option) (context.Context, error)
+    // WithOverwrittenVariablesFromWire deserializes the data produced by MarshalStorage and
+    // returns a new context.Context that holds a reference to these overwritten variables.
+    WithOverwrittenVariablesFromWire(ctx context.Context, data []byte, opts ...Option) (context.Context, error)
 }
 
 type overwritingOps struct{}
 
 // ExtractOverwritingStorage returns the Storage currently associated with ctx, or
-// nil if no such Storage could be found.
+// the default Storage set via SetDefaultStorage if no such Storage could be found.
 func (overwritingOps) ExtractOverwritingStorage(ctx context.Context) Storage {
     return ExtractOverwritingStorage(ctx)
 }
@@ -27,6 +55,83 @@ func (overwritingOps) GetOverwrittenValue(ctx context.Context, name string) Valu
     return GetOverwrittenValue(ctx, name)
 }
 
+// WithOverwrittenVariables returns a new context.Context that holds a reference to
+// the given overwritten variables.
+func (overwritingOps) WithOverwrittenVariables(
+    ctx context.Context,
+    overwrittenVariables map[string]interface{},
+    opts ...Option,
+) context.Context {
+    return WithOverwrittenVariables(ctx, overwrittenVariables, opts...)
+}
+
+// WithOverwrittenVariablesTTL works like WithOverwrittenVariables except that the
+// given variables behave as if they were absent once the given TTL elapses.
+func (overwritingOps) WithOverwrittenVariablesTTL(
+    ctx context.Context,
+    overwrittenVariables map[string]interface{},
+    ttl time.Duration,
+    opts ...Option,
+) context.Context {
+    return WithOverwrittenVariablesTTL(ctx, overwrittenVariables, ttl, opts...)
+}
+
+// WithMutableOverwrittenVariables returns a new context.Context that holds a reference
+// to the given overwritten variables, which can be modified later on.
+func (overwritingOps) WithMutableOverwrittenVariables(
+    ctx context.Context,
+    overwrittenVariables map[string]interface{},
+    opts ...Option,
+) context.Context {
+    return WithMutableOverwrittenVariables(ctx, overwrittenVariables, opts...)
+}
+
+// WithoutOverwrittenVariables returns a new context.Context in which the variables
+// under the given names are no longer overwritten.
+func (overwritingOps) WithoutOverwrittenVariables(ctx context.Context, names ...string) context.Context {
+    return WithoutOverwrittenVariables(ctx, names...)
+}
+
+// WithOverwrittenLayer returns a new context.Context in which the given variables
+// are overwritten with the given Priority.
+func (overwritingOps) WithOverwrittenLayer(
+    ctx context.Context,
+    overwrittenVariables map[string]interface{},
+    priority Priority,
+    opts ...Option,
+) context.Context {
+    return WithOverwrittenLayer(ctx, overwrittenVariables, priority, opts...)
+}
+
+// WithTargeting returns a new context.Context in which variables are overwritten
+// according to their Targeting.
+func (overwritingOps) WithTargeting(
+    ctx context.Context,
+    extractor AttributesExtractor,
+    targetings map[string]Targeting,
+    opts ...Option,
+) context.Context {
+    return WithTargeting(ctx, extractor, targetings, opts...)
+}
+
+// WithOverwrittenVariablesFromJSON parses the given JSON object and returns a new
+// context.Context that holds a reference to its fields as overwritten variables.
+func (overwritingOps) WithOverwrittenVariablesFromJSON(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+    return WithOverwrittenVariablesFromJSON(ctx, data, opts...)
+}
+
+// WithOverwrittenVariablesFromYAML parses the given YAML document and returns a new
+// context.Context that holds a reference to its fields as overwritten variables.
+func (overwritingOps) WithOverwrittenVariablesFromYAML(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+    return WithOverwrittenVariablesFromYAML(ctx, data, opts...)
+}
+
+// WithOverwrittenVariablesFromWire deserializes the data produced by MarshalStorage and
+// returns a new context.Context that holds a reference to these overwritten variables.
+func (overwritingOps) WithOverwrittenVariablesFromWire(ctx context.Context, data []byte, opts ...Option) (context.Context, error) {
+    return WithOverwrittenVariablesFromWire(ctx, data, opts...)
+}
+
 // Ops provides a wrapper around all overwriting-related functions provided by the library.
 // It can be mocked to help write tests more fluently.
 var Ops IOverwritingOps = overwritingOps{}
@@ -0,0 +1,59 @@
+package dvow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMockOps_WithOverwrittenVariables(t *testing.T) {
+	opsMock, cleanup := MockOps()
+	defer cleanup()
+
+	ctx := context.Background()
+	installed := context.WithValue(ctx, overwritingStorageKey, dynamicOverwritingStorage{})
+
+	opsMock.On("WithOverwrittenVariables", ctx, map[string]interface{}{"a": "1"}, mock.Anything, mock.Anything).Return(installed).Twice()
+
+	actual, err := WithOverwrittenVariablesFromJSON(ctx, []byte(`{"a": "1"}`), LenientConversion())
+	assert.Nil(t, err)
+	assert.Equal(t, installed, actual, "loaders must install storages via Ops")
+
+	actual, err = WithOverwrittenVariablesFromYAML(ctx, []byte(`a: "1"`), LenientConversion())
+	assert.Nil(t, err)
+	assert.Equal(t, installed, actual, "loaders must install storages via Ops")
+
+	opsMock.On("WithOverwrittenVariablesFromJSON", ctx, []byte(`{}`)).Return(nil, assert.AnError).Once()
+
+	actual, err = Ops.WithOverwrittenVariablesFromJSON(ctx, []byte(`{}`))
+	assert.Nil(t, actual)
+	assert.Equal(t, assert.AnError, err)
+
+	mock.AssertExpectationsForObjects(t, opsMock)
+}
+
+func TestMockOps_Derivations(t *testing.T) {
+	opsMock, cleanup := MockOps()
+	defer cleanup()
+
+	ctx := context.Background()
+	installed := context.WithValue(ctx, overwritingStorageKey, dynamicOverwritingStorage{})
+	variables := map[string]interface{}{"a": 1}
+
+	opsMock.On("WithOverwrittenVariablesTTL", ctx, variables, time.Minute).Return(installed).Once()
+	opsMock.On("WithMutableOverwrittenVariables", ctx, variables).Return(installed).Once()
+	opsMock.On("WithoutOverwrittenVariables", ctx, "a", "b").Return(installed).Once()
+	opsMock.On("WithOverwrittenLayer", ctx, variables, PriorityExperiment).Return(installed).Once()
+	opsMock.On("WithTargeting", ctx, mock.Anything, map[string]Targeting{}).Return(installed).Once()
+
+	assert.Equal(t, installed, Ops.WithOverwrittenVariablesTTL(ctx, variables, time.Minute))
+	assert.Equal(t, installed, Ops.WithMutableOverwrittenVariables(ctx, variables))
+	assert.Equal(t, installed, Ops.WithoutOverwrittenVariables(ctx, "a", "b"))
+	assert.Equal(t, installed, Ops.WithOverwrittenLayer(ctx, variables, PriorityExperiment))
+	assert.Equal(t, installed, Ops.WithTargeting(ctx, func(context.Context) Attributes { return nil }, map[string]Targeting{}))
+
+	mock.AssertExpectationsForObjects(t, opsMock)
+}
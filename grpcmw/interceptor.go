@@ -0,0 +1,105 @@
+// Package grpcmw provides gRPC server interceptors that set up every package of this
+// library on the context of each RPC.
+package grpcmw
+
+import (
+	"context"
+
+	"github.com/jamestrandung/go-context/config"
+	"github.com/jamestrandung/go-context/dvow"
+	dvowgrpcmw "github.com/jamestrandung/go-context/dvow/grpcmw"
+	"github.com/jamestrandung/go-context/memoize"
+	"google.golang.org/grpc"
+)
+
+// Config configures the interceptors returned by UnaryServerInterceptor and
+// StreamServerInterceptor.
+type Config struct {
+	// ConcurrencyLevel is the number of shards of the memoize cache installed on every
+	// RPC. It defaults to the ConcurrencyLevel of config.Default if zero.
+	ConcurrencyLevel int
+	// PropagateOverwrites makes the interceptors reconstruct the overwritten variables
+	// sent by clients using the interceptors of the dvow/grpcmw package.
+	PropagateOverwrites bool
+	// OverwriteOptions are passed to dvow.WithOverwrittenVariables when propagating
+	// overwritten variables.
+	OverwriteOptions []dvow.Option
+}
+
+// UnaryServerInterceptor returns a server interceptor that installs a memoize cache on
+// the context of every RPC and destroys it once the handler returns, even if it panics.
+func UnaryServerInterceptor(cfg Config) grpc.UnaryServerInterceptor {
+	overwrites := dvowgrpcmw.UnaryServerInterceptor(cfg.OverwriteOptions...)
+
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		withCache := func(ctx context.Context, req interface{}) (interface{}, error) {
+			ctx, destroyFn := memoize.WithConcurrentCache(ctx, concurrencyLevel(cfg), config.Default().MemoizeOptions()...)
+			defer destroyFn()
+
+			return handler(ctx, req)
+		}
+
+		if !cfg.PropagateOverwrites {
+			return withCache(ctx, req)
+		}
+
+		return overwrites(ctx, req, info, withCache)
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor that installs a memoize cache on
+// the context of every stream and destroys it once the handler returns, even if it
+// panics.
+func StreamServerInterceptor(cfg Config) grpc.StreamServerInterceptor {
+	overwrites := dvowgrpcmw.StreamServerInterceptor(cfg.OverwriteOptions...)
+
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		withCache := func(srv interface{}, ss grpc.ServerStream) error {
+			ctx, destroyFn := memoize.WithConcurrentCache(ss.Context(), concurrencyLevel(cfg), config.Default().MemoizeOptions()...)
+			defer destroyFn()
+
+			return handler(
+				srv, &serverStream{
+					ServerStream: ss,
+					ctx:          ctx,
+				},
+			)
+		}
+
+		if !cfg.PropagateOverwrites {
+			return withCache(srv, ss)
+		}
+
+		return overwrites(srv, ss, info, withCache)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context ...
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// concurrencyLevel returns the ConcurrencyLevel of the given Config, or that of
+// config.Default if it is zero.
+func concurrencyLevel(cfg Config) int {
+	if cfg.ConcurrencyLevel != 0 {
+		return cfg.ConcurrencyLevel
+	}
+
+	return config.Default().Memoize.ConcurrencyLevel
+}
@@ -0,0 +1,192 @@
+package grpcmw
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jamestrandung/go-context/dvow"
+	dvowgrpcmw "github.com/jamestrandung/go-context/dvow/grpcmw"
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type interceptorTestKey struct{}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func execute(ctx context.Context) (memoize.TypedOutcome[int], memoize.Extra) {
+	return memoize.Execute(ctx, interceptorTestKey{}, func(context.Context) (int, error) {
+		return 1, nil
+	})
+}
+
+// incomingContext returns a context whose incoming metadata carries the overwritten
+// variables of the given context as sent by the client interceptors of dvow/grpcmw.
+func incomingContext(t *testing.T, overwrittenVariables map[string]interface{}) context.Context {
+	ctx := dvow.WithOverwrittenVariables(context.Background(), overwrittenVariables)
+
+	var incomingCtx context.Context
+	err := dvowgrpcmw.UnaryClientInterceptor()(
+		ctx, "/svc/method", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			incomingCtx = metadata.NewIncomingContext(context.Background(), md)
+			return nil
+		},
+	)
+	assert.Nil(t, err)
+
+	return incomingCtx
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "cache is installed and destroyed",
+			test: func(t *testing.T) {
+				var rpcCtx context.Context
+				_, err := UnaryServerInterceptor(Config{})(
+					context.Background(), nil, &grpc.UnaryServerInfo{},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						rpcCtx = ctx
+
+						_, extra := execute(ctx)
+						assert.True(t, extra.IsMemoized)
+
+						return nil, nil
+					},
+				)
+				assert.Nil(t, err)
+
+				outcome, _ := execute(rpcCtx)
+				assert.Equal(t, memoize.ErrCacheAlreadyDestroyed, outcome.Err)
+			},
+		},
+		{
+			desc: "cache is destroyed on panic",
+			test: func(t *testing.T) {
+				var rpcCtx context.Context
+				assert.Panics(t, func() {
+					_, _ = UnaryServerInterceptor(Config{})(
+						context.Background(), nil, &grpc.UnaryServerInfo{},
+						func(ctx context.Context, req interface{}) (interface{}, error) {
+							rpcCtx = ctx
+							panic("boom")
+						},
+					)
+				})
+
+				outcome, _ := execute(rpcCtx)
+				assert.Equal(t, memoize.ErrCacheAlreadyDestroyed, outcome.Err)
+			},
+		},
+		{
+			desc: "overwrites are propagated",
+			test: func(t *testing.T) {
+				ctx := incomingContext(t, map[string]interface{}{"enabled": true})
+
+				_, err := UnaryServerInterceptor(Config{PropagateOverwrites: true})(
+					ctx, nil, &grpc.UnaryServerInfo{},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						assert.True(t, dvow.GetOrDefaultAs(ctx, "enabled", false))
+
+						_, extra := execute(ctx)
+						assert.True(t, extra.IsMemoized)
+
+						return nil, nil
+					},
+				)
+				assert.Nil(t, err)
+			},
+		},
+		{
+			desc: "overwrites are not propagated by default",
+			test: func(t *testing.T) {
+				ctx := incomingContext(t, map[string]interface{}{"enabled": true})
+
+				_, err := UnaryServerInterceptor(Config{})(
+					ctx, nil, &grpc.UnaryServerInfo{},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						assert.False(t, dvow.GetOrDefaultAs(ctx, "enabled", false))
+						return nil, nil
+					},
+				)
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "cache is installed and destroyed",
+			test: func(t *testing.T) {
+				var streamCtx context.Context
+				err := StreamServerInterceptor(Config{ConcurrencyLevel: 4})(
+					nil, &fakeServerStream{ctx: context.Background()}, &grpc.StreamServerInfo{},
+					func(srv interface{}, ss grpc.ServerStream) error {
+						streamCtx = ss.Context()
+
+						_, extra := execute(streamCtx)
+						assert.True(t, extra.IsMemoized)
+
+						return nil
+					},
+				)
+				assert.Nil(t, err)
+
+				outcome, _ := execute(streamCtx)
+				assert.Equal(t, memoize.ErrCacheAlreadyDestroyed, outcome.Err)
+			},
+		},
+		{
+			desc: "overwrites are propagated",
+			test: func(t *testing.T) {
+				ctx := incomingContext(t, map[string]interface{}{"enabled": true})
+
+				err := StreamServerInterceptor(Config{PropagateOverwrites: true})(
+					nil, &fakeServerStream{ctx: ctx}, &grpc.StreamServerInfo{},
+					func(srv interface{}, ss grpc.ServerStream) error {
+						assert.True(t, dvow.GetOrDefaultAs(ss.Context(), "enabled", false))
+
+						_, extra := execute(ss.Context())
+						assert.True(t, extra.IsMemoized)
+
+						return nil
+					},
+				)
+				assert.Nil(t, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package helper
+
+import (
+	"reflect"
+)
+
+// DeepCopier is implemented by types that know how to deep copy themselves, e.g.
+// those whose unexported fields hold references that must not be shared.
+type DeepCopier interface {
+	// DeepCopy returns a deep copy of the receiver, which must be of the same type.
+	DeepCopy() interface{}
+}
+
+// DeepCopy returns a deep copy of v. Maps, slices, arrays, pointers, interfaces and
+// exported struct fields are copied recursively while unexported struct fields are
+// copied as-is since they cannot be set via reflection. Values implementing
+// DeepCopier are copied by their DeepCopy methods instead.
+func DeepCopy[T any](v T) T {
+	var result T
+
+	copied := copyValue(reflect.ValueOf(&v).Elem(), make(map[uintptr]reflect.Value))
+	reflect.ValueOf(&result).Elem().Set(copied)
+
+	return result
+}
+
+func copyValue(v reflect.Value, visited map[uintptr]reflect.Value) reflect.Value {
+	if copied, ok := copyWithCopier(v); ok {
+		return copied
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		if copied, ok := visited[v.Pointer()]; ok {
+			return copied
+		}
+
+		copied := reflect.New(v.Type().Elem())
+		visited[v.Pointer()] = copied
+		copied.Elem().Set(copyValue(v.Elem(), visited))
+
+		return copied
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(copyValue(v.Elem(), visited))
+
+		return copied
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(copyValue(iter.Key(), visited), copyValue(iter.Value(), visited))
+		}
+
+		return copied
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(copyValue(v.Index(i), visited))
+		}
+
+		return copied
+
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(copyValue(v.Index(i), visited))
+		}
+
+		return copied
+
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				copied.Field(i).Set(copyValue(v.Field(i), visited))
+			}
+		}
+
+		return copied
+
+	default:
+		return v
+	}
+}
+
+// copyWithCopier copies v using its DeepCopy method if it implements DeepCopier and
+// returns a value of the same type.
+func copyWithCopier(v reflect.Value) (reflect.Value, bool) {
+	if !v.CanInterface() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return reflect.Value{}, false
+	}
+
+	copier, ok := v.Interface().(DeepCopier)
+	if !ok {
+		return reflect.Value{}, false
+	}
+
+	copied := reflect.ValueOf(copier.DeepCopy())
+	if !copied.IsValid() || copied.Type() != v.Type() {
+		return reflect.Value{}, false
+	}
+
+	return copied, true
+}
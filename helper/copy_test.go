@@ -0,0 +1,115 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type copierTestValue struct {
+	items []int
+}
+
+func (v copierTestValue) DeepCopy() interface{} {
+	return copierTestValue{items: append([]int(nil), v.items...)}
+}
+
+func TestDeepCopy(t *testing.T) {
+	type node struct {
+		Name     string
+		Children []*node
+		Labels   map[string]interface{}
+		Parent   *node
+		private  []int
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "nil",
+			test: func(t *testing.T) {
+				assert.Nil(t, DeepCopy[interface{}](nil))
+				assert.Nil(t, DeepCopy[*int](nil))
+			},
+		},
+		{
+			desc: "scalar",
+			test: func(t *testing.T) {
+				assert.Equal(t, 1, DeepCopy(1))
+				assert.Equal(t, "a", DeepCopy("a"))
+			},
+		},
+		{
+			desc: "nested maps and slices",
+			test: func(t *testing.T) {
+				original := map[string]interface{}{
+					"list": []interface{}{1, map[string]interface{}{"a": 1}},
+					"arr":  [1][]int{{1}},
+				}
+
+				copied := DeepCopy(original)
+				assert.Equal(t, original, copied)
+
+				copied["list"].([]interface{})[1].(map[string]interface{})["a"] = 2
+				copied["arr"].([1][]int)[0][0] = 2
+
+				assert.Equal(t, 1, original["list"].([]interface{})[1].(map[string]interface{})["a"])
+				assert.Equal(t, 1, original["arr"].([1][]int)[0][0])
+			},
+		},
+		{
+			desc: "values behind interfaces",
+			test: func(t *testing.T) {
+				var original interface{} = map[string]interface{}{"a": []int{1}}
+
+				copied := DeepCopy(original)
+				copied.(map[string]interface{})["a"].([]int)[0] = 2
+
+				assert.Equal(t, 1, original.(map[string]interface{})["a"].([]int)[0])
+			},
+		},
+		{
+			desc: "DeepCopier",
+			test: func(t *testing.T) {
+				original := map[string]copierTestValue{"a": {items: []int{1}}}
+
+				copied := DeepCopy(original)
+				copied["a"].items[0] = 2
+
+				assert.Equal(t, 1, original["a"].items[0])
+			},
+		},
+		{
+			desc: "pointers with cycles",
+			test: func(t *testing.T) {
+				root := &node{
+					Name:    "root",
+					Labels:  map[string]interface{}{"a": 1},
+					private: []int{1},
+				}
+				root.Children = []*node{{Name: "child", Parent: root}}
+
+				copied := DeepCopy(root)
+				assert.Equal(t, "root", copied.Name)
+				assert.Equal(t, []int{1}, copied.private)
+				assert.True(t, copied == copied.Children[0].Parent)
+				assert.False(t, root == copied)
+
+				copied.Labels["a"] = 2
+				copied.Children[0].Name = "changed"
+
+				assert.Equal(t, 1, root.Labels["a"])
+				assert.Equal(t, "child", root.Children[0].Name)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,253 @@
+package helper
+
+import (
+	"reflect"
+)
+
+// EqualOption configures how Equal compares values.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	ignoreUnexported bool
+	coerceTypes      bool
+}
+
+// IgnoreUnexported makes Equal skip unexported struct fields.
+func IgnoreUnexported() EqualOption {
+	return func(o *equalOptions) {
+		o.ignoreUnexported = true
+	}
+}
+
+// CoerceTypes makes Equal compare numbers, strings and booleans by value regardless
+// of their types, e.g. int(1) equals float64(1) and a named string type equals a
+// plain string holding the same text. Maps, slices and arrays of different types
+// are compared element by element.
+func CoerceTypes() EqualOption {
+	return func(o *equalOptions) {
+		o.coerceTypes = true
+	}
+}
+
+// Equal returns whether a and b are deeply equal. Without options, it behaves
+// exactly like reflect.DeepEqual.
+func Equal(a interface{}, b interface{}, opts ...EqualOption) bool {
+	if len(opts) == 0 {
+		return reflect.DeepEqual(a, b)
+	}
+
+	c := comparer{
+		visited: make(map[visit]bool),
+	}
+
+	for _, opt := range opts {
+		opt(&c.options)
+	}
+
+	return c.equal(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+// visit is a pair of pointers being compared, used to stop at cycles.
+type visit struct {
+	a   uintptr
+	b   uintptr
+	typ reflect.Type
+}
+
+type comparer struct {
+	options equalOptions
+	visited map[visit]bool
+}
+
+func (c comparer) equal(a reflect.Value, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+
+	if c.options.coerceTypes {
+		if equal, ok := coerceEqual(a, b); ok {
+			return equal
+		}
+	}
+
+	if a.Type() != b.Type() && (!c.options.coerceTypes || a.Kind() != b.Kind()) {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		if a.Pointer() == b.Pointer() {
+			return true
+		}
+
+		v := visit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+		if c.visited[v] {
+			return true
+		}
+
+		c.visited[v] = true
+
+		return c.equal(a.Elem(), b.Elem())
+
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+
+		return c.equal(a.Elem(), b.Elem())
+
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+
+		if a.Type().Key() != b.Type().Key() {
+			return false
+		}
+
+		iter := a.MapRange()
+		for iter.Next() {
+			value := b.MapIndex(iter.Key())
+			if !value.IsValid() || !c.equal(iter.Value(), value) {
+				return false
+			}
+		}
+
+		return true
+
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			return false
+		}
+
+		return c.equalElements(a, b)
+
+	case reflect.Array:
+		return c.equalElements(a, b)
+
+	case reflect.Struct:
+		if a.Type() != b.Type() {
+			return false
+		}
+
+		for i := 0; i < a.NumField(); i++ {
+			if c.options.ignoreUnexported && !a.Type().Field(i).IsExported() {
+				continue
+			}
+
+			if !c.equal(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+
+	case reflect.Func:
+		return a.IsNil() && b.IsNil()
+
+	case reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+
+	case reflect.Float32, reflect.Float64:
+		return a.Float() == b.Float()
+
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+
+	case reflect.String:
+		return a.String() == b.String()
+
+	default:
+		return false
+	}
+}
+
+func (c comparer) equalElements(a reflect.Value, b reflect.Value) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+
+	for i := 0; i < a.Len(); i++ {
+		if !c.equal(a.Index(i), b.Index(i)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// coerceEqual compares a and b by value if both are numbers, strings or booleans, and
+// returns false as the second result otherwise.
+func coerceEqual(a reflect.Value, b reflect.Value) (bool, bool) {
+	switch {
+	case a.Kind() == reflect.String && b.Kind() == reflect.String:
+		return a.String() == b.String(), true
+	case a.Kind() == reflect.Bool && b.Kind() == reflect.Bool:
+		return a.Bool() == b.Bool(), true
+	case isNumber(a.Kind()) && isNumber(b.Kind()):
+		return numberEqual(a, b), true
+	default:
+		return false, false
+	}
+}
+
+func numberEqual(a reflect.Value, b reflect.Value) bool {
+	switch {
+	case isInt(a.Kind()) && isInt(b.Kind()):
+		return a.Int() == b.Int()
+	case isUint(a.Kind()) && isUint(b.Kind()):
+		return a.Uint() == b.Uint()
+	case isInt(a.Kind()) && isUint(b.Kind()):
+		return a.Int() >= 0 && uint64(a.Int()) == b.Uint()
+	case isUint(a.Kind()) && isInt(b.Kind()):
+		return b.Int() >= 0 && a.Uint() == uint64(b.Int())
+	default:
+		return toFloat(a) == toFloat(b)
+	}
+}
+
+func toFloat(v reflect.Value) float64 {
+	switch {
+	case isInt(v.Kind()):
+		return float64(v.Int())
+	case isUint(v.Kind()):
+		return float64(v.Uint())
+	default:
+		return v.Float()
+	}
+}
+
+func isNumber(kind reflect.Kind) bool {
+	return isInt(kind) || isUint(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func isInt(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUint(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return false
+	}
+}
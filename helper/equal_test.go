@@ -0,0 +1,79 @@
+package helper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type equalTestValue struct {
+	Name    string
+	Items   []interface{}
+	Next    *equalTestValue
+	private int
+}
+
+type equalTestString string
+
+func TestEqual(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without options",
+			test: func(t *testing.T) {
+				assert.True(t, Equal(nil, nil))
+				assert.True(t, Equal(map[string]interface{}{"a": []int{1}}, map[string]interface{}{"a": []int{1}}))
+				assert.False(t, Equal(1, 1.0))
+				assert.False(t, Equal(equalTestValue{private: 1}, equalTestValue{private: 2}))
+			},
+		},
+		{
+			desc: "ignore unexported",
+			test: func(t *testing.T) {
+				a := &equalTestValue{Name: "a", Items: []interface{}{1}, private: 1}
+				b := &equalTestValue{Name: "a", Items: []interface{}{1}, private: 2}
+
+				assert.True(t, Equal(a, b, IgnoreUnexported()))
+
+				b.Items[0] = 2
+				assert.False(t, Equal(a, b, IgnoreUnexported()))
+			},
+		},
+		{
+			desc: "coerce types",
+			test: func(t *testing.T) {
+				assert.True(t, Equal(1, 1.0, CoerceTypes()))
+				assert.True(t, Equal(int8(1), uint64(1), CoerceTypes()))
+				assert.False(t, Equal(-1, uint64(1<<64-1), CoerceTypes()))
+				assert.True(t, Equal(equalTestString("a"), "a", CoerceTypes()))
+				assert.True(t, Equal([]interface{}{1, "a"}, []interface{}{1.0, equalTestString("a")}, CoerceTypes()))
+				assert.True(t, Equal(map[string]interface{}{"a": 1}, map[string]interface{}{"a": int64(1)}, CoerceTypes()))
+				assert.True(t, Equal([]int{1}, []float64{1}, CoerceTypes()))
+				assert.False(t, Equal([]int{1}, []float64{1.5}, CoerceTypes()))
+				assert.False(t, Equal(1, "1", CoerceTypes()))
+				assert.False(t, Equal(1, nil, CoerceTypes()))
+			},
+		},
+		{
+			desc: "cycles",
+			test: func(t *testing.T) {
+				a := &equalTestValue{Name: "a"}
+				a.Next = a
+
+				b := &equalTestValue{Name: "a"}
+				b.Next = b
+
+				assert.True(t, Equal(a, b, IgnoreUnexported()))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
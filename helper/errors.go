@@ -0,0 +1,8 @@
+package helper
+
+import "errors"
+
+var (
+	// ErrUnhashable is returned by Hash when a value cannot be hashed.
+	ErrUnhashable = errors.New("value cannot be hashed")
+)
@@ -0,0 +1,83 @@
+package helper
+
+import (
+	"fmt"
+	"hash/fnv"
+
+	"github.com/mitchellh/hashstructure/v2"
+	"github.com/pkg/errors"
+)
+
+// Hasher computes the hash of an arbitrary value.
+type Hasher func(v interface{}) (uint64, error)
+
+// HashOption configures how Hash computes hashes.
+type HashOption func(*hashOptions)
+
+type hashOptions struct {
+	hasher Hasher
+}
+
+// WithHasher makes Hash use the given Hasher instead of StructureHasher.
+func WithHasher(hasher Hasher) HashOption {
+	return func(o *hashOptions) {
+		if hasher != nil {
+			o.hasher = hasher
+		}
+	}
+}
+
+// Hash returns the hash of v computed by StructureHasher, or by the Hasher given via
+// WithHasher. It never panics; values that cannot be hashed, including those making
+// the Hasher panic, return an error wrapping ErrUnhashable.
+func Hash(v interface{}, opts ...HashOption) (hash uint64, err error) {
+	o := hashOptions{
+		hasher: StructureHasher,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			hash, err = 0, errors.Wrap(ErrUnhashable, fmt.Sprintf("%T: %v", v, r))
+		}
+	}()
+
+	hash, err = o.hasher(v)
+	if err != nil {
+		return 0, errors.Wrap(ErrUnhashable, fmt.Sprintf("%T: %v", v, err))
+	}
+
+	return hash, nil
+}
+
+// StructureHasher hashes the structure of v recursively, so that values that are
+// deeply equal have the same hash. Types implementing fmt.Stringer are hashed using
+// their string representations.
+func StructureHasher(v interface{}) (uint64, error) {
+	return hashstructure.Hash(v, hashstructure.FormatV2, &hashstructure.HashOptions{UseStringer: true})
+}
+
+// FNV64aHasher hashes the bytes of strings, byte slices and fmt.Stringer using the
+// 64-bit FNV-1a algorithm, whose output is stable across versions and processes.
+// Other values cannot be hashed.
+func FNV64aHasher(v interface{}) (uint64, error) {
+	var b []byte
+	switch value := v.(type) {
+	case string:
+		b = []byte(value)
+	case []byte:
+		b = value
+	case fmt.Stringer:
+		b = []byte(value.String())
+	default:
+		return 0, fmt.Errorf("unsupported type %T", v)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write(b)
+
+	return h.Sum64(), nil
+}
@@ -0,0 +1,101 @@
+package helper
+
+import (
+	"errors"
+	"hash/fnv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "deeply equal values have the same hash",
+			test: func(t *testing.T) {
+				h1, err := Hash(map[string]interface{}{"a": []int{1, 2}})
+				assert.Nil(t, err)
+
+				h2, err := Hash(map[string]interface{}{"a": []int{1, 2}})
+				assert.Nil(t, err)
+				assert.Equal(t, h1, h2)
+
+				h3, err := Hash(map[string]interface{}{"a": []int{2, 1}})
+				assert.Nil(t, err)
+				assert.NotEqual(t, h1, h3)
+			},
+		},
+		{
+			desc: "unhashable value",
+			test: func(t *testing.T) {
+				hash, err := Hash(func() {})
+				assert.ErrorIs(t, err, ErrUnhashable)
+				assert.Equal(t, uint64(0), hash)
+			},
+		},
+		{
+			desc: "panicking hasher",
+			test: func(t *testing.T) {
+				hash, err := Hash(1, WithHasher(func(v interface{}) (uint64, error) {
+					panic("boom")
+				}))
+
+				assert.ErrorIs(t, err, ErrUnhashable)
+				assert.Contains(t, err.Error(), "boom")
+				assert.Equal(t, uint64(0), hash)
+			},
+		},
+		{
+			desc: "custom hasher",
+			test: func(t *testing.T) {
+				hash, err := Hash(1, WithHasher(func(v interface{}) (uint64, error) {
+					return 42, nil
+				}))
+				assert.Nil(t, err)
+				assert.Equal(t, uint64(42), hash)
+
+				_, err = Hash(1, WithHasher(func(v interface{}) (uint64, error) {
+					return 42, errors.New("failed")
+				}))
+				assert.ErrorIs(t, err, ErrUnhashable)
+			},
+		},
+		{
+			desc: "nil hasher is ignored",
+			test: func(t *testing.T) {
+				expected, _ := StructureHasher("a")
+
+				hash, err := Hash("a", WithHasher(nil))
+				assert.Nil(t, err)
+				assert.Equal(t, expected, hash)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestFNV64aHasher(t *testing.T) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte("abc"))
+	expected := h.Sum64()
+
+	hash, err := Hash("abc", WithHasher(FNV64aHasher))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, hash)
+
+	hash, err = Hash([]byte("abc"), WithHasher(FNV64aHasher))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, hash)
+
+	_, err = Hash(1, WithHasher(FNV64aHasher))
+	assert.ErrorIs(t, err, ErrUnhashable)
+}
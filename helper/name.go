@@ -0,0 +1,33 @@
+package helper
+
+import (
+	"reflect"
+	"regexp"
+)
+
+// packagePathPrefix matches the import path of a package up to its last slash, e.g.
+// "github.com/jamestrandung/" in "github.com/jamestrandung/go-context.Key".
+var packagePathPrefix = regexp.MustCompile(`[\w.\-~]+/`)
+
+// NameOf returns a readable name of the dynamic type of v, e.g. "*memoize.key" or
+// "dvow.Setting[time.Duration]", or "nil" if v is nil. Like reflect.Type.String,
+// named types are qualified by their package names. Types used to instantiate
+// generic types are qualified the same way instead of by their full import paths.
+func NameOf(v interface{}) string {
+	info, ok := DefaultTypeCache.Of(v)
+	if !ok {
+		return "nil"
+	}
+
+	return info.Name
+}
+
+// TypeNameOf returns a readable name of type T in the same format as NameOf. Unlike
+// NameOf, it also works for interface types, e.g. "error" or "context.Context".
+func TypeNameOf[T any]() string {
+	return DefaultTypeCache.Get(reflect.TypeOf((*T)(nil)).Elem()).Name
+}
+
+func nameOf(t reflect.Type) string {
+	return packagePathPrefix.ReplaceAllString(t.String(), "")
+}
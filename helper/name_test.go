@@ -0,0 +1,30 @@
+package helper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type nameTestKey struct{}
+
+type nameTestGeneric[T any] struct{}
+
+func TestNameOf(t *testing.T) {
+	assert.Equal(t, "nil", NameOf(nil))
+	assert.Equal(t, "int", NameOf(1))
+	assert.Equal(t, "map[string]interface {}", NameOf(map[string]interface{}{}))
+	assert.Equal(t, "helper.nameTestKey", NameOf(nameTestKey{}))
+	assert.Equal(t, "*helper.nameTestKey", NameOf(&nameTestKey{}))
+	assert.Equal(t, "helper.nameTestGeneric[helper.nameTestKey]", NameOf(nameTestGeneric[nameTestKey]{}))
+	assert.Equal(t, "[]*helper.nameTestGeneric[*helper.nameTestKey]", NameOf([]*nameTestGeneric[*nameTestKey]{}))
+}
+
+func TestTypeNameOf(t *testing.T) {
+	assert.Equal(t, "int", TypeNameOf[int]())
+	assert.Equal(t, "error", TypeNameOf[error]())
+	assert.Equal(t, "context.Context", TypeNameOf[context.Context]())
+	assert.Equal(t, "*helper.nameTestKey", TypeNameOf[*nameTestKey]())
+	assert.Equal(t, "helper.nameTestGeneric[context.Context]", TypeNameOf[nameTestGeneric[context.Context]]())
+}
@@ -5,7 +5,51 @@ import "reflect"
 // IsComparable returns whether v is not nil and has an underlying
 // type that is comparable.
 func IsComparable(v interface{}) bool {
-	return v != nil && reflect.TypeOf(v).Comparable()
+	info, ok := DefaultTypeCache.Of(v)
+	return ok && info.Comparable
+}
+
+// TryComparable returns whether v is not nil and can be compared, or
+// used as a map key, without panicking. Unlike IsComparable, it also
+// inspects the dynamic values held by interfaces inside v, e.g. in
+// struct fields or array elements, since these may not be comparable
+// even though the type of v is.
+func TryComparable(v interface{}) bool {
+	info, ok := DefaultTypeCache.Of(v)
+	if !ok || !info.Comparable {
+		return false
+	}
+
+	return !info.HoldsInterfaces || isComparable(reflect.ValueOf(v))
+}
+
+func isComparable(rv reflect.Value) bool {
+	if !rv.Type().Comparable() {
+		return false
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface:
+		return rv.IsNil() || isComparable(rv.Elem())
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if !isComparable(rv.Field(i)) {
+				return false
+			}
+		}
+
+		return true
+	case reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if !isComparable(rv.Index(i)) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return true
+	}
 }
 
 // IsSameType returns whether v1 and v2 are both not nil and have
@@ -16,6 +60,43 @@ func IsSameType(v1 interface{}, v2 interface{}) bool {
 
 // IsCastable returns whether v can be casted to type T.
 func IsCastable[T any](v interface{}) bool {
-	_, ok := v.(T)
+	_, ok := As[T](v)
 	return ok
 }
+
+// As returns v casted to type T and true if possible. Otherwise, it
+// returns the zero value of T and false. It never panics, even if v
+// is nil.
+func As[T any](v interface{}) (T, bool) {
+	casted, ok := v.(T)
+	return casted, ok
+}
+
+// IsNil returns whether v is nil or holds a nil value of a nillable
+// type, e.g. a nil pointer, slice or map stored in an interface.
+func IsNil(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// IsZero returns whether v is the zero value of type T.
+func IsZero[T comparable](v T) bool {
+	var zero T
+	return v == zero
+}
+
+// IsZeroValue returns whether v is nil or holds the zero value of its
+// type, e.g. 0, "", a nil map or a struct whose fields are all zero.
+// Unlike IsZero, it also works for types that are not comparable.
+func IsZeroValue(v interface{}) bool {
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
@@ -0,0 +1,130 @@
+package helper
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsComparable(t *testing.T) {
+	assert.False(t, IsComparable(nil))
+	assert.True(t, IsComparable(1))
+	assert.True(t, IsComparable(struct{ a int }{}))
+	assert.False(t, IsComparable([]int{}))
+	assert.False(t, IsComparable(map[string]int{}))
+}
+
+func TestTryComparable(t *testing.T) {
+	type key struct {
+		a int
+		b interface{}
+	}
+
+	assert.False(t, TryComparable(nil))
+	assert.True(t, TryComparable(1))
+	assert.True(t, TryComparable(key{a: 1, b: "b"}))
+	assert.True(t, TryComparable(key{a: 1}))
+	assert.True(t, TryComparable([2]interface{}{1, key{}}))
+	assert.False(t, TryComparable([]int{}))
+	assert.False(t, TryComparable(key{b: []int{}}))
+	assert.False(t, TryComparable(key{b: key{b: map[string]int{}}}))
+	assert.False(t, TryComparable([2]interface{}{1, []int{}}))
+}
+
+func TestIsSameType(t *testing.T) {
+	assert.False(t, IsSameType(nil, nil))
+	assert.False(t, IsSameType(1, nil))
+	assert.True(t, IsSameType(1, 2))
+	assert.False(t, IsSameType(1, int64(2)))
+}
+
+func TestIsCastable(t *testing.T) {
+	assert.True(t, IsCastable[int](1))
+	assert.False(t, IsCastable[int](int64(1)))
+	assert.False(t, IsCastable[int](nil))
+	assert.True(t, IsCastable[error](assert.AnError))
+	assert.False(t, IsCastable[error](nil))
+}
+
+func TestIsNil(t *testing.T) {
+	var nilError error
+	var nilPointer *int
+	var nilMap map[string]int
+	var nilSlice []int
+	var nilFunc func()
+	var nilChan chan int
+
+	assert.True(t, IsNil(nil))
+	assert.True(t, IsNil(nilError))
+	assert.True(t, IsNil(nilPointer))
+	assert.True(t, IsNil(nilMap))
+	assert.True(t, IsNil(nilSlice))
+	assert.True(t, IsNil(nilFunc))
+	assert.True(t, IsNil(nilChan))
+
+	assert.False(t, IsNil(0))
+	assert.False(t, IsNil(""))
+	assert.False(t, IsNil(struct{}{}))
+	assert.False(t, IsNil(new(int)))
+	assert.False(t, IsNil([]int{}))
+	assert.False(t, IsNil(map[string]int{}))
+}
+
+func TestIsZero(t *testing.T) {
+	assert.True(t, IsZero(0))
+	assert.True(t, IsZero(""))
+	assert.True(t, IsZero(struct{ a int }{}))
+	assert.False(t, IsZero(1))
+	assert.False(t, IsZero(struct{ a int }{a: 1}))
+}
+
+func TestIsZeroValue(t *testing.T) {
+	var nilMap map[string]int
+
+	assert.True(t, IsZeroValue(nil))
+	assert.True(t, IsZeroValue(0))
+	assert.True(t, IsZeroValue(nilMap))
+	assert.True(t, IsZeroValue(struct{ s []int }{}))
+	assert.False(t, IsZeroValue(map[string]int{}))
+	assert.False(t, IsZeroValue(struct{ s []int }{s: []int{}}))
+}
+
+func TestAs(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "concrete type",
+			test: func(t *testing.T) {
+				i, ok := As[int](1)
+				assert.True(t, ok)
+				assert.Equal(t, 1, i)
+
+				i, ok = As[int]("1")
+				assert.False(t, ok)
+				assert.Equal(t, 0, i)
+			},
+		},
+		{
+			desc: "interface type",
+			test: func(t *testing.T) {
+				s, ok := As[fmt.Stringer](nil)
+				assert.False(t, ok)
+				assert.Nil(t, s)
+
+				err, ok := As[error](assert.AnError)
+				assert.True(t, ok)
+				assert.Equal(t, assert.AnError, err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
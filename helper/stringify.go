@@ -0,0 +1,231 @@
+package helper
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Redactor is implemented by values that must not be rendered as-is by Stringify,
+// e.g. secrets or personal data.
+type Redactor interface {
+	// Redact returns the rendering of the receiver to use in place of its actual value.
+	Redact() string
+}
+
+const (
+	// maxStringifyElements is the number of elements of a map, slice or array rendered
+	// by Stringify before the remaining ones get summarized.
+	maxStringifyElements = 10
+	// maxStringifyDepth is the number of nested values rendered by Stringify before
+	// deeper ones get elided, which also stops at cycles.
+	maxStringifyDepth = 5
+	// truncationMarker is appended to renderings cut short by Stringify.
+	truncationMarker = "..."
+)
+
+// Stringify returns a human-readable rendering of v for diagnostics such as logs and
+// debug dumps, e.g. `map["a":1 "b":[1 2 3]]`. Values implementing Redactor are
+// rendered using their Redact methods, fmt.Stringer and error using their String and
+// Error methods. Maps are rendered with sorted keys and only their first elements,
+// like slices and arrays. The rendering is cut to at most maxLen bytes, marked by a
+// trailing "...", unless maxLen is not positive.
+func Stringify(v interface{}, maxLen int) string {
+	w := &boundedWriter{
+		maxLen: maxLen,
+	}
+
+	w.render(reflect.ValueOf(v), 0)
+
+	return w.String()
+}
+
+// boundedWriter renders values into a buffer until it holds maxLen bytes.
+type boundedWriter struct {
+	sb     strings.Builder
+	maxLen int
+	full   bool
+}
+
+func (w *boundedWriter) write(s string) {
+	if w.full {
+		return
+	}
+
+	if w.maxLen <= 0 || w.sb.Len()+len(s) <= w.maxLen {
+		w.sb.WriteString(s)
+		return
+	}
+
+	w.full = true
+
+	rendered := w.sb.String() + s
+
+	cut := w.maxLen - len(truncationMarker)
+	if cut < 0 {
+		cut = 0
+	}
+
+	for cut > 0 && !utf8.RuneStart(rendered[cut]) {
+		cut--
+	}
+
+	w.sb.Reset()
+	w.sb.WriteString(rendered[:cut])
+	w.sb.WriteString(truncationMarker)
+}
+
+func (w *boundedWriter) String() string {
+	s := w.sb.String()
+	if w.maxLen > 0 && len(s) > w.maxLen {
+		// maxLen is too small to even fit the truncation marker
+		return s[:w.maxLen]
+	}
+
+	return s
+}
+
+func (w *boundedWriter) render(rv reflect.Value, depth int) {
+	if w.full {
+		return
+	}
+
+	if !rv.IsValid() {
+		w.write("nil")
+		return
+	}
+
+	if rv.CanInterface() {
+		switch value := rv.Interface().(type) {
+		case Redactor:
+			if !IsNil(value) {
+				w.write(value.Redact())
+				return
+			}
+		case error:
+			if !IsNil(value) {
+				w.write(value.Error())
+				return
+			}
+		case fmt.Stringer:
+			if !IsNil(value) {
+				w.write(value.String())
+				return
+			}
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		if depth > maxStringifyDepth {
+			w.write(truncationMarker)
+			return
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if rv.IsNil() {
+			w.write("nil")
+			return
+		}
+
+		if rv.Kind() == reflect.Pointer {
+			w.write("&")
+		}
+
+		w.render(rv.Elem(), depth+1)
+
+	case reflect.String:
+		w.write(fmt.Sprintf("%q", rv.String()))
+
+	case reflect.Map:
+		w.renderMap(rv, depth)
+
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			w.write("nil")
+			return
+		}
+
+		w.write("[")
+		for i := 0; i < rv.Len() && i < maxStringifyElements; i++ {
+			if i > 0 {
+				w.write(" ")
+			}
+
+			w.render(rv.Index(i), depth+1)
+		}
+
+		w.writeRemaining(rv.Len())
+		w.write("]")
+
+	case reflect.Struct:
+		w.write("{")
+		for i := 0; i < rv.NumField(); i++ {
+			if i > 0 {
+				w.write(" ")
+			}
+
+			w.write(rv.Type().Field(i).Name + ":")
+			w.render(rv.Field(i), depth+1)
+		}
+
+		w.write("}")
+
+	default:
+		w.write(fmt.Sprint(rv))
+	}
+}
+
+func (w *boundedWriter) renderMap(rv reflect.Value, depth int) {
+	if rv.IsNil() {
+		w.write("nil")
+		return
+	}
+
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+
+	entries := make([]entry, 0, rv.Len())
+
+	iter := rv.MapRange()
+	for iter.Next() {
+		kw := &boundedWriter{}
+		kw.render(iter.Key(), depth+1)
+
+		entries = append(entries, entry{
+			key:   kw.String(),
+			value: iter.Value(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].key < entries[j].key
+	})
+
+	w.write("map[")
+	for i := 0; i < len(entries) && i < maxStringifyElements; i++ {
+		if i > 0 {
+			w.write(" ")
+		}
+
+		w.write(entries[i].key + ":")
+		w.render(entries[i].value, depth+1)
+	}
+
+	w.writeRemaining(len(entries))
+	w.write("]")
+}
+
+// writeRemaining summarizes the elements of a collection of the given length that
+// were not rendered.
+func (w *boundedWriter) writeRemaining(length int) {
+	if length > maxStringifyElements {
+		w.write(fmt.Sprintf(" ...+%d more", length-maxStringifyElements))
+	}
+}
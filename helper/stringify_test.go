@@ -0,0 +1,105 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stringifyTestSecret string
+
+func (s stringifyTestSecret) Redact() string {
+	return "<redacted>"
+}
+
+type stringifyTestNode struct {
+	Name   string
+	Secret stringifyTestSecret
+	Next   *stringifyTestNode
+	count  int
+}
+
+func TestStringify(t *testing.T) {
+	bigMap := make(map[int]int)
+	for i := 0; i < 20; i++ {
+		bigMap[i] = i
+	}
+
+	cyclic := &stringifyTestNode{Name: "a"}
+	cyclic.Next = cyclic
+
+	scenarios := []struct {
+		desc     string
+		value    interface{}
+		maxLen   int
+		expected string
+	}{
+		{
+			desc:     "nil",
+			value:    nil,
+			expected: "nil",
+		},
+		{
+			desc:     "scalars",
+			value:    []interface{}{1, 1.5, true, "a", nil},
+			expected: `[1 1.5 true "a" nil]`,
+		},
+		{
+			desc:     "stringer and error",
+			value:    []interface{}{time.Second, assert.AnError},
+			expected: `[1s ` + assert.AnError.Error() + `]`,
+		},
+		{
+			desc:     "map with sorted keys",
+			value:    map[string]interface{}{"b": []int{1}, "a": map[string]int(nil)},
+			expected: `map["a":nil "b":[1]]`,
+		},
+		{
+			desc:     "big map",
+			value:    bigMap,
+			expected: `map[0:0 1:1 10:10 11:11 12:12 13:13 14:14 15:15 16:16 17:17 ...+10 more]`,
+		},
+		{
+			desc:     "struct with redacted and unexported fields",
+			value:    &stringifyTestNode{Name: "a", Secret: "password", count: 1},
+			expected: `&{Name:"a" Secret:<redacted> Next:nil count:1}`,
+		},
+		{
+			desc:     "cycle",
+			value:    cyclic,
+			expected: `&{Name:"a" Secret:<redacted> Next:&{Name:"a" Secret:<redacted> Next:&{Name:"a" Secret:<redacted> Next:... count:0} count:0} count:0}`,
+		},
+		{
+			desc:     "truncated",
+			value:    strings.Repeat("a", 100),
+			maxLen:   10,
+			expected: `"aaaaaa...`,
+		},
+		{
+			desc:     "truncated at rune boundary",
+			value:    "ééééé",
+			maxLen:   8,
+			expected: `"éé...`,
+		},
+		{
+			desc:     "maxLen smaller than marker",
+			value:    "abc",
+			maxLen:   2,
+			expected: `..`,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			actual := Stringify(sc.value, sc.maxLen)
+			assert.Equal(t, sc.expected, actual)
+
+			if sc.maxLen > 0 {
+				assert.LessOrEqual(t, len(actual), sc.maxLen)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package helper
+
+import (
+	"reflect"
+	"sync"
+)
+
+// TypeInfo holds the metadata of a type that is expensive to compute via reflection.
+type TypeInfo struct {
+	// Type is the type described by this TypeInfo.
+	Type reflect.Type
+	// Name is the readable name of Type as returned by NameOf.
+	Name string
+	// Kind is the kind of Type.
+	Kind reflect.Kind
+	// Comparable is whether values of Type are comparable according to the type system.
+	Comparable bool
+	// HoldsInterfaces is whether values of Type hold interfaces, directly or in struct
+	// fields or array elements, whose dynamic values must be inspected to tell whether
+	// these values can actually be compared.
+	HoldsInterfaces bool
+}
+
+// TypeCache memoizes the TypeInfo of every type it is asked about. It is safe for
+// concurrent use and its zero value is ready to use. Types are never evicted, which
+// is fine since a program only has a bounded number of them.
+type TypeCache struct {
+	infos sync.Map
+}
+
+// DefaultTypeCache is the TypeCache used by the helpers of this package, e.g. NameOf
+// and TryComparable.
+var DefaultTypeCache = &TypeCache{}
+
+// Get returns the TypeInfo of the given type.
+func (c *TypeCache) Get(t reflect.Type) TypeInfo {
+	if info, ok := c.infos.Load(t); ok {
+		return info.(TypeInfo)
+	}
+
+	info := TypeInfo{
+		Type:            t,
+		Name:            nameOf(t),
+		Kind:            t.Kind(),
+		Comparable:      t.Comparable(),
+		HoldsInterfaces: holdsInterfaces(t),
+	}
+
+	c.infos.Store(t, info)
+
+	return info
+}
+
+// Of returns the TypeInfo of the dynamic type of v and true, or false if v is nil.
+func (c *TypeCache) Of(v interface{}) (TypeInfo, bool) {
+	if v == nil {
+		return TypeInfo{}, false
+	}
+
+	return c.Get(reflect.TypeOf(v)), true
+}
+
+func holdsInterfaces(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Array:
+		return holdsInterfaces(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if holdsInterfaces(t.Field(i).Type) {
+				return true
+			}
+		}
+
+		return false
+	default:
+		return false
+	}
+}
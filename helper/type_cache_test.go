@@ -0,0 +1,86 @@
+package helper
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typeCacheTestKey struct {
+	id    int
+	value [1]interface{}
+}
+
+func TestTypeCache(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "nil",
+			test: func(t *testing.T) {
+				var c TypeCache
+
+				_, ok := c.Of(nil)
+				assert.False(t, ok)
+			},
+		},
+		{
+			desc: "metadata",
+			test: func(t *testing.T) {
+				var c TypeCache
+
+				info, ok := c.Of(&typeCacheTestKey{})
+				assert.True(t, ok)
+				assert.Equal(t, reflect.TypeOf(&typeCacheTestKey{}), info.Type)
+				assert.Equal(t, "*helper.typeCacheTestKey", info.Name)
+				assert.Equal(t, reflect.Pointer, info.Kind)
+				assert.True(t, info.Comparable)
+				assert.False(t, info.HoldsInterfaces)
+
+				info, _ = c.Of(typeCacheTestKey{})
+				assert.Equal(t, reflect.Struct, info.Kind)
+				assert.True(t, info.Comparable)
+				assert.True(t, info.HoldsInterfaces)
+
+				info, _ = c.Of([]int{})
+				assert.False(t, info.Comparable)
+				assert.False(t, info.HoldsInterfaces)
+			},
+		},
+		{
+			desc: "cached",
+			test: func(t *testing.T) {
+				var c TypeCache
+
+				info1 := c.Get(reflect.TypeOf(1))
+				info2 := c.Get(reflect.TypeOf(2))
+				assert.Equal(t, info1, info2)
+
+				count := 0
+				c.infos.Range(func(key, value interface{}) bool {
+					count++
+					return true
+				})
+
+				assert.Equal(t, 1, count)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func BenchmarkTryComparable(b *testing.B) {
+	key := typeCacheTestKey{id: 1, value: [1]interface{}{"a"}}
+
+	for i := 0; i < b.N; i++ {
+		TryComparable(key)
+	}
+}
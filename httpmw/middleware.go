@@ -0,0 +1,118 @@
+// Package httpmw provides a net/http middleware that sets up every package of this
+// library on the request context, replacing the middlewares each service would
+// otherwise write by hand.
+package httpmw
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/config"
+	"github.com/jamestrandung/go-context/ctxpool"
+	dvowhttpmw "github.com/jamestrandung/go-context/dvow/httpmw"
+	"github.com/jamestrandung/go-context/memoize"
+)
+
+type contextKey struct{}
+
+var backgroundKey = contextKey{}
+
+type poolKeyType struct{}
+
+var poolKey = poolKeyType{}
+
+// Config configures the middleware returned by New.
+type Config struct {
+	// ConcurrencyLevel is the number of shards of the memoize cache installed on every
+	// request. It defaults to the ConcurrencyLevel of config.Default if zero.
+	ConcurrencyLevel int
+	// DisableCache disables installing a memoize cache.
+	DisableCache bool
+	// Overwrites configures how overwritten variables are extracted from the request
+	// headers. They are not extracted if nil.
+	Overwrites *dvowhttpmw.Config
+	// Pool runs both the memoized functions of the memoize cache and the background
+	// tasks submitted via Go, if not nil.
+	Pool *ctxpool.Pool
+}
+
+// New returns a middleware that, for every request:
+//   - extracts overwritten variables from the headers as specified in cfg.Overwrites
+//     using the middleware of the dvow/httpmw package,
+//   - registers a background context, see Background,
+//   - installs a memoize cache that gets destroyed once the next handler returns.
+func New(cfg Config) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		handler := http.HandlerFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				ctx := r.Context()
+				ctx = context.WithValue(ctx, backgroundKey, cext.Detach(ctx))
+
+				if cfg.Pool != nil {
+					ctx = context.WithValue(ctx, poolKey, cfg.Pool)
+				}
+
+				if !cfg.DisableCache {
+					opts := config.Default().MemoizeOptions()
+					if cfg.Pool != nil {
+						opts = append(opts, memoize.WithPool(cfg.Pool))
+					}
+
+					var destroyFn memoize.DestroyFn
+					ctx, destroyFn = memoize.WithConcurrentCache(ctx, concurrencyLevel(cfg), opts...)
+					defer destroyFn()
+				}
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+			},
+		)
+
+		if cfg.Overwrites == nil {
+			return handler
+		}
+
+		return dvowhttpmw.New(*cfg.Overwrites)(handler)
+	}
+}
+
+// Background returns a context carrying the same values as the request context given
+// to the middleware returned by New, except for the memoize cache of the request, and
+// that never gets cancelled. It is meant for work that must outlive the request, e.g.
+// asynchronous writes, and still sees the overwritten variables of the request. If ctx
+// was not derived from such a request context, Background returns cext.Detach(ctx).
+func Background(ctx context.Context) context.Context {
+	if background, ok := ctx.Value(backgroundKey).(context.Context); ok {
+		return background
+	}
+
+	return cext.Detach(ctx)
+}
+
+// Go runs fn with the Background context of ctx on the Pool given to the middleware
+// returned by New, waiting for one of its goroutines to become available if needed.
+// If there's no such Pool, fn runs on a new goroutine. Go returns an error only if
+// fn could not be scheduled, see ctxpool.Pool.Submit.
+func Go(ctx context.Context, fn func(context.Context)) error {
+	background := Background(ctx)
+
+	pool, ok := ctx.Value(poolKey).(*ctxpool.Pool)
+	if !ok {
+		go fn(background)
+		return nil
+	}
+
+	return pool.Submit(ctx, func(context.Context) {
+		fn(background)
+	})
+}
+
+// concurrencyLevel returns the ConcurrencyLevel of the given Config, or that of
+// config.Default if it is zero.
+func concurrencyLevel(cfg Config) int {
+	if cfg.ConcurrencyLevel != 0 {
+		return cfg.ConcurrencyLevel
+	}
+
+	return config.Default().Memoize.ConcurrencyLevel
+}
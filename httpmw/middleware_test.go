@@ -0,0 +1,181 @@
+package httpmw
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jamestrandung/go-context/ctxpool"
+	"github.com/jamestrandung/go-context/dvow"
+	dvowhttpmw "github.com/jamestrandung/go-context/dvow/httpmw"
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/stretchr/testify/assert"
+)
+
+type middlewareTestKey struct{}
+
+func TestNew(t *testing.T) {
+	serve := func(cfg Config, header http.Header, handler http.HandlerFunc) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header = header
+
+		w := httptest.NewRecorder()
+		New(cfg)(handler).ServeHTTP(w, r)
+
+		return w
+	}
+
+	execute := func(ctx context.Context) memoize.Extra {
+		_, extra := memoize.Execute(ctx, middlewareTestKey{}, func(context.Context) (int, error) {
+			return 1, nil
+		})
+
+		return extra
+	}
+
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "cache is installed and destroyed",
+			test: func(t *testing.T) {
+				var requestCtx context.Context
+				serve(Config{}, http.Header{}, func(w http.ResponseWriter, r *http.Request) {
+					requestCtx = r.Context()
+					assert.True(t, execute(requestCtx).IsMemoized)
+				})
+
+				outcome, _ := memoize.Execute(requestCtx, middlewareTestKey{}, func(context.Context) (int, error) {
+					return 1, nil
+				})
+				assert.Equal(t, memoize.ErrCacheAlreadyDestroyed, outcome.Err)
+			},
+		},
+		{
+			desc: "cache is disabled",
+			test: func(t *testing.T) {
+				serve(Config{DisableCache: true}, http.Header{}, func(w http.ResponseWriter, r *http.Request) {
+					assert.False(t, execute(r.Context()).IsMemoized)
+				})
+			},
+		},
+		{
+			desc: "overwrites and background context",
+			test: func(t *testing.T) {
+				cfg := Config{
+					Overwrites: &dvowhttpmw.Config{},
+				}
+
+				header := http.Header{
+					dvowhttpmw.DefaultJSONHeader: []string{`{"enabled": true}`},
+				}
+
+				var background context.Context
+				serve(cfg, header, func(w http.ResponseWriter, r *http.Request) {
+					assert.True(t, dvow.GetOrDefaultAs(r.Context(), "enabled", false))
+
+					background = Background(r.Context())
+				})
+
+				assert.Nil(t, background.Err())
+				assert.Nil(t, background.Done())
+				assert.True(t, dvow.GetOrDefaultAs(background, "enabled", false))
+				assert.False(t, execute(background).IsMemoized, "the cache of the request must not leak")
+			},
+		},
+		{
+			desc: "invalid overwrites",
+			test: func(t *testing.T) {
+				cfg := Config{
+					Overwrites: &dvowhttpmw.Config{},
+				}
+
+				header := http.Header{
+					dvowhttpmw.DefaultJSONHeader: []string{`{`},
+				}
+
+				w := serve(cfg, header, func(w http.ResponseWriter, r *http.Request) {
+					assert.Fail(t, "next handler must not be called")
+				})
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestBackground(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middlewareTestKey{}, "value"))
+	cancel()
+
+	background := Background(ctx)
+	assert.Nil(t, background.Err())
+	assert.Equal(t, "value", background.Value(middlewareTestKey{}))
+}
+
+func TestGo(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without pool",
+			test: func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.WithValue(context.Background(), middlewareTestKey{}, "value"))
+				cancel()
+
+				done := make(chan interface{})
+				assert.Nil(t, Go(ctx, func(ctx context.Context) {
+					done <- ctx.Value(middlewareTestKey{})
+				}))
+
+				assert.Equal(t, "value", <-done)
+			},
+		},
+		{
+			desc: "with pool shared by the cache",
+			test: func(t *testing.T) {
+				pool := ctxpool.New(1)
+				defer pool.Close()
+
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+				done := make(chan memoize.Extra)
+				New(Config{Pool: pool})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					_, extra := memoize.Execute(r.Context(), middlewareTestKey{}, func(context.Context) (int, error) {
+						return 1, nil
+					})
+					assert.True(t, extra.IsMemoized)
+
+					assert.Nil(t, Go(r.Context(), func(ctx context.Context) {
+						_, extra := memoize.Execute(ctx, middlewareTestKey{}, func(context.Context) (int, error) {
+							return 1, nil
+						})
+
+						done <- extra
+					}))
+				})).ServeHTTP(httptest.NewRecorder(), r)
+
+				assert.False(t, (<-done).IsMemoized, "the cache of the request must not leak")
+
+				pool.Close()
+				assert.Equal(t, ctxpool.ErrPoolClosed, Go(context.WithValue(context.Background(), poolKey, pool), func(context.Context) {}))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
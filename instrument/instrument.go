@@ -0,0 +1,106 @@
+// Package instrument provides a single Instrumentation facade accepted by the packages
+// of this library, so that traces, metrics and logs are configured once instead of
+// through hooks specific to each package.
+package instrument
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Attr is a key-value pair describing a span, a measurement or a log.
+type Attr struct {
+	Key   string
+	Value interface{}
+}
+
+// Span is an operation started by Instrumentation.StartSpan.
+type Span interface {
+	// End completes this Span, recording err if it is not nil.
+	End(err error)
+}
+
+// Instrumentation receives the traces, metrics and logs of the packages of this library.
+type Instrumentation interface {
+	// StartSpan starts a Span with the given name as a child of the span active in ctx,
+	// if any, and returns a context holding the new Span.
+	StartSpan(ctx context.Context, name string, attrs ...Attr) (context.Context, Span)
+	// Count adds delta to the counter with the given name.
+	Count(ctx context.Context, name string, delta int64, attrs ...Attr)
+	// Record records value in the histogram with the given name.
+	Record(ctx context.Context, name string, value float64, attrs ...Attr)
+	// Log records a message.
+	Log(ctx context.Context, message string, attrs ...Attr)
+}
+
+// Noop is an Instrumentation that discards everything.
+type Noop struct{}
+
+// StartSpan returns ctx as-is with a Span that does nothing.
+func (Noop) StartSpan(ctx context.Context, name string, attrs ...Attr) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// Count does nothing.
+func (Noop) Count(ctx context.Context, name string, delta int64, attrs ...Attr) {
+	// do nothing
+}
+
+// Record does nothing.
+func (Noop) Record(ctx context.Context, name string, value float64, attrs ...Attr) {
+	// do nothing
+}
+
+// Log does nothing.
+func (Noop) Log(ctx context.Context, message string, attrs ...Attr) {
+	// do nothing
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(err error) {
+	// do nothing
+}
+
+type instrumentationHolder struct {
+	instrumentation Instrumentation
+}
+
+var defaultInstrumentation atomic.Value
+
+// Default returns the Instrumentation set by SetDefault, or Noop if it was never called.
+func Default() Instrumentation {
+	holder, _ := defaultInstrumentation.Load().(instrumentationHolder)
+	if holder.instrumentation == nil {
+		return Noop{}
+	}
+
+	return holder.instrumentation
+}
+
+// SetDefault sets the Instrumentation used by the packages of this library when none
+// was given explicitly. Passing nil restores Noop. It should be called once during the
+// initialization of a program, before caches and storages get created.
+func SetDefault(i Instrumentation) {
+	defaultInstrumentation.Store(instrumentationHolder{instrumentation: i})
+}
+
+// OrDefault returns i if it is not nil, otherwise Default().
+func OrDefault(i Instrumentation) Instrumentation {
+	if i == nil {
+		return Default()
+	}
+
+	return i
+}
+
+// IsNoop returns whether i discards everything, which lets callers skip preparing
+// attributes and measurements altogether.
+func IsNoop(i Instrumentation) bool {
+	if i == nil {
+		return true
+	}
+
+	_, ok := i.(Noop)
+	return ok
+}
@@ -0,0 +1,55 @@
+package instrument
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubInstrumentation is an Instrumentation that is not Noop.
+type stubInstrumentation struct {
+	Noop
+}
+
+func TestDefault(t *testing.T) {
+	defer SetDefault(nil)
+
+	assert.Equal(t, Noop{}, Default())
+	assert.True(t, IsNoop(Default()))
+
+	o := &stubInstrumentation{}
+
+	SetDefault(o)
+	assert.Equal(t, o, Default())
+	assert.False(t, IsNoop(Default()))
+
+	SetDefault(nil)
+	assert.Equal(t, Noop{}, Default())
+}
+
+func TestOrDefault(t *testing.T) {
+	defer SetDefault(nil)
+
+	o := &stubInstrumentation{}
+	SetDefault(o)
+
+	assert.Equal(t, o, OrDefault(nil))
+	assert.Equal(t, Noop{}, OrDefault(Noop{}))
+}
+
+func TestNoop(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NotPanics(t, func() {
+		spanCtx, span := Noop{}.StartSpan(ctx, "span", Attr{Key: "a", Value: 1})
+		assert.Equal(t, ctx, spanCtx)
+
+		span.End(errors.New("failed"))
+
+		Noop{}.Count(ctx, "counter", 1)
+		Noop{}.Record(ctx, "histogram", 1.5)
+		Noop{}.Log(ctx, "message")
+	})
+}
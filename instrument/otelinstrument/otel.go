@@ -0,0 +1,145 @@
+// Package otelinstrument provides an instrument.Instrumentation backed by OpenTelemetry,
+// kept apart from package instrument so that only programs using it depend on the
+// OpenTelemetry metric API.
+package otelinstrument
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jamestrandung/go-context/instrument"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type int64Adder interface {
+	Add(ctx context.Context, incr int64, attrs ...attribute.KeyValue)
+}
+
+type float64Recorder interface {
+	Record(ctx context.Context, incr float64, attrs ...attribute.KeyValue)
+}
+
+// Instrumentation is an instrument.Instrumentation backed by OpenTelemetry. Spans are
+// started using its Tracer, counters and histograms are created on demand using its
+// Meter, and logs are recorded as events of the span active in the given context.
+type Instrumentation struct {
+	tracer trace.Tracer
+	meter  metric.Meter
+
+	counters   sync.Map // map[string]int64Adder
+	histograms sync.Map // map[string]float64Recorder
+}
+
+// New returns an Instrumentation using the given Tracer and Meter. Either can be nil to
+// discard spans or measurements respectively.
+func New(tracer trace.Tracer, meter metric.Meter) *Instrumentation {
+	if tracer == nil {
+		tracer = trace.NewNoopTracerProvider().Tracer("")
+	}
+
+	if meter == nil {
+		meter = metric.NewNoopMeter()
+	}
+
+	return &Instrumentation{
+		tracer: tracer,
+		meter:  meter,
+	}
+}
+
+// StartSpan starts a span using the Tracer of this Instrumentation.
+func (o *Instrumentation) StartSpan(ctx context.Context, name string, attrs ...instrument.Attr) (context.Context, instrument.Span) {
+	ctx, span := o.tracer.Start(ctx, name, trace.WithAttributes(toKeyValues(attrs)...))
+	return ctx, otelSpan{span: span}
+}
+
+// Count adds delta to the Int64Counter with the given name.
+func (o *Instrumentation) Count(ctx context.Context, name string, delta int64, attrs ...instrument.Attr) {
+	if counter, ok := o.counters.Load(name); ok {
+		counter.(int64Adder).Add(ctx, delta, toKeyValues(attrs)...)
+		return
+	}
+
+	counter, err := o.meter.Int64Counter(name)
+	if err != nil {
+		return
+	}
+
+	actual, _ := o.counters.LoadOrStore(name, counter)
+	actual.(int64Adder).Add(ctx, delta, toKeyValues(attrs)...)
+}
+
+// Record records value in the Float64Histogram with the given name.
+func (o *Instrumentation) Record(ctx context.Context, name string, value float64, attrs ...instrument.Attr) {
+	if histogram, ok := o.histograms.Load(name); ok {
+		histogram.(float64Recorder).Record(ctx, value, toKeyValues(attrs)...)
+		return
+	}
+
+	histogram, err := o.meter.Float64Histogram(name)
+	if err != nil {
+		return
+	}
+
+	actual, _ := o.histograms.LoadOrStore(name, histogram)
+	actual.(float64Recorder).Record(ctx, value, toKeyValues(attrs)...)
+}
+
+// Log adds an event to the span active in ctx, if it is recording.
+func (o *Instrumentation) Log(ctx context.Context, message string, attrs ...instrument.Attr) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	span.AddEvent(message, trace.WithAttributes(toKeyValues(attrs)...))
+}
+
+type otelSpan struct {
+	span trace.Span
+}
+
+func (s otelSpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
+
+func toKeyValues(attrs []instrument.Attr) []attribute.KeyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		kvs = append(kvs, toKeyValue(attr))
+	}
+
+	return kvs
+}
+
+func toKeyValue(attr instrument.Attr) attribute.KeyValue {
+	switch v := attr.Value.(type) {
+	case string:
+		return attribute.String(attr.Key, v)
+	case bool:
+		return attribute.Bool(attr.Key, v)
+	case int:
+		return attribute.Int(attr.Key, v)
+	case int64:
+		return attribute.Int64(attr.Key, v)
+	case float64:
+		return attribute.Float64(attr.Key, v)
+	case fmt.Stringer:
+		return attribute.Stringer(attr.Key, v)
+	default:
+		return attribute.String(attr.Key, fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,180 @@
+package otelinstrument
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jamestrandung/go-context/instrument"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricinstrument "go.opentelemetry.io/otel/metric/instrument"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingSpan struct {
+	trace.Span
+	name       string
+	attrs      []attribute.KeyValue
+	events     []string
+	errs       []error
+	statusCode codes.Code
+	isEnded    bool
+}
+
+func (s *recordingSpan) IsRecording() bool {
+	return true
+}
+
+func (s *recordingSpan) AddEvent(name string, options ...trace.EventOption) {
+	s.events = append(s.events, name)
+}
+
+func (s *recordingSpan) RecordError(err error, options ...trace.EventOption) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.statusCode = code
+}
+
+func (s *recordingSpan) End(options ...trace.SpanEndOption) {
+	s.isEnded = true
+}
+
+type recordingTracer struct {
+	trace.Tracer
+	spans []*recordingSpan
+}
+
+func (t *recordingTracer) Start(
+	ctx context.Context,
+	spanName string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+
+	span := &recordingSpan{
+		Span:  trace.SpanFromContext(context.Background()),
+		name:  spanName,
+		attrs: cfg.Attributes(),
+	}
+
+	t.spans = append(t.spans, span)
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingCounter struct {
+	metricinstrument.Int64Counter
+	meter *recordingMeter
+	name  string
+}
+
+func (c recordingCounter) Add(ctx context.Context, incr int64, attrs ...attribute.KeyValue) {
+	c.meter.values[c.name] = append(c.meter.values[c.name], float64(incr))
+}
+
+type recordingHistogram struct {
+	metricinstrument.Float64Histogram
+	meter *recordingMeter
+	name  string
+}
+
+func (h recordingHistogram) Record(ctx context.Context, incr float64, attrs ...attribute.KeyValue) {
+	h.meter.values[h.name] = append(h.meter.values[h.name], incr)
+}
+
+type recordingMeter struct {
+	metric.Meter
+	values map[string][]float64
+}
+
+func (m *recordingMeter) Int64Counter(name string, options ...metricinstrument.Int64Option) (metricinstrument.Int64Counter, error) {
+	return recordingCounter{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Float64Histogram(name string, options ...metricinstrument.Float64Option) (metricinstrument.Float64Histogram, error) {
+	return recordingHistogram{meter: m, name: name}, nil
+}
+
+func TestInstrumentation(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "spans and logs",
+			test: func(t *testing.T) {
+				tracer := &recordingTracer{}
+				o := New(tracer, nil)
+
+				ctx, span := o.StartSpan(
+					context.Background(), "span",
+					instrument.Attr{Key: "string", Value: "a"},
+					instrument.Attr{Key: "bool", Value: true},
+					instrument.Attr{Key: "int", Value: 1},
+					instrument.Attr{Key: "duration", Value: time.Second},
+					instrument.Attr{Key: "other", Value: []int{1}},
+				)
+
+				o.Log(ctx, "message")
+				span.End(errors.New("failed"))
+
+				assert.Len(t, tracer.spans, 1)
+
+				recorded := tracer.spans[0]
+				assert.Equal(t, "span", recorded.name)
+				assert.Equal(
+					t, []attribute.KeyValue{
+						attribute.String("string", "a"),
+						attribute.Bool("bool", true),
+						attribute.Int("int", 1),
+						attribute.String("duration", "1s"),
+						attribute.String("other", "[1]"),
+					}, recorded.attrs,
+				)
+				assert.Equal(t, []string{"message"}, recorded.events)
+				assert.Equal(t, []error{errors.New("failed")}, recorded.errs)
+				assert.Equal(t, codes.Error, recorded.statusCode)
+				assert.True(t, recorded.isEnded)
+			},
+		},
+		{
+			desc: "logs without span",
+			test: func(t *testing.T) {
+				assert.NotPanics(t, func() {
+					New(nil, nil).Log(context.Background(), "message")
+				})
+			},
+		},
+		{
+			desc: "counters and histograms",
+			test: func(t *testing.T) {
+				meter := &recordingMeter{
+					values: make(map[string][]float64),
+				}
+
+				o := New(nil, meter)
+
+				ctx := context.Background()
+				o.Count(ctx, "counter", 1)
+				o.Count(ctx, "counter", 2, instrument.Attr{Key: "a", Value: 1})
+				o.Record(ctx, "histogram", 1.5)
+
+				assert.Equal(t, []float64{1, 2}, meter.values["counter"])
+				assert.Equal(t, []float64{1.5}, meter.values["histogram"])
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
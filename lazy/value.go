@@ -0,0 +1,53 @@
+// Package lazy provides values computed at most once per context tree, e.g. parsed auth
+// claims or tenant config, using the memoize cache of the tree under the hood.
+package lazy
+
+import (
+	"context"
+
+	"github.com/jamestrandung/go-context/memoize"
+)
+
+// valueKey is the memoize executionKey of a Value.
+type valueKey[K comparable] struct {
+	key K
+}
+
+// Value is a request-scoped singleton computed on first access.
+type Value[T any] struct {
+	get func(ctx context.Context) (T, error)
+}
+
+// New returns a Value identified by the given key whose content is computed using init
+// on first access. Values created with the same key share their content within a
+// context tree, so the key should not be of type string or any other built-in type to
+// avoid collisions between packages, similar to the keys of context.WithValue.
+func New[K comparable, T any](key K, init func(ctx context.Context) (T, error)) *Value[T] {
+	return &Value[T]{
+		get: func(ctx context.Context) (T, error) {
+			outcome, _ := memoize.Execute(ctx, valueKey[K]{key: key}, init)
+			return outcome.Value, outcome.Err
+		},
+	}
+}
+
+// Get returns the content of this Value, computing it if this is the first access
+// within the context tree of ctx. Concurrent callers wait for the same computation,
+// and its error, if any, is returned to all of them.
+//
+// Note: the content can only be computed once if the given context has been
+// initialized using memoize.WithCache. Otherwise, it is computed on every access.
+func (v *Value[T]) Get(ctx context.Context) (T, error) {
+	return v.get(ctx)
+}
+
+// GetOrDefault returns the content of this Value, or defaultValue if computing it
+// failed.
+func (v *Value[T]) GetOrDefault(ctx context.Context, defaultValue T) T {
+	value, err := v.get(ctx)
+	if err != nil {
+		return defaultValue
+	}
+
+	return value
+}
@@ -0,0 +1,111 @@
+package lazy
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/stretchr/testify/assert"
+)
+
+type claimsKey struct{}
+
+type tenantKey struct{}
+
+func TestValue_Get(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "computed once per context tree",
+			test: func(t *testing.T) {
+				var calls int32
+				claims := New(claimsKey{}, func(ctx context.Context) (string, error) {
+					atomic.AddInt32(&calls, 1)
+					return "claims", nil
+				})
+
+				ctx, destroyFn := memoize.WithCache(context.Background())
+				defer destroyFn()
+
+				var wg sync.WaitGroup
+				for i := 0; i < 10; i++ {
+					wg.Add(1)
+
+					go func() {
+						defer wg.Done()
+
+						child := context.WithValue(ctx, tenantKey{}, "tenant")
+
+						value, err := claims.Get(child)
+						assert.Equal(t, "claims", value)
+						assert.Nil(t, err)
+					}()
+				}
+
+				wg.Wait()
+
+				assert.Equal(t, int32(1), calls)
+
+				otherCtx, otherDestroyFn := memoize.WithCache(context.Background())
+				defer otherDestroyFn()
+
+				claims.Get(otherCtx)
+				assert.Equal(t, int32(2), calls)
+			},
+		},
+		{
+			desc: "values with the same key share their content",
+			test: func(t *testing.T) {
+				first := New(tenantKey{}, func(ctx context.Context) (int, error) {
+					return 1, nil
+				})
+
+				second := New(tenantKey{}, func(ctx context.Context) (int, error) {
+					return 2, nil
+				})
+
+				ctx, destroyFn := memoize.WithCache(context.Background())
+				defer destroyFn()
+
+				assert.Equal(t, 1, first.GetOrDefault(ctx, 0))
+				assert.Equal(t, 1, second.GetOrDefault(ctx, 0))
+			},
+		},
+		{
+			desc: "errors",
+			test: func(t *testing.T) {
+				v := New(claimsKey{}, func(ctx context.Context) (string, error) {
+					return "", assert.AnError
+				})
+
+				_, err := v.Get(context.Background())
+				assert.Equal(t, assert.AnError, err)
+				assert.Equal(t, "default", v.GetOrDefault(context.Background(), "default"))
+			},
+		},
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				calls := 0
+				v := New(claimsKey{}, func(ctx context.Context) (int, error) {
+					calls++
+					return calls, nil
+				})
+
+				assert.Equal(t, 1, v.GetOrDefault(context.Background(), 0))
+				assert.Equal(t, 2, v.GetOrDefault(context.Background(), 0))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package memoize
+
+import (
+	"context"
+)
+
+// Backend is a second-level store of outcomes shared across caches, e.g. across
+// requests, that a cache consults before executing a memoized function. Promises
+// still deduplicate concurrent executions within a cache on top of it.
+//
+// Note: a Backend is used concurrently and hence must be thread-safe.
+//
+//go:generate mockery --name Backend --case underscore --inpkg
+type Backend interface {
+	// Get returns the Outcome stored under the given executionKey and whether
+	// it was found.
+	Get(ctx context.Context, executionKey interface{}) (Outcome, bool)
+	// Set stores the given Outcome under the given executionKey. It is called
+	// only for outcomes without errors.
+	Set(ctx context.Context, executionKey interface{}, outcome Outcome)
+}
+
+// WithBackend makes the cache look up the outcome of a memoized function in the given
+// Backend before executing it, and store the outcome in this Backend after the function
+// succeeds.
+func WithBackend(backend Backend) Option {
+	return func(o *options) {
+		o.backend = backend
+	}
+}
+
+// backedFunction returns a Function looking up the outcome of the given Function in
+// the given Backend before calling it, and storing its outcome after it succeeds.
+func backedFunction(backend Backend, executionKey interface{}, function Function) Function {
+	if backend == nil {
+		return function
+	}
+
+	return func(ctx context.Context) (interface{}, error) {
+		if outcome, ok := backend.Get(ctx, executionKey); ok {
+			return outcome.Value, outcome.Err
+		}
+
+		value, err := function(ctx)
+		if err == nil {
+			backend.Set(
+				ctx, executionKey, Outcome{
+					Value: value,
+				},
+			)
+		}
+
+		return value, err
+	}
+}
@@ -0,0 +1,79 @@
+package memoize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestWithBackend(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "outcome found in backend",
+			test: func(t *testing.T) {
+				backend := &MockBackend{}
+				backend.On("Get", mock.Anything, "key").Return(Outcome{Value: 1}, true).Once()
+
+				ctx, destroyFn := WithCache(context.Background(), WithBackend(backend))
+				defer destroyFn()
+
+				for i := 0; i < 2; i++ {
+					outcome, _ := Execute(ctx, "key", func(context.Context) (int, error) {
+						assert.Fail(t, "memoizedFn must not be executed")
+						return 2, nil
+					})
+
+					assert.Equal(t, TypedOutcome[int]{Value: 1}, outcome)
+				}
+
+				backend.AssertExpectations(t)
+			},
+		},
+		{
+			desc: "successful outcome stored in backend",
+			test: func(t *testing.T) {
+				backend := &MockBackend{}
+				backend.On("Get", mock.Anything, "key").Return(Outcome{}, false).Once()
+				backend.On("Set", mock.Anything, "key", Outcome{Value: 2}).Return().Once()
+
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4, WithBackend(backend))
+				defer destroyFn()
+
+				outcome, _ := Execute(ctx, "key", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.Equal(t, TypedOutcome[int]{Value: 2}, outcome)
+				backend.AssertExpectations(t)
+			},
+		},
+		{
+			desc: "failed outcome not stored in backend",
+			test: func(t *testing.T) {
+				backend := &MockBackend{}
+				backend.On("Get", mock.Anything, "key").Return(Outcome{}, false).Once()
+
+				ctx, destroyFn := WithCache(context.Background(), WithBackend(backend))
+				defer destroyFn()
+
+				outcome, _ := Execute(ctx, "key", func(context.Context) (int, error) {
+					return 0, assert.AnError
+				})
+
+				assert.Equal(t, assert.AnError, outcome.Err)
+				backend.AssertExpectations(t)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
@@ -23,12 +23,40 @@ type iCache interface {
 		ctx context.Context,
 		executionKey interface{},
 		memoizedFn Function,
+		opts ...executeOption,
 	) (Outcome, Extra)
+	// prefetch starts executing the given memoizedFn in the background unless
+	// a promise already exists for the given executionKey.
+	prefetch(
+		ctx context.Context,
+		executionKey interface{},
+		memoizedFn Function,
+		opts ...executeOption,
+	)
+	// peek returns the promise under the given executionKey without creating
+	// it, nil if there is none.
+	peek(executionKey interface{}) *promise
 	// findPromises returns all promise that were memoized under the given
 	// executionKey type at the time findPromises was called.
 	//
 	// Note: if executionKey is nil, all promises will be returned.
 	findPromises(executionKey interface{}) map[interface{}]*promise
+	// countPromises returns the number of promises that are still pending and
+	// the number of those that have completed, without blocking executions.
+	countPromises() (pending int, completed int)
+	// invalidate removes the promise memoized under the given executionKey
+	// so that the next call to execute invokes its memoizedFn again.
+	invalidate(executionKey interface{})
+	// invalidateByKeyType removes all promises memoized under executionKeys
+	// of the given type.
+	invalidateByKeyType(executionKeyType string)
+	// invalidateNamespace removes all promises registered in the given
+	// namespace.
+	invalidateNamespace(namespace string)
+	// stats returns how this cache has been used since it was created.
+	stats() CacheStats
+	// shardStats returns the statistics of each shard of this cache.
+	shardStats() []ShardStats
 }
 
 type noMemoizeCache struct {
@@ -47,6 +75,7 @@ func (c *noMemoizeCache) execute(
 	ctx context.Context,
 	executionKey interface{},
 	memoizedFn Function,
+	opts ...executeOption,
 ) (Outcome, Extra) {
 	if atomic.LoadInt64(&c.isDestroyed) == 1 {
 		return Outcome{
@@ -68,16 +97,39 @@ func (c *noMemoizeCache) execute(
 			}
 	}
 
-	result, err := doExecute(ctx, memoizedFn)
+	startedAt := timeNow().UnixNano()
+	result, err := doExecute(ctx, memoizedFn, nil)
 	return Outcome{
 			Value: result,
 			Err:   err,
-		}, Extra{
-			IsMemoized: false,
-			IsExecuted: true,
-		}
+		}, newExtra(false, true, startedAt, timeNow().UnixNano())
+}
+
+func (c *noMemoizeCache) prefetch(
+	ctx context.Context,
+	executionKey interface{},
+	memoizedFn Function,
+	opts ...executeOption,
+) {
+	// do nothing since outcomes would not be memoized
+}
+
+func (c *noMemoizeCache) peek(executionKey interface{}) *promise {
+	return nil
 }
 
 func (c *noMemoizeCache) findPromises(executionKey interface{}) map[interface{}]*promise {
 	return nil
 }
+
+func (c *noMemoizeCache) countPromises() (pending int, completed int) {
+	return 0, 0
+}
+
+func (c *noMemoizeCache) invalidate(executionKey interface{}) {
+	// do nothing
+}
+
+func (c *noMemoizeCache) invalidateByKeyType(executionKeyType string) {
+	// do nothing
+}
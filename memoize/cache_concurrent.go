@@ -2,20 +2,49 @@ package memoize
 
 import (
 	"context"
-	"github.com/mitchellh/hashstructure/v2"
+	"runtime"
 	"sync"
+
+	"github.com/jamestrandung/go-context/helper"
+)
+
+const (
+	defaultConcurrencyLevel = 10
+	// maxConcurrencyLevel is the number of shards above which more shards would
+	// cost more memory than they save in contention.
+	maxConcurrencyLevel = 1024
+	// shardsPerProc is the number of shards per CPU created for AutoShards.
+	shardsPerProc = 4
 )
 
-const defaultConcurrencyLevel = 10
+// AutoShards can be given to WithConcurrentCache as concurrencyLevel to size the
+// shards of the cache according to runtime.GOMAXPROCS.
+const AutoShards = -1
 
 type concurrentCache []*cache
 
-// newConcurrentCache creates a new concurrentCache.
-func newConcurrentCache(rootCtx context.Context, concurrencyLevel int) concurrentCache {
-	if concurrencyLevel == 0 {
+// normalizeConcurrencyLevel resolves AutoShards and clamps the given concurrency
+// level to a valid number of shards. Zero and negative values other than AutoShards
+// fall back to defaultConcurrencyLevel.
+func normalizeConcurrencyLevel(concurrencyLevel int) int {
+	switch {
+	case concurrencyLevel == AutoShards:
+		concurrencyLevel = runtime.GOMAXPROCS(0) * shardsPerProc
+	case concurrencyLevel <= 0:
 		concurrencyLevel = defaultConcurrencyLevel
 	}
 
+	if concurrencyLevel > maxConcurrencyLevel {
+		return maxConcurrencyLevel
+	}
+
+	return concurrencyLevel
+}
+
+// newConcurrentCache creates a new concurrentCache.
+func newConcurrentCache(rootCtx context.Context, concurrencyLevel int) concurrentCache {
+	concurrencyLevel = normalizeConcurrencyLevel(concurrencyLevel)
+
 	shards := make([]*cache, concurrencyLevel)
 
 	for i := 0; i < concurrencyLevel; i++ {
@@ -30,6 +59,11 @@ func (c concurrentCache) getShard(executionKey interface{}) *cache {
 }
 
 func (c concurrentCache) hashIndex(executionKey interface{}) uint64 {
+	// All shards share the same hasher
+	if hasher := c[0].hasher; hasher != nil {
+		return hasher(executionKey) % uint64(len(c))
+	}
+
 	return hashAny(executionKey) % uint64(len(c))
 }
 
@@ -81,9 +115,25 @@ func (c concurrentCache) execute(
 	ctx context.Context,
 	executionKey interface{},
 	memoizedFn Function,
+	opts ...executeOption,
 ) (Outcome, Extra) {
 	shard := c.getShard(executionKey)
-	return shard.execute(ctx, executionKey, memoizedFn)
+	return shard.execute(ctx, executionKey, memoizedFn, opts...)
+}
+
+func (c concurrentCache) prefetch(
+	ctx context.Context,
+	executionKey interface{},
+	memoizedFn Function,
+	opts ...executeOption,
+) {
+	shard := c.getShard(executionKey)
+	shard.prefetch(ctx, executionKey, memoizedFn, opts...)
+}
+
+func (c concurrentCache) peek(executionKey interface{}) *promise {
+	shard := c.getShard(executionKey)
+	return shard.peek(executionKey)
 }
 
 func (c concurrentCache) findPromises(executionKey interface{}) map[interface{}]*promise {
@@ -100,15 +150,50 @@ func (c concurrentCache) findPromises(executionKey interface{}) map[interface{}]
 	return m
 }
 
-var hashFn = hashstructure.Hash
+func (c concurrentCache) countPromises() (pending int, completed int) {
+	for _, shard := range c {
+		shardPending, shardCompleted := shard.countPromises()
 
-func hashAny(key interface{}) uint64 {
-	defer func() {
-		// Fall back to 0 if panics
-		recover()
-	}()
+		pending += shardPending
+		completed += shardCompleted
+	}
+
+	return pending, completed
+}
 
-	hash, err := hashFn(key, hashstructure.FormatV2, &hashstructure.HashOptions{UseStringer: true})
+func (c concurrentCache) invalidate(executionKey interface{}) {
+	if !helper.TryComparable(executionKey) {
+		return
+	}
+
+	// All shards share the same dependencies
+	c.invalidateKeys(c[0].dependencies.cascade(executionKey))
+}
+
+func (c concurrentCache) invalidateByKeyType(executionKeyType string) {
+	for _, shard := range c {
+		shard.invalidateKeysOfType(executionKeyType)
+	}
+
+	c.invalidateKeys(c[0].dependencies.cascadeByKeyType(executionKeyType))
+}
+
+// invalidateKeys removes the promises memoized under the given executionKeys from
+// their respective shards without cascading to their dependents.
+func (c concurrentCache) invalidateKeys(executionKeys []interface{}) {
+	shardKeys := make(map[*cache][]interface{})
+	for _, executionKey := range executionKeys {
+		shard := c.getShard(executionKey)
+		shardKeys[shard] = append(shardKeys[shard], executionKey)
+	}
+
+	for shard, keys := range shardKeys {
+		shard.invalidateKeys(keys)
+	}
+}
+
+func hashAny(key interface{}) uint64 {
+	hash, err := helper.Hash(key)
 	if err != nil {
 		// Use the 1st shard as fallback in case hashing fails
 		return 0
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -293,3 +294,34 @@ func TestConcurrentCache_FindPromises(t *testing.T) {
 	promises = c.findPromises("key")
 	assert.Equal(t, 0, len(promises), "no promises should come from a destroyed cache")
 }
+
+func TestNormalizeConcurrencyLevel(t *testing.T) {
+	assert.Equal(t, 1, normalizeConcurrencyLevel(1))
+	assert.Equal(t, 16, normalizeConcurrencyLevel(16))
+	assert.Equal(t, defaultConcurrencyLevel, normalizeConcurrencyLevel(0))
+	assert.Equal(t, defaultConcurrencyLevel, normalizeConcurrencyLevel(-5))
+	assert.Equal(t, maxConcurrencyLevel, normalizeConcurrencyLevel(1<<30))
+	assert.Equal(t, runtime.GOMAXPROCS(0)*shardsPerProc, normalizeConcurrencyLevel(AutoShards))
+}
+
+func TestConcurrentCache_ShardStats(t *testing.T) {
+	c := newConcurrentCache(context.Background(), 4)
+
+	c.take(
+		map[interface{}]Outcome{
+			"a": {Value: 1},
+			"b": {Value: 2},
+		},
+	)
+
+	stats := c.shardStats()
+	assert.Len(t, stats, 4)
+
+	promises := 0
+	for _, s := range stats {
+		promises += s.Promises
+		assert.GreaterOrEqual(t, s.LockAcquisitions, s.LockContentions)
+	}
+
+	assert.Equal(t, 2, promises)
+}
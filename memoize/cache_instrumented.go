@@ -0,0 +1,55 @@
+package memoize
+
+import (
+	"context"
+	"time"
+
+	"github.com/jamestrandung/go-context/helper"
+	"github.com/jamestrandung/go-context/instrument"
+)
+
+// Names of the spans & metrics reported by instrumentedCache.
+const (
+	ExecuteSpanName             = "memoize.Execute"
+	ExecutionsMetricName        = "memoize.executions"
+	ExecutionDurationMetricName = "memoize.execution.duration"
+)
+
+// Attributes of the spans & metrics reported by instrumentedCache.
+const (
+	KeyTypeAttr    = "memoize.key_type"
+	IsMemoizedAttr = "memoize.is_memoized"
+	IsExecutedAttr = "memoize.is_executed"
+)
+
+// instrumentedCache is an iCache reporting executions to an Instrumentation.
+type instrumentedCache struct {
+	iCache
+	instrumentation instrument.Instrumentation
+}
+
+func (c instrumentedCache) execute(
+	ctx context.Context,
+	executionKey interface{},
+	memoizedFn Function,
+	opts ...executeOption,
+) (Outcome, Extra) {
+	keyType := instrument.Attr{Key: KeyTypeAttr, Value: helper.NameOf(executionKey)}
+
+	ctx, span := c.instrumentation.StartSpan(ctx, ExecuteSpanName, keyType)
+	startTime := time.Now()
+
+	outcome, extra := c.iCache.execute(ctx, executionKey, memoizedFn, opts...)
+
+	attrs := []instrument.Attr{
+		keyType,
+		{Key: IsMemoizedAttr, Value: extra.IsMemoized},
+		{Key: IsExecutedAttr, Value: extra.IsExecuted},
+	}
+
+	c.instrumentation.Count(ctx, ExecutionsMetricName, 1, attrs...)
+	c.instrumentation.Record(ctx, ExecutionDurationMetricName, time.Since(startTime).Seconds(), attrs...)
+	span.End(outcome.Err)
+
+	return outcome, extra
+}
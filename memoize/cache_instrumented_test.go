@@ -0,0 +1,153 @@
+package memoize
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/jamestrandung/go-context/instrument"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingInstrumentation struct {
+	instrument.Noop
+	mu       sync.Mutex
+	spans    []string
+	errs     []error
+	counts   map[string][]instrument.Attr
+	recorded []string
+}
+
+type recordingSpan struct {
+	i *recordingInstrumentation
+}
+
+func (s recordingSpan) End(err error) {
+	s.i.mu.Lock()
+	defer s.i.mu.Unlock()
+
+	s.i.errs = append(s.i.errs, err)
+}
+
+func (i *recordingInstrumentation) StartSpan(
+	ctx context.Context,
+	name string,
+	attrs ...instrument.Attr,
+) (context.Context, instrument.Span) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.spans = append(i.spans, name)
+
+	return ctx, recordingSpan{i: i}
+}
+
+func (i *recordingInstrumentation) Count(ctx context.Context, name string, delta int64, attrs ...instrument.Attr) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.counts == nil {
+		i.counts = make(map[string][]instrument.Attr)
+	}
+
+	i.counts[name] = append(i.counts[name], attrs...)
+}
+
+func (i *recordingInstrumentation) Record(ctx context.Context, name string, value float64, attrs ...instrument.Attr) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.recorded = append(i.recorded, name)
+}
+
+type instrumentedTestKey struct{}
+
+func TestInstrumentedCache_Execute(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "explicit instrumentation",
+			test: func(t *testing.T) {
+				i := &recordingInstrumentation{}
+
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4, WithInstrumentation(i))
+				defer destroyFn()
+
+				for n := 0; n < 2; n++ {
+					outcome, extra := Execute(ctx, instrumentedTestKey{}, func(context.Context) (int, error) {
+						return 1, assert.AnError
+					})
+
+					assert.Equal(t, 1, outcome.Value)
+					assert.True(t, extra.IsMemoized)
+				}
+
+				assert.Equal(t, []string{ExecuteSpanName, ExecuteSpanName}, i.spans)
+				assert.Equal(t, []error{assert.AnError, assert.AnError}, i.errs)
+				assert.Equal(t, []string{ExecutionDurationMetricName, ExecutionDurationMetricName}, i.recorded)
+				assert.Equal(
+					t, []instrument.Attr{
+						{Key: KeyTypeAttr, Value: "memoize.instrumentedTestKey"},
+						{Key: IsMemoizedAttr, Value: true},
+						{Key: IsExecutedAttr, Value: true},
+						{Key: KeyTypeAttr, Value: "memoize.instrumentedTestKey"},
+						{Key: IsMemoizedAttr, Value: true},
+						{Key: IsExecutedAttr, Value: true},
+					}, i.counts[ExecutionsMetricName],
+				)
+			},
+		},
+		{
+			desc: "default instrumentation",
+			test: func(t *testing.T) {
+				i := &recordingInstrumentation{}
+
+				instrument.SetDefault(i)
+				defer instrument.SetDefault(nil)
+
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				Execute(ctx, instrumentedTestKey{}, func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, []string{ExecuteSpanName}, i.spans)
+				assert.Equal(t, []error{nil}, i.errs)
+			},
+		},
+		{
+			desc: "no instrumentation",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background(), WithInstrumentation(instrument.Noop{}))
+				defer destroyFn()
+
+				assert.IsType(t, &cache{}, extractCache(ctx))
+			},
+		},
+		{
+			desc: "destroyed cache",
+			test: func(t *testing.T) {
+				i := &recordingInstrumentation{}
+
+				ctx, destroyFn := WithCache(context.Background(), WithInstrumentation(i))
+				destroyFn()
+
+				outcome, _ := Execute(ctx, instrumentedTestKey{}, func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, ErrCacheAlreadyDestroyed, outcome.Err)
+				assert.Equal(t, []error{ErrCacheAlreadyDestroyed}, i.errs)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
@@ -0,0 +1,85 @@
+package memoize
+
+import (
+	"container/list"
+)
+
+// lruEntry is an element of the recency list of a cache.
+type lruEntry struct {
+	executionKey interface{}
+	promise      *promise
+}
+
+// store puts the given promise into this cache under the given executionKey and
+// evicts the least recently used completed promises if this cache holds more than
+// maxEntries promises. The caller must hold c.promisesMu.
+func (c *cache) store(executionKey interface{}, p *promise) {
+	if c.promises == nil {
+		c.promises = make(map[interface{}]*promise)
+	}
+
+	if old, ok := c.promises[executionKey]; ok {
+		c.untrack(old)
+	}
+
+	c.promises[executionKey] = p
+
+	if c.maxEntries <= 0 {
+		return
+	}
+
+	if c.recency == nil {
+		c.recency = list.New()
+	}
+
+	p.element = c.recency.PushFront(
+		&lruEntry{
+			executionKey: executionKey,
+			promise:      p,
+		},
+	)
+
+	c.evict()
+}
+
+// remove removes the promise under the given executionKey from this cache. The
+// caller must hold c.promisesMu.
+func (c *cache) remove(executionKey interface{}) {
+	if p, ok := c.promises[executionKey]; ok {
+		c.untrack(p)
+		delete(c.promises, executionKey)
+	}
+}
+
+// touch marks the given promise as the most recently used one. The caller must
+// hold c.promisesMu.
+func (c *cache) touch(p *promise) {
+	if p.element != nil {
+		c.recency.MoveToFront(p.element)
+	}
+}
+
+// untrack removes the given promise from the recency list. The caller must hold
+// c.promisesMu.
+func (c *cache) untrack(p *promise) {
+	if p.element != nil {
+		c.recency.Remove(p.element)
+		p.element = nil
+	}
+}
+
+// evict removes the least recently used completed promises until this cache holds
+// at most maxEntries promises. Pending promises are never evicted since callers
+// are still waiting for them. The caller must hold c.promisesMu.
+func (c *cache) evict() {
+	for e := c.recency.Back(); e != nil && len(c.promises) > c.maxEntries; {
+		prev := e.Prev()
+
+		entry := e.Value.(*lruEntry)
+		if entry.promise.isDone() {
+			c.remove(entry.executionKey)
+		}
+
+		e = prev
+	}
+}
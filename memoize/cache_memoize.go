@@ -1,12 +1,16 @@
 package memoize
 
 import (
+	"container/list"
 	"context"
 	"fmt"
-	"reflect"
 	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/ctxpool"
 	"github.com/jamestrandung/go-context/helper"
 	"github.com/pkg/errors"
 )
@@ -15,8 +19,42 @@ import (
 type cache struct {
 	rootCtx     context.Context
 	isDestroyed bool
-	promisesMu  sync.Mutex
+	promisesMu  sync.RWMutex
 	promises    map[interface{}]*promise
+	// pool runs memoized functions if not nil.
+	pool *ctxpool.Pool
+	// slots is the semaphore bounding the number of memoized functions running at
+	// the same time, shared by all shards of a cache, nil if unbounded.
+	slots chan struct{}
+	// isRetryable reports errors that should not be memoized, if not nil.
+	isRetryable func(error) bool
+	// panics determines what happens when a memoized function panics.
+	panics panicPolicy
+	// backend is consulted before executing memoized functions, if not nil.
+	backend Backend
+	// hasher assigns executionKeys to the shards of the concurrentCache this
+	// cache belongs to, if not nil.
+	hasher func(executionKey interface{}) uint64
+	// dependencies records which executionKeys depend on which other ones, shared
+	// by all shards of a cache, nil if not tracked.
+	dependencies *dependencyGraph
+	// stopReaper is closed to stop the reaper of expired promises, nil if it
+	// was never started.
+	stopReaper chan struct{}
+	// defaultTTL is the TTL of promises created without one, forever if zero.
+	defaultTTL time.Duration
+	// maxEntries is the number of promises above which the least recently used
+	// completed ones get evicted, unlimited if not positive.
+	maxEntries int
+	// recency orders promises from the most to the least recently used one, nil
+	// unless maxEntries is positive.
+	recency *list.List
+	// lockAcquisitions & lockContentions count how often promisesMu was acquired
+	// and how often it was already held by another goroutine at the time.
+	lockAcquisitions int64
+	lockContentions  int64
+	// statsRecorder counts hits, misses & panics of this cache.
+	statsRecorder statsRecorder
 }
 
 // newCache creates a new cache.
@@ -27,33 +65,56 @@ func newCache(rootCtx context.Context) *cache {
 	}
 }
 
+// lock acquires promisesMu while counting contentions.
+func (c *cache) lock() {
+	atomic.AddInt64(&c.lockAcquisitions, 1)
+
+	if !c.promisesMu.TryLock() {
+		atomic.AddInt64(&c.lockContentions, 1)
+		c.promisesMu.Lock()
+	}
+}
+
+// rlock acquires the read lock of promisesMu while counting contentions.
+func (c *cache) rlock() {
+	atomic.AddInt64(&c.lockAcquisitions, 1)
+
+	if !c.promisesMu.TryRLock() {
+		atomic.AddInt64(&c.lockContentions, 1)
+		c.promisesMu.RLock()
+	}
+}
+
 func (c *cache) destroy() {
-	c.promisesMu.Lock()
+	c.lock()
 	defer c.promisesMu.Unlock()
 
 	c.isDestroyed = true
 	c.promises = nil
+	c.recency = nil
+	c.dependencies.clear()
+
+	if c.stopReaper != nil {
+		close(c.stopReaper)
+		c.stopReaper = nil
+	}
 }
 
 func (c *cache) take(entries map[interface{}]Outcome) {
-	c.promisesMu.Lock()
+	c.lock()
 	defer c.promisesMu.Unlock()
 
 	if c.isDestroyed {
 		return
 	}
 
-	if c.promises == nil {
-		c.promises = make(map[interface{}]*promise)
-	}
-
 	for executionKey, outcome := range entries {
-		if executionKey == nil {
+		if !helper.TryComparable(executionKey) {
 			continue
 		}
 
 		p := completedPromise(c.extractExecutionKeyType(executionKey), outcome)
-		c.promises[executionKey] = p
+		c.store(executionKey, p)
 	}
 }
 
@@ -61,6 +122,7 @@ func (c *cache) execute(
 	ctx context.Context,
 	executionKey interface{},
 	memoizedFn Function,
+	opts ...executeOption,
 ) (Outcome, Extra) {
 	if memoizedFn == nil {
 		return Outcome{
@@ -72,18 +134,23 @@ func (c *cache) execute(
 			}
 	}
 
-	if !helper.IsComparable(executionKey) {
-		result, err := doExecute(ctx, memoizedFn)
+	if !helper.TryComparable(executionKey) {
+		var recovered *recoveredPanic
+		startedAt := timeNow().UnixNano()
+		result, err := doExecute(ctx, memoizedFn, c.panics.onPanic(executionKey, &recovered))
+		if recovered != nil && c.panics.propagate {
+			panic(recovered.value)
+		}
+
 		return Outcome{
 				Value: result,
 				Err:   err,
-			}, Extra{
-				IsMemoized: false,
-				IsExecuted: true,
-			}
+			}, newExtra(false, true, startedAt, timeNow().UnixNano())
 	}
 
-	p, err := c.promise(executionKey, memoizedFn)
+	opts = withNamespaceOf(ctx, opts)
+
+	p, err := c.promise(executionKey, memoizedFn, opts...)
 	if err != nil {
 		return Outcome{
 				Value: nil,
@@ -94,16 +161,96 @@ func (c *cache) execute(
 			}
 	}
 
-	return p.get(ctx), Extra{
-		IsMemoized: true,
-		IsExecuted: p.isExecuted(),
+	o := newExecuteOptions(opts...)
+	c.dependencies.add(executionKey, o.dependencies)
+
+	if o.maxAge > 0 && p.isOlderThan(o.maxAge) {
+		c.refresh(ctx, executionKey, p, memoizedFn, o)
+	}
+
+	get := p.get
+	if o.noWait {
+		get = p.tryGet
+	}
+
+	outcome := get(ctx)
+	if !p.isDone() {
+		// The caller stopped or refused to wait, the outcome carries the error
+		return outcome, newExtra(true, p.isExecuted(), atomic.LoadInt64(&p.startedAt), 0)
+	}
+
+	return outcome, p.extra()
+}
+
+// peekPromise returns the valid promise under the given executionKey while only
+// holding the read lock, so that repeated hits on the same keys don't serialize.
+// It always misses if promises are evicted since hits must then update their
+// recency, which requires the write lock.
+func (c *cache) peekPromise(executionKey interface{}) (*promise, bool) {
+	if c.maxEntries > 0 {
+		return nil, false
+	}
+
+	p := c.findPromise(executionKey)
+	return p, p != nil
+}
+
+// findPromise returns the valid promise under the given executionKey, nil if
+// there is none, while only holding the read lock.
+func (c *cache) findPromise(executionKey interface{}) *promise {
+	c.rlock()
+	defer c.promisesMu.RUnlock()
+
+	if c.isDestroyed {
+		return nil
+	}
+
+	p, ok := c.promises[executionKey]
+	if !ok || p.isExpired() {
+		return nil
+	}
+
+	return p
+}
+
+func (c *cache) peek(executionKey interface{}) *promise {
+	if !helper.TryComparable(executionKey) {
+		return nil
+	}
+
+	return c.findPromise(executionKey)
+}
+
+func (c *cache) prefetch(
+	ctx context.Context,
+	executionKey interface{},
+	memoizedFn Function,
+	opts ...executeOption,
+) {
+	if memoizedFn == nil || !helper.TryComparable(executionKey) {
+		return
+	}
+
+	opts = withNamespaceOf(ctx, opts)
+
+	p, err := c.promise(executionKey, memoizedFn, opts...)
+	if err != nil || State(atomic.LoadInt32(&p.state)) != IsCreated {
+		// The promise is already running or completed
+		return
 	}
+
+	go p.getInBackground(ctx)
 }
 
 // promise returns a promise for the future result of calling the given function.
-// Calls to promise with the same key return the same promise.
-func (c *cache) promise(executionKey interface{}, function Function) (*promise, error) {
-	c.promisesMu.Lock()
+// Calls to promise with the same key return the same promise until it expires.
+func (c *cache) promise(executionKey interface{}, function Function, opts ...executeOption) (*promise, error) {
+	if p, ok := c.peekPromise(executionKey); ok {
+		c.statsRecorder.recordHit(p.executionKeyType, p.isPopulated())
+		return p, nil
+	}
+
+	c.lock()
 	defer c.promisesMu.Unlock()
 
 	if c.isDestroyed {
@@ -111,31 +258,135 @@ func (c *cache) promise(executionKey interface{}, function Function) (*promise,
 	}
 
 	p, ok := c.promises[executionKey]
-	if !ok {
-		return c.createPromise(executionKey, function), nil
+	if !ok || p.isExpired() {
+		p = c.createPromise(executionKey, function, newExecuteOptions(opts...))
+		c.statsRecorder.recordMiss(p.executionKeyType)
+
+		return p, nil
 	}
 
+	c.touch(p)
+	c.statsRecorder.recordHit(p.executionKeyType, p.isPopulated())
+
 	return p, nil
 }
 
-func (c *cache) createPromise(executionKey interface{}, function Function) *promise {
-	p := newPromise(c.extractExecutionKeyType(executionKey), c.rootCtx, function)
-	if c.promises == nil {
-		c.promises = make(map[interface{}]*promise)
+func (c *cache) createPromise(executionKey interface{}, function Function, o executeOptions) *promise {
+	p := c.newPromise(executionKey, function, o)
+
+	c.store(executionKey, p)
+
+	if p.ttl > 0 && c.stopReaper == nil {
+		c.startReaper()
 	}
 
-	c.promises[executionKey] = p
+	return p
+}
+
+// refresh re-executes the given function in the background and replaces the given
+// stale promise with the new one once it succeeds, unless another refresh of this
+// promise is already running.
+func (c *cache) refresh(ctx context.Context, executionKey interface{}, stale *promise, function Function, o executeOptions) {
+	if !atomic.CompareAndSwapInt32(&stale.isRefreshing, 0, 1) {
+		return
+	}
+
+	fresh := c.newPromise(executionKey, function, o)
+
+	go func() {
+		if outcome := fresh.getInBackground(cext.Detach(ctx)); outcome.Err != nil {
+			// Keep serving the stale outcome, the next call will try again
+			atomic.StoreInt32(&stale.isRefreshing, 0)
+			return
+		}
+
+		c.lock()
+		defer c.promisesMu.Unlock()
+
+		if c.isDestroyed || c.promises[executionKey] != stale {
+			return
+		}
+
+		c.store(executionKey, fresh)
+
+		if fresh.ttl > 0 && c.stopReaper == nil {
+			c.startReaper()
+		}
+	}()
+}
+
+// newPromise returns a promise for the given function configured according to
+// this cache and the given executeOptions.
+func (c *cache) newPromise(executionKey interface{}, function Function, o executeOptions) *promise {
+	p := newPromise(c.extractExecutionKeyType(executionKey), c.rootCtx, backedFunction(c.backend, executionKey, function))
+	p.pool = c.pool
+	p.slots = c.slots
+	p.ttl = o.ttl
+	if p.ttl == 0 {
+		p.ttl = c.defaultTTL
+	}
+	p.timeout = o.timeout
+	p.namespace = o.namespace
+	p.isRetryable = c.isRetryable
+	p.executionKey = executionKey
+	p.panics = c.panics
+	p.stats = &c.statsRecorder
 
 	return p
 }
 
+// reapInterval is how often the reaper of a cache removes expired promises.
+var reapInterval = time.Minute
+
+// startReaper starts a goroutine removing expired promises from this cache
+// until it is destroyed or its root context is cancelled. The caller must
+// hold c.promisesMu.
+func (c *cache) startReaper() {
+	stop := make(chan struct{})
+	c.stopReaper = stop
+
+	var rootDone <-chan struct{}
+	if c.rootCtx != nil {
+		rootDone = c.rootCtx.Done()
+	}
+
+	ticker := time.NewTicker(reapInterval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-rootDone:
+				return
+			case <-ticker.C:
+				c.reap()
+			}
+		}
+	}()
+}
+
+// reap removes expired promises from this cache.
+func (c *cache) reap() {
+	c.lock()
+	defer c.promisesMu.Unlock()
+
+	for executionKey, p := range c.promises {
+		if p.isExpired() {
+			c.remove(executionKey)
+		}
+	}
+}
+
 func (c *cache) findPromises(executionKey interface{}) map[interface{}]*promise {
 	returnAll := false
 	if executionKey == nil {
 		returnAll = true
 	}
 
-	c.promisesMu.Lock()
+	c.lock()
 	defer c.promisesMu.Unlock()
 
 	if c.isDestroyed {
@@ -156,32 +407,99 @@ func (c *cache) findPromises(executionKey interface{}) map[interface{}]*promise
 			continue
 		}
 
+		if p.isExpired() {
+			continue
+		}
+
 		m[key] = p
 	}
 
 	return m
 }
 
+func (c *cache) countPromises() (pending int, completed int) {
+	c.rlock()
+	defer c.promisesMu.RUnlock()
+
+	if c.isDestroyed {
+		return 0, 0
+	}
+
+	for _, p := range c.promises {
+		if p.isExpired() {
+			continue
+		}
+
+		if p.isDone() {
+			completed++
+		} else {
+			pending++
+		}
+	}
+
+	return pending, completed
+}
+
+func (c *cache) invalidate(executionKey interface{}) {
+	if !helper.TryComparable(executionKey) {
+		return
+	}
+
+	c.invalidateKeys(c.dependencies.cascade(executionKey))
+}
+
+func (c *cache) invalidateByKeyType(executionKeyType string) {
+	c.invalidateKeysOfType(executionKeyType)
+	c.invalidateKeys(c.dependencies.cascadeByKeyType(executionKeyType))
+}
+
+// invalidateKeys removes the promises memoized under the given executionKeys
+// without cascading to their dependents.
+func (c *cache) invalidateKeys(executionKeys []interface{}) {
+	c.lock()
+	defer c.promisesMu.Unlock()
+
+	for _, executionKey := range executionKeys {
+		c.remove(executionKey)
+	}
+}
+
+// invalidateKeysOfType removes the promises memoized under executionKeys of the
+// given type without cascading to their dependents.
+func (c *cache) invalidateKeysOfType(executionKeyType string) {
+	c.lock()
+	defer c.promisesMu.Unlock()
+
+	for executionKey, p := range c.promises {
+		if p.executionKeyType == executionKeyType {
+			c.remove(executionKey)
+		}
+	}
+}
+
 func (c *cache) extractExecutionKeyType(executionKey interface{}) string {
-	return reflect.TypeOf(executionKey).String()
+	return helper.NameOf(executionKey)
 }
 
-func doExecute(ctx context.Context, memoizedFn Function) (result interface{}, err error) {
+// doExecute calls memoizedFn and converts its panic, if any, into an error. The
+// given onPanic callback, if not nil, receives the recovered value and the stack
+// trace of the panic.
+func doExecute(
+	ctx context.Context,
+	memoizedFn Function,
+	onPanic func(recovered interface{}, stack []byte),
+) (result interface{}, err error) {
 	// Convert panics into standard errors for clients to handle gracefully
 	defer func() {
 		if r := recover(); r != nil {
 			result = nil
 
-			stackTrace := func() string {
-				stack := debug.Stack()
-				if len(stack) == 0 {
-					return ""
-				}
-
-				return string(stack)
-			}()
+			stack := debug.Stack()
+			if onPanic != nil {
+				onPanic(r, stack)
+			}
 
-			err = errors.Wrap(ErrPanicExecutingMemoizedFn, fmt.Sprintf("%v \n %v", r, stackTrace))
+			err = errors.Wrap(ErrPanicExecutingMemoizedFn, fmt.Sprintf("%v \n %v", r, string(stack)))
 		}
 	}()
 
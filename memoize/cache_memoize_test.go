@@ -120,6 +120,35 @@ func TestCache_Execute(t *testing.T) {
                 assert.Equal(t, (int32)(100), evaled, "got %v calls to function, wanted 100", evaled)
             },
         },
+        {
+            desc: "executionKey holding non-comparable values",
+            test: func(t *testing.T) {
+                type key struct {
+                    value interface{}
+                }
+
+                var evaled int32 = 0
+
+                memoizedFn := func(context.Context) (interface{}, error) {
+                    atomic.AddInt32(&evaled, 1)
+                    return 1, nil
+                }
+
+                c := newCache(context.Background())
+
+                assert.NotPanics(t, func() {
+                    for i := 0; i < 2; i++ {
+                        outcome, extra := c.execute(context.Background(), key{value: []int{1}}, memoizedFn)
+                        assert.Equal(t, 1, outcome.Value)
+                        assert.False(t, extra.IsMemoized)
+                        assert.True(t, extra.IsExecuted)
+                    }
+                })
+
+                assert.Equal(t, (int32)(2), evaled)
+                assert.Empty(t, c.promises)
+            },
+        },
         {
             desc: "nil memoizedFn",
             test: func(t *testing.T) {
@@ -275,3 +304,34 @@ func TestCache_FindPromises(t *testing.T) {
     promises = c.findPromises("key")
     assert.Equal(t, 0, len(promises), "no promises should come from a destroyed cache")
 }
+
+func TestCache_CountPromises(t *testing.T) {
+    var c cache
+
+    p, _ := c.promise(
+        "completed", func(ctx context.Context) (interface{}, error) {
+            return 1, nil
+        },
+    )
+    p.get(context.Background())
+
+    c.promise(
+        "pending", func(ctx context.Context) (interface{}, error) {
+            return 2, nil
+        },
+    )
+
+    // should count while other readers are holding the lock
+    c.promisesMu.RLock()
+    pending, completed := c.countPromises()
+    c.promisesMu.RUnlock()
+
+    assert.Equal(t, 1, pending)
+    assert.Equal(t, 1, completed)
+
+    c.destroy()
+
+    pending, completed = c.countPromises()
+    assert.Equal(t, 0, pending, "no promises should come from a destroyed cache")
+    assert.Equal(t, 0, completed, "no promises should come from a destroyed cache")
+}
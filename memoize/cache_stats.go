@@ -0,0 +1,212 @@
+package memoize
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CacheStats summarizes how a cache has been used since it was created.
+type CacheStats struct {
+	KeyTypeStats
+	// ByKeyType breaks these statistics down by the type of executionKey, e.g.
+	// "memoize.userKey".
+	ByKeyType map[string]KeyTypeStats
+}
+
+// KeyTypeStats summarizes how a cache has been used for executionKeys of one type.
+type KeyTypeStats struct {
+	// Hits is the number of calls to Execute served by an existing promise,
+	// including PopulatedHits.
+	Hits int64
+	// Misses is the number of calls to Execute that created a new promise and
+	// hence executed their memoizedFn.
+	Misses int64
+	// PopulatedHits is the number of calls to Execute served by an Outcome that
+	// was put into the cache via PopulateCache.
+	PopulatedHits int64
+	// Panics is the number of memoizedFn that panicked.
+	Panics int64
+	// InFlight is the number of promises that were still pending when the
+	// statistics were taken.
+	InFlight int
+}
+
+func (s *KeyTypeStats) add(other KeyTypeStats) {
+	s.Hits += other.Hits
+	s.Misses += other.Misses
+	s.PopulatedHits += other.PopulatedHits
+	s.Panics += other.Panics
+	s.InFlight += other.InFlight
+}
+
+// merge adds the given statistics to these ones.
+func (s *CacheStats) merge(other CacheStats) {
+	s.add(other.KeyTypeStats)
+
+	if len(other.ByKeyType) > 0 && s.ByKeyType == nil {
+		s.ByKeyType = make(map[string]KeyTypeStats, len(other.ByKeyType))
+	}
+
+	for executionKeyType, keyTypeStats := range other.ByKeyType {
+		merged := s.ByKeyType[executionKeyType]
+		merged.add(keyTypeStats)
+		s.ByKeyType[executionKeyType] = merged
+	}
+}
+
+// statsRecorder counts the usage of a cache per executionKeyType and forwards it
+// to a MetricsReporter, if any. Counters are updated atomically so that recording
+// hits doesn't serialize concurrent callers.
+type statsRecorder struct {
+	byKeyType sync.Map // map[string]*keyTypeCounters
+	reporter  MetricsReporter
+}
+
+// keyTypeCounters holds the counters of one executionKeyType.
+type keyTypeCounters struct {
+	hits          int64
+	misses        int64
+	populatedHits int64
+	panics        int64
+}
+
+func (r *statsRecorder) counters(executionKeyType string) *keyTypeCounters {
+	if counters, ok := r.byKeyType.Load(executionKeyType); ok {
+		return counters.(*keyTypeCounters)
+	}
+
+	counters, _ := r.byKeyType.LoadOrStore(executionKeyType, &keyTypeCounters{})
+	return counters.(*keyTypeCounters)
+}
+
+func (r *statsRecorder) recordHit(executionKeyType string, isPopulated bool) {
+	counters := r.counters(executionKeyType)
+
+	atomic.AddInt64(&counters.hits, 1)
+	if isPopulated {
+		atomic.AddInt64(&counters.populatedHits, 1)
+	}
+
+	if r.reporter != nil {
+		r.reporter.OnHit(executionKeyType)
+	}
+}
+
+func (r *statsRecorder) recordMiss(executionKeyType string) {
+	atomic.AddInt64(&r.counters(executionKeyType).misses, 1)
+
+	if r.reporter != nil {
+		r.reporter.OnMiss(executionKeyType)
+	}
+}
+
+func (r *statsRecorder) recordExecution(executionKeyType string, duration time.Duration, err error) {
+	if errors.Is(err, ErrPanicExecutingMemoizedFn) {
+		atomic.AddInt64(&r.counters(executionKeyType).panics, 1)
+	}
+
+	if r.reporter != nil {
+		r.reporter.OnExecutionDone(executionKeyType, duration, err)
+	}
+}
+
+// snapshot returns the statistics recorded so far.
+func (r *statsRecorder) snapshot() CacheStats {
+	stats := CacheStats{
+		ByKeyType: make(map[string]KeyTypeStats),
+	}
+
+	r.byKeyType.Range(
+		func(key, value interface{}) bool {
+			counters := value.(*keyTypeCounters)
+
+			s := KeyTypeStats{
+				Hits:          atomic.LoadInt64(&counters.hits),
+				Misses:        atomic.LoadInt64(&counters.misses),
+				PopulatedHits: atomic.LoadInt64(&counters.populatedHits),
+				Panics:        atomic.LoadInt64(&counters.panics),
+			}
+
+			stats.ByKeyType[key.(string)] = s
+			stats.add(s)
+
+			return true
+		},
+	)
+
+	return stats
+}
+
+func (c *cache) stats() CacheStats {
+	stats := c.statsRecorder.snapshot()
+
+	c.lock()
+	defer c.promisesMu.Unlock()
+
+	for _, p := range c.promises {
+		if p.isDone() {
+			continue
+		}
+
+		keyTypeStats := stats.ByKeyType[p.executionKeyType]
+		keyTypeStats.InFlight++
+		stats.ByKeyType[p.executionKeyType] = keyTypeStats
+		stats.InFlight++
+	}
+
+	return stats
+}
+
+func (c concurrentCache) stats() CacheStats {
+	var stats CacheStats
+	for _, shard := range c {
+		stats.merge(shard.stats())
+	}
+
+	return stats
+}
+
+func (c *noMemoizeCache) stats() CacheStats {
+	return CacheStats{}
+}
+
+// ShardStats describes the load of one shard of a cache, e.g. to tune the
+// concurrencyLevel given to WithConcurrentCache.
+type ShardStats struct {
+	// Promises is the number of promises held by the shard.
+	Promises int
+	// LockAcquisitions is the number of times the lock of the shard was acquired.
+	LockAcquisitions int64
+	// LockContentions is the number of times the lock of the shard was already
+	// held by another goroutine when it was acquired.
+	LockContentions int64
+}
+
+func (c *cache) shardStats() []ShardStats {
+	c.promisesMu.Lock()
+	promises := len(c.promises)
+	c.promisesMu.Unlock()
+
+	return []ShardStats{
+		{
+			Promises:         promises,
+			LockAcquisitions: atomic.LoadInt64(&c.lockAcquisitions),
+			LockContentions:  atomic.LoadInt64(&c.lockContentions),
+		},
+	}
+}
+
+func (c concurrentCache) shardStats() []ShardStats {
+	stats := make([]ShardStats, 0, len(c))
+	for _, shard := range c {
+		stats = append(stats, shard.shardStats()...)
+	}
+
+	return stats
+}
+
+func (c *noMemoizeCache) shardStats() []ShardStats {
+	return nil
+}
@@ -0,0 +1,50 @@
+package memoize
+
+import (
+	"context"
+
+	"github.com/jamestrandung/go-context/helper"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the name of the Tracer obtained from the TracerProvider given to
+// WithTracerProvider.
+const TracerName = "github.com/jamestrandung/go-context/memoize"
+
+// tracedCache is an iCache starting an OpenTelemetry span for every execution.
+type tracedCache struct {
+	iCache
+	tracer trace.Tracer
+}
+
+func (c tracedCache) execute(
+	ctx context.Context,
+	executionKey interface{},
+	memoizedFn Function,
+	opts ...executeOption,
+) (Outcome, Extra) {
+	executionKeyType := helper.NameOf(executionKey)
+
+	ctx, span := c.tracer.Start(
+		ctx,
+		executionKeyType,
+		trace.WithAttributes(attribute.String(KeyTypeAttr, executionKeyType)),
+	)
+	defer span.End()
+
+	outcome, extra := c.iCache.execute(ctx, executionKey, memoizedFn, opts...)
+
+	span.SetAttributes(
+		attribute.Bool(IsMemoizedAttr, extra.IsMemoized),
+		attribute.Bool(IsExecutedAttr, extra.IsExecuted),
+	)
+
+	if outcome.Err != nil {
+		span.RecordError(outcome.Err)
+		span.SetStatus(codes.Error, outcome.Err.Error())
+	}
+
+	return outcome, extra
+}
@@ -0,0 +1,109 @@
+package memoize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jamestrandung/go-context/helper"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingOTelSpan struct {
+	trace.Span
+	name       string
+	attrs      []attribute.KeyValue
+	errs       []error
+	statusCode codes.Code
+	isEnded    bool
+}
+
+func (s *recordingOTelSpan) SetAttributes(kv ...attribute.KeyValue) {
+	s.attrs = append(s.attrs, kv...)
+}
+
+func (s *recordingOTelSpan) RecordError(err error, options ...trace.EventOption) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordingOTelSpan) SetStatus(code codes.Code, description string) {
+	s.statusCode = code
+}
+
+func (s *recordingOTelSpan) End(options ...trace.SpanEndOption) {
+	s.isEnded = true
+}
+
+type recordingTracerProvider struct {
+	trace.TracerProvider
+	tracer *recordingTracer
+}
+
+func (tp *recordingTracerProvider) Tracer(name string, options ...trace.TracerOption) trace.Tracer {
+	tp.tracer.name = name
+	return tp.tracer
+}
+
+type recordingTracer struct {
+	trace.Tracer
+	name  string
+	spans []*recordingOTelSpan
+}
+
+func (t *recordingTracer) Start(
+	ctx context.Context,
+	spanName string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+
+	span := &recordingOTelSpan{
+		Span:  trace.SpanFromContext(context.Background()),
+		name:  spanName,
+		attrs: cfg.Attributes(),
+	}
+
+	t.spans = append(t.spans, span)
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type tracedTestKey struct{}
+
+func TestTracedCache_Execute(t *testing.T) {
+	tracer := &recordingTracer{}
+
+	ctx, destroyFn := WithCache(context.Background(), WithTracerProvider(&recordingTracerProvider{tracer: tracer}))
+	defer destroyFn()
+
+	var spanInFn trace.Span
+	for i := 0; i < 2; i++ {
+		Execute(ctx, tracedTestKey{}, func(ctx context.Context) (int, error) {
+			spanInFn = trace.SpanFromContext(ctx)
+			return 0, assert.AnError
+		})
+	}
+
+	assert.Equal(t, TracerName, tracer.name)
+	assert.Len(t, tracer.spans, 2)
+	assert.Same(t, tracer.spans[0], spanInFn, "memoizedFn must run in the span of its execution")
+
+	keyType := helper.NameOf(tracedTestKey{})
+	for _, span := range tracer.spans {
+		assert.Equal(t, keyType, span.name)
+		assert.Equal(
+			t,
+			[]attribute.KeyValue{
+				attribute.String(KeyTypeAttr, keyType),
+				attribute.Bool(IsMemoizedAttr, true),
+				attribute.Bool(IsExecutedAttr, true),
+			},
+			span.attrs,
+		)
+		assert.Equal(t, []error{assert.AnError}, span.errs)
+		assert.Equal(t, codes.Error, span.statusCode)
+		assert.True(t, span.isEnded)
+	}
+}
@@ -0,0 +1,35 @@
+package memoize
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves forward when told to.
+type fakeClock struct {
+	now int64
+}
+
+// useFakeClock makes timeNow return the time of a fakeClock until the given test
+// completes.
+func useFakeClock(t *testing.T) *fakeClock {
+	clock := &fakeClock{
+		now: time.Now().UnixNano(),
+	}
+
+	nowFn.Store(clock.time)
+	t.Cleanup(func() {
+		nowFn.Store(time.Now)
+	})
+
+	return clock
+}
+
+func (c *fakeClock) time() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.now))
+}
+
+func (c *fakeClock) add(d time.Duration) {
+	atomic.AddInt64(&c.now, int64(d))
+}
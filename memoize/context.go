@@ -2,6 +2,10 @@ package memoize
 
 import (
 	"context"
+	"time"
+
+	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/helper"
 )
 
 type contextKey struct{}
@@ -26,9 +30,9 @@ type DestroyFn func()
 // still proceed till completion.
 //
 // Note: the return DestroyFn must be deferred to minimize memory leaks.
-func WithCache(ctx context.Context) (context.Context, DestroyFn) {
-	c := newCache(ctx)
-	return context.WithValue(ctx, memoizeStoreKey, c), c.destroy
+func WithCache(ctx context.Context, opts ...Option) (context.Context, DestroyFn) {
+	c := NewCache(ctx, opts...)
+	return c.Attach(ctx), c.Destroy
 }
 
 // WithConcurrentCache returns a new context.Context that holds a reference
@@ -45,16 +49,22 @@ func WithCache(ctx context.Context) (context.Context, DestroyFn) {
 // can cancel the context given to Execute to stop waiting for the result from
 // the memoized function, which will still proceed till completion.
 //
+// The concurrencyLevel is the number of shards of the cache. Pass AutoShards to
+// size them according to runtime.GOMAXPROCS. Zero and negative values fall back
+// to a default level while values above 1024 are clamped.
+//
 // Note: the return DestroyFn must be deferred to minimize memory leaks.
-func WithConcurrentCache(ctx context.Context, concurrencyLevel int) (context.Context, DestroyFn) {
+func WithConcurrentCache(ctx context.Context, concurrencyLevel int, opts ...Option) (context.Context, DestroyFn) {
 	c := func() iCache {
-		if concurrencyLevel == 1 {
+		if normalizeConcurrencyLevel(concurrencyLevel) == 1 {
 			return newCache(ctx)
 		}
 
 		return newConcurrentCache(ctx, concurrencyLevel)
 	}()
 
+	c = newOptions(opts...).apply(c)
+
 	return context.WithValue(ctx, memoizeStoreKey, c), c.destroy
 }
 
@@ -78,7 +88,19 @@ func extractCache(ctx context.Context) iCache {
 //
 // Note: the given entries can only be populated in the cache if the
 // input context has been initialized using WithCache.
+//
+// Deprecated: use PopulateTypedCache instead.
 func PopulateCacheWithTypedOutcomes[K comparable, V any](ctx context.Context, entries map[K]TypedOutcome[V]) {
+	PopulateTypedCache(ctx, entries)
+}
+
+// PopulateTypedCache works like PopulateCache but takes entries keyed by a
+// concrete executionKey type and carrying TypedOutcome, so that pre-warming
+// code doesn't need to convert them into the untyped form.
+//
+// Note: the given entries can only be populated in the cache if the
+// input context has been initialized using WithCache.
+func PopulateTypedCache[K comparable, V any](ctx context.Context, entries map[K]TypedOutcome[V]) {
 	if len(entries) == 0 {
 		return
 	}
@@ -131,6 +153,125 @@ func Execute[K comparable, V any](
 	ctx context.Context,
 	executionKey K,
 	memoizedFn func(context.Context) (V, error),
+) (TypedOutcome[V], Extra) {
+	return execute(ctx, executionKey, memoizedFn)
+}
+
+// ExecuteWithTTL works like Execute but the outcome stays memoized for the
+// given duration after the memoizedFn completes. Once it expires, the next
+// call re-executes the memoizedFn, and expired outcomes are removed from the
+// cache in the background. This is useful for long-lived contexts, e.g. those
+// of background workers, whose cache would otherwise serve stale outcomes.
+//
+// Note: the TTL applies only if this call creates the promise for the given
+// executionKey. An outcome memoized via Execute never expires unless the cache
+// was created using WithDefaultTTL.
+func ExecuteWithTTL[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	memoizedFn func(context.Context) (V, error),
+	ttl time.Duration,
+) (TypedOutcome[V], Extra) {
+	return execute(ctx, executionKey, memoizedFn, withTTL(ttl))
+}
+
+// ExecuteWithTimeout works like Execute but the memoizedFn is given a context that
+// gets cancelled once the given timeout elapses. If the memoizedFn has not returned
+// by then, all callers receive ErrExecutionTimedOut, even if the memoizedFn ignores
+// the cancellation of its context, instead of being blocked by a hung execution
+// for the life of the request.
+//
+// Note: the timeout applies only if this call creates the promise for the given
+// executionKey.
+func ExecuteWithTimeout[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	memoizedFn func(context.Context) (V, error),
+	timeout time.Duration,
+) (TypedOutcome[V], Extra) {
+	return execute(ctx, executionKey, memoizedFn, withTimeout(timeout))
+}
+
+// ExecuteRefreshing works like Execute but serves outcomes in stale-while-revalidate
+// mode. If the memoized outcome completed more than maxAge ago, it is still returned
+// immediately, while the memoizedFn gets re-executed in the background. Once this
+// re-execution succeeds, its outcome replaces the stale one for subsequent calls. If
+// it fails, the stale outcome keeps being served and the next call tries again. This
+// is useful for caches reused across a long-lived streaming handler.
+//
+// Note: the re-execution is detached from the cancellation of the given context,
+// but it is abandoned if the root context given to WithCache gets cancelled.
+func ExecuteRefreshing[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	memoizedFn func(context.Context) (V, error),
+	maxAge time.Duration,
+) (TypedOutcome[V], Extra) {
+	return execute(ctx, executionKey, memoizedFn, withMaxAge(maxAge))
+}
+
+// ExecuteWithDeps works like Execute but records that the outcome memoized under
+// the given executionKey is derived from the outcomes memoized under the given
+// dependencies. Invalidating any of these dependencies, e.g. via Invalidate, then
+// cascades to this executionKey and, transitively, to the keys depending on it, so
+// that derived computations get re-executed without destroying the whole cache.
+//
+// Note: dependencies that are not comparable are ignored. The dependencies are
+// recorded even if this call hits an existing promise.
+func ExecuteWithDeps[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	memoizedFn func(context.Context) (V, error),
+	dependencies ...interface{},
+) (TypedOutcome[V], Extra) {
+	return execute(ctx, executionKey, memoizedFn, withDependencies(dependencies))
+}
+
+// TryExecute works like Execute but never waits for the memoizedFn run by another
+// caller. If the outcome memoized under the given executionKey is still pending,
+// it returns ErrExecutionPending immediately so that best-effort code paths can
+// fall back instead of blocking. If there is no promise for this key yet, the
+// memoizedFn is executed by this call as usual.
+func TryExecute[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	memoizedFn func(context.Context) (V, error),
+) (TypedOutcome[V], Extra) {
+	return execute(ctx, executionKey, memoizedFn, withoutWaiting())
+}
+
+// Prefetch registers a promise for the given executionKey and starts executing the
+// given memoizedFn in the background without blocking the caller, so that later
+// calls to Execute with this key hit an in-flight or already completed promise.
+// It does nothing if a promise already exists for this key.
+//
+// The execution is detached from the cancellation of the given context, but it
+// is abandoned if the root context given to WithCache gets cancelled.
+//
+// Note: this function can only prefetch if the given context has been
+// initialized using WithCache.
+func Prefetch[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	memoizedFn func(context.Context) (V, error),
+) {
+	if memoizedFn == nil {
+		return
+	}
+
+	c := extractCache(ctx)
+	c.prefetch(
+		cext.Detach(ctx), executionKey, func(ctx context.Context) (interface{}, error) {
+			return memoizedFn(ctx)
+		},
+	)
+}
+
+func execute[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	memoizedFn func(context.Context) (V, error),
+	opts ...executeOption,
 ) (TypedOutcome[V], Extra) {
 	var convertedFn func(context.Context) (interface{}, error)
 	if memoizedFn != nil {
@@ -141,7 +282,7 @@ func Execute[K comparable, V any](
 
 	c := extractCache(ctx)
 
-	outcome, extra := c.execute(ctx, executionKey, convertedFn)
+	outcome, extra := c.execute(ctx, executionKey, convertedFn, opts...)
 	return newTypedOutcome[V](outcome), extra
 }
 
@@ -175,6 +316,44 @@ func FindOutcomes[K comparable, V any](ctx context.Context, executionKey K) map[
 	return m
 }
 
+// StreamOutcomes calls fn for each Outcome memoized under the given executionKey
+// type, one at a time and in no particular order, until fn returns false. Unlike
+// FindOutcomes, it doesn't build a map of all outcomes, which is wasteful for
+// requests memoizing thousands of items. If wait is true, StreamOutcomes waits
+// for pending promises to complete, otherwise it skips them.
+//
+// Note: this function can only stream memoized Outcome if the given context
+// has been initialized using WithCache.
+func StreamOutcomes[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	fn func(K, TypedOutcome[V]) bool,
+	wait bool,
+) {
+	c := extractCache(ctx)
+
+	for key, p := range c.findPromises(executionKey) {
+		if !wait && !p.isDone() {
+			continue
+		}
+
+		// Check if context was cancelled while we were waiting
+		// for the previous promise.
+		if ctx.Err() != nil {
+			return
+		}
+
+		outcome := p.get(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !fn(key.(K), newTypedOutcome[V](outcome)) {
+			return
+		}
+	}
+}
+
 // FindAllOutcomes returns all Outcome that were memoized in this cache
 // at the time findOutcomes was called. If a promise is still pending,
 // the function will block & wait for it to complete to get its Outcome.
@@ -204,6 +383,193 @@ func FindAllOutcomes(ctx context.Context) map[interface{}]Outcome {
 	return m
 }
 
+// Snapshot returns the Outcome of all promises in this cache that have completed,
+// keyed by their executionKey. Pending promises are left out so that Snapshot never
+// waits. Together with Restore, it allows transferring memoized outcomes from the
+// context of a request into that of a detached background job.
+//
+// Note: this function can only return outcomes if the given context has been
+// initialized using WithCache.
+func Snapshot(ctx context.Context) map[interface{}]Outcome {
+	c := extractCache(ctx)
+
+	m := make(map[interface{}]Outcome)
+	for key, p := range c.findPromises(nil) {
+		if p.isDone() {
+			m[key] = p.outcome
+		}
+	}
+
+	return m
+}
+
+// Restore puts the outcomes returned by Snapshot into the cache of the given
+// context, where they are served like pre-populated outcomes. Outcomes memoized
+// under the same executionKey in this cache get replaced.
+//
+// Note: the given snapshot can only be restored if the given context has been
+// initialized using WithCache.
+func Restore(ctx context.Context, snapshot map[interface{}]Outcome) {
+	PopulateCache(ctx, snapshot)
+}
+
+// Peek returns the State of the promise memoized under the given executionKey
+// without triggering its execution or waiting for it, along with its Outcome if
+// it has completed, i.e. if the State is IsResolved or IsPopulated. The returned
+// bool reports whether such a promise exists. This is useful for building
+// progress UIs over long request pipelines.
+//
+// Note: this function can only find promises if the given context has been
+// initialized using WithCache.
+func Peek[K comparable](ctx context.Context, executionKey K) (Outcome, State, bool) {
+	c := extractCache(ctx)
+
+	p := c.peek(executionKey)
+	if p == nil {
+		return Outcome{}, IsCreated, false
+	}
+
+	state := p.currentState()
+	if state != IsResolved && state != IsPopulated {
+		return Outcome{}, state, true
+	}
+
+	return p.outcome, state, true
+}
+
+// Done returns a channel that is closed once the promise memoized under the
+// given executionKey completes, without triggering its execution. It returns
+// nil if there is no such promise, and receiving from a nil channel blocks
+// forever, similar to context.Context.Done.
+//
+// Note: this function can only find promises if the given context has been
+// initialized using WithCache.
+func Done[K comparable](ctx context.Context, executionKey K) <-chan struct{} {
+	c := extractCache(ctx)
+
+	p := c.peek(executionKey)
+	if p == nil {
+		return nil
+	}
+
+	return p.done
+}
+
+// Errors returns the non-nil Outcome.Err of all promises in this cache that have
+// completed, keyed by their executionKey. Unlike WaitErrors, it never waits for
+// pending promises, which are left out.
+//
+// Note: this function can only return errors if the given context has been
+// initialized using WithCache.
+func Errors(ctx context.Context) map[interface{}]error {
+	c := extractCache(ctx)
+
+	m := make(map[interface{}]error)
+	for key, p := range c.findPromises(nil) {
+		if !p.isDone() {
+			continue
+		}
+
+		if err := p.outcome.Err; err != nil {
+			m[key] = err
+		}
+	}
+
+	return m
+}
+
+// WaitErrors works like Errors but waits for pending promises to complete so that
+// a request handler can tell whether any memoized execution failed before building
+// its response. If the given context gets cancelled while waiting, WaitErrors
+// returns the errors collected so far along with the context error.
+//
+// Note: this function can only return errors if the given context has been
+// initialized using WithCache.
+func WaitErrors(ctx context.Context) (map[interface{}]error, error) {
+	c := extractCache(ctx)
+
+	m := make(map[interface{}]error)
+	for key, p := range c.findPromises(nil) {
+		// Check if context was cancelled while we were waiting
+		// for the previous promise.
+		if ctx.Err() != nil {
+			return m, ctx.Err()
+		}
+
+		outcome := p.get(ctx)
+		if ctx.Err() != nil {
+			return m, ctx.Err()
+		}
+
+		if outcome.Err != nil {
+			m[key] = outcome.Err
+		}
+	}
+
+	return m, nil
+}
+
+// CountPromises returns the number of promises in this cache that are
+// still pending and the number of those that have completed, including
+// pre-populated ones. Unlike FindAllOutcomes, it never waits, which makes
+// it suitable for logs and metrics. Counting only takes the read lock of
+// the cache, hence it does not hold up concurrent executions.
+//
+// Note: this function can only count promises if the given context has
+// been initialized using WithCache.
+func CountPromises(ctx context.Context) (pending int, completed int) {
+	c := extractCache(ctx)
+	return c.countPromises()
+}
+
+// Invalidate removes the outcome memoized under the given executionKey so that
+// the next call to Execute with this key invokes its memoizedFn again. This is
+// useful when callers know that the underlying data changed mid-request.
+//
+// Invalidating a key also invalidates the keys that were executed depending on
+// it via ExecuteWithDeps, transitively.
+//
+// Note: callers already waiting for a pending execution under this key will
+// still receive its outcome.
+func Invalidate[K comparable](ctx context.Context, executionKey K) {
+	c := extractCache(ctx)
+	c.invalidate(executionKey)
+}
+
+// InvalidateByKeyType removes all outcomes memoized under executionKeys of type
+// K, similar to calling Invalidate for every key returned by FindOutcomes, and
+// cascades to the keys that were executed depending on keys of this type.
+//
+// Note: K must be the concrete type of the executionKeys given to Execute, an
+// interface type would not match any outcome.
+func InvalidateByKeyType[K comparable](ctx context.Context) {
+	c := extractCache(ctx)
+	c.invalidateByKeyType(helper.TypeNameOf[K]())
+}
+
+// Stats returns how the cache of the given context has been used so far, i.e.
+// its hits, misses, panics and in-flight executions, in total and per type of
+// executionKey. It never waits, which makes it suitable for logs and metrics.
+//
+// Note: this function can only return statistics if the given context has been
+// initialized using WithCache.
+func Stats(ctx context.Context) CacheStats {
+	c := extractCache(ctx)
+	return c.stats()
+}
+
+// ShardStatistics returns the statistics of each shard of the cache of the given
+// context, one for a cache created using WithCache, so that the concurrencyLevel
+// given to WithConcurrentCache can be tuned. Shards with many contentions relative
+// to their acquisitions indicate that more shards would help.
+//
+// Note: this function can only return statistics if the given context has been
+// initialized using WithCache.
+func ShardStatistics(ctx context.Context) []ShardStats {
+	c := extractCache(ctx)
+	return c.shardStats()
+}
+
 // TypedOutcome ...
 type TypedOutcome[V any] struct {
 	Value V
@@ -217,7 +583,7 @@ func newTypedOutcome[V any](o Outcome) TypedOutcome[V] {
 		}
 	}
 
-	casted, _ := o.Value.(V)
+	casted, _ := helper.As[V](o.Value)
 
 	return TypedOutcome[V]{
 		Value: casted,
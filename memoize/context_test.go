@@ -2,12 +2,16 @@ package memoize
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"github.com/jamestrandung/go-context/helper"
 	"github.com/stretchr/testify/assert"
 	"reflect"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestWithCache(t *testing.T) {
@@ -18,6 +22,62 @@ func TestWithCache(t *testing.T) {
 	assert.Equal(t, reflect.TypeOf((*cache)(nil)), reflect.TypeOf(actual))
 }
 
+func TestWithConcurrentCache(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "invalid concurrency levels do not panic",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{-5, 0, 1 << 30} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel)
+
+					outcome, _ := Execute(ctx, "key", func(context.Context) (int, error) {
+						return 1, nil
+					})
+
+					assert.Equal(t, TypedOutcome[int]{Value: 1}, outcome)
+					destroyFn()
+				}
+			},
+		},
+		{
+			desc: "auto shards",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithConcurrentCache(context.Background(), AutoShards)
+				defer destroyFn()
+
+				assert.Len(t, ShardStatistics(ctx), runtime.GOMAXPROCS(0)*shardsPerProc)
+			},
+		},
+		{
+			desc: "shard statistics",
+			test: func(t *testing.T) {
+				assert.Nil(t, ShardStatistics(context.Background()))
+
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				Execute(ctx, "key", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				stats := ShardStatistics(ctx)
+				assert.Len(t, stats, 1)
+				assert.Equal(t, 1, stats[0].Promises)
+				assert.Positive(t, stats[0].LockAcquisitions)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
 func TestExtractCache(t *testing.T) {
 	ctx := context.Background()
 
@@ -128,6 +188,61 @@ func TestPopulateCache(t *testing.T) {
 	}
 }
 
+type populateTestKey struct {
+	id int
+}
+
+func TestPopulateTypedCache(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "typed outcomes are served by Execute",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				PopulateTypedCache(
+					ctx, map[populateTestKey]TypedOutcome[string]{
+						{id: 1}: {Value: "one"},
+						{id: 2}: {Err: assert.AnError},
+					},
+				)
+
+				memoizedFn := func(context.Context) (string, error) {
+					return "executed", nil
+				}
+
+				outcome, extra := Execute(ctx, populateTestKey{id: 1}, memoizedFn)
+				assert.Equal(t, TypedOutcome[string]{Value: "one"}, outcome)
+				assert.False(t, extra.IsExecuted)
+
+				outcome, _ = Execute(ctx, populateTestKey{id: 2}, memoizedFn)
+				assert.Equal(t, TypedOutcome[string]{Err: assert.AnError}, outcome)
+			},
+		},
+		{
+			desc: "context was not initialized using WithCache",
+			test: func(t *testing.T) {
+				assert.NotPanics(t, func() {
+					PopulateTypedCache(
+						context.Background(), map[populateTestKey]TypedOutcome[string]{
+							{id: 1}: {Value: "one"},
+						},
+					)
+				})
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
 func TestExecute(t *testing.T) {
 	scenarios := []struct {
 		desc string
@@ -243,6 +358,104 @@ func TestExecute(t *testing.T) {
 	}
 }
 
+func TestExecuteRefreshing(t *testing.T) {
+	clock := useFakeClock(t)
+
+	ctx, destroyFn := WithCache(context.Background())
+	defer destroyFn()
+
+	var calls int32
+	memoizedFn := func(context.Context) (int32, error) {
+		return atomic.AddInt32(&calls, 1), nil
+	}
+
+	outcome, _ := ExecuteRefreshing(ctx, "key", memoizedFn, time.Minute)
+	assert.Equal(t, int32(1), outcome.Value)
+
+	clock.add(30 * time.Second)
+
+	outcome, _ = ExecuteRefreshing(ctx, "key", memoizedFn, time.Minute)
+	assert.Equal(t, int32(1), outcome.Value, "fresh outcome is served")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	clock.add(time.Minute)
+
+	outcome, _ = ExecuteRefreshing(ctx, "key", memoizedFn, time.Minute)
+	assert.Equal(t, int32(1), outcome.Value, "stale outcome is served while refreshing")
+
+	assert.Eventually(t, func() bool {
+		outcome, _ := Execute(ctx, "key", memoizedFn)
+		return outcome.Value == 2
+	}, time.Second, time.Millisecond, "refreshed outcome replaces the stale one")
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestPrefetch(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				Prefetch(context.Background(), "key", func(context.Context) (int, error) {
+					assert.Fail(t, "memoizedFn must not be executed")
+					return 1, nil
+				})
+			},
+		},
+		{
+			desc: "later calls hit the prefetched promise",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel)
+
+					release := make(chan struct{})
+					started := make(chan struct{})
+
+					callerCtx, cancel := context.WithCancel(ctx)
+					Prefetch(callerCtx, "key", func(context.Context) (int, error) {
+						close(started)
+						<-release
+						return 1, nil
+					})
+
+					// Cancelling the caller does not abandon the prefetch
+					cancel()
+
+					<-started
+
+					pending, _ := CountPromises(ctx)
+					assert.Equal(t, 1, pending)
+
+					close(release)
+
+					outcome, extra := Execute(ctx, "key", func(context.Context) (int, error) {
+						return 2, nil
+					})
+
+					assert.Equal(t, TypedOutcome[int]{Value: 1}, outcome)
+					assert.True(t, extra.IsExecuted)
+
+					Prefetch(ctx, "key", func(context.Context) (int, error) {
+						assert.Fail(t, "memoizedFn must not be executed again")
+						return 3, nil
+					})
+
+					destroyFn()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
 func TestFindOutcomes(t *testing.T) {
 	scenarios := []struct {
 		desc string
@@ -350,6 +563,107 @@ func TestFindOutcomes(t *testing.T) {
 	}
 }
 
+type streamTestKey struct {
+	id int
+}
+
+func TestStreamOutcomes(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				StreamOutcomes(context.Background(), streamTestKey{}, func(streamTestKey, TypedOutcome[int]) bool {
+					assert.Fail(t, "no outcome expected")
+					return true
+				}, true)
+			},
+		},
+		{
+			desc: "outcomes of the given key type",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
+
+				PopulateCache(ctx, map[interface{}]Outcome{
+					streamTestKey{id: 1}: {Value: 1},
+					streamTestKey{id: 2}: {Value: 2},
+					"other":              {Value: 3},
+				})
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(ctx, streamTestKey{id: 3}, func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 3, nil
+				})
+
+				<-started
+
+				collect := func(wait bool) map[streamTestKey]TypedOutcome[int] {
+					m := make(map[streamTestKey]TypedOutcome[int])
+					StreamOutcomes(ctx, streamTestKey{}, func(key streamTestKey, outcome TypedOutcome[int]) bool {
+						m[key] = outcome
+						return true
+					}, wait)
+
+					return m
+				}
+
+				assert.Equal(
+					t,
+					map[streamTestKey]TypedOutcome[int]{
+						{id: 1}: {Value: 1},
+						{id: 2}: {Value: 2},
+					},
+					collect(false),
+				)
+
+				close(release)
+
+				assert.Equal(
+					t,
+					map[streamTestKey]TypedOutcome[int]{
+						{id: 1}: {Value: 1},
+						{id: 2}: {Value: 2},
+						{id: 3}: {Value: 3},
+					},
+					collect(true),
+				)
+			},
+		},
+		{
+			desc: "stops when fn returns false",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				PopulateCache(ctx, map[interface{}]Outcome{
+					streamTestKey{id: 1}: {Value: 1},
+					streamTestKey{id: 2}: {Value: 2},
+				})
+
+				calls := 0
+				StreamOutcomes(ctx, streamTestKey{}, func(streamTestKey, TypedOutcome[int]) bool {
+					calls++
+					return false
+				}, true)
+
+				assert.Equal(t, 1, calls)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
 func TestFindAllOutcomes(t *testing.T) {
 	scenarios := []struct {
 		desc string
@@ -457,40 +771,861 @@ func TestFindAllOutcomes(t *testing.T) {
 	}
 }
 
-func TestNewTypedOutcome(t *testing.T) {
+func TestSnapshot(t *testing.T) {
 	scenarios := []struct {
 		desc string
 		test func(t *testing.T)
 	}{
 		{
-			desc: "outcome Value is nil",
+			desc: "without cache",
 			test: func(t *testing.T) {
-				o := Outcome{}
+				assert.Empty(t, Snapshot(context.Background()))
+			},
+		},
+		{
+			desc: "completed outcomes are restored into another cache",
+			test: func(t *testing.T) {
+				parentCtx, destroyParentFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyParentFn()
+
+				PopulateCache(parentCtx, map[interface{}]Outcome{
+					"populated": {Value: 1},
+				})
+
+				Execute(parentCtx, "executed", func(context.Context) (int, error) {
+					return 0, assert.AnError
+				})
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(parentCtx, "pending", func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 1, nil
+				})
+
+				<-started
+				defer close(release)
+
+				snapshot := Snapshot(parentCtx)
+				assert.Equal(
+					t,
+					map[interface{}]Outcome{
+						"populated": {Value: 1},
+						"executed":  {Value: 0, Err: assert.AnError},
+					},
+					snapshot,
+				)
 
-				actual := newTypedOutcome[int](o)
-				assert.Equal(t, 0, actual.Value)
+				jobCtx, destroyJobFn := WithCache(context.Background())
+				defer destroyJobFn()
+
+				Restore(jobCtx, snapshot)
+
+				outcome, extra := Execute(jobCtx, "executed", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, TypedOutcome[int]{Err: assert.AnError}, outcome)
+				assert.False(t, extra.IsExecuted)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestPeek(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				_, _, ok := Peek(context.Background(), "key")
+				assert.False(t, ok)
+				assert.Nil(t, Done(context.Background(), "key"))
 			},
 		},
 		{
-			desc: "outcome Value is not of expected type",
+			desc: "states of promises",
 			test: func(t *testing.T) {
-				o := Outcome{
-					Value: "string",
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
+
+				PopulateCache(ctx, map[interface{}]Outcome{
+					"populated": {Value: 1},
+				})
+
+				outcome, state, ok := Peek(ctx, "populated")
+				assert.True(t, ok)
+				assert.Equal(t, IsPopulated, state)
+				assert.Equal(t, Outcome{Value: 1}, outcome)
+
+				_, _, ok = Peek(ctx, "missing")
+				assert.False(t, ok)
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(ctx, "pending", func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 2, nil
+				})
+
+				<-started
+
+				outcome, state, ok = Peek(ctx, "pending")
+				assert.True(t, ok)
+				assert.Equal(t, IsExecuted, state)
+				assert.Equal(t, Outcome{}, outcome)
+
+				done := Done(ctx, "pending")
+				select {
+				case <-done:
+					assert.Fail(t, "pending promise must not be done")
+				default:
 				}
 
-				actual := newTypedOutcome[int](o)
-				assert.Equal(t, 0, actual.Value)
+				close(release)
+				<-done
+
+				outcome, state, ok = Peek(ctx, "pending")
+				assert.True(t, ok)
+				assert.Equal(t, IsResolved, state)
+				assert.Equal(t, Outcome{Value: 2}, outcome)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				assert.Empty(t, Errors(context.Background()))
+
+				errs, err := WaitErrors(context.Background())
+				assert.Empty(t, errs)
+				assert.Nil(t, err)
 			},
 		},
 		{
-			desc: "outcome Value is of expected type",
+			desc: "completed and pending promises",
 			test: func(t *testing.T) {
-				o := Outcome{
-					Value: 1,
-				}
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
 
-				actual := newTypedOutcome[int](o)
-				assert.Equal(t, 1, actual.Value)
+				PopulateCache(ctx, map[interface{}]Outcome{
+					"populated": {Err: assert.AnError},
+					"succeeded": {Value: 1},
+				})
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(ctx, "pending", func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 0, assert.AnError
+				})
+
+				<-started
+
+				assert.Equal(t, map[interface{}]error{"populated": assert.AnError}, Errors(ctx))
+
+				cancelledCtx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				_, err := WaitErrors(cancelledCtx)
+				assert.Equal(t, context.Canceled, err)
+
+				close(release)
+
+				errs, err := WaitErrors(ctx)
+				assert.Nil(t, err)
+				assert.Equal(
+					t,
+					map[interface{}]error{
+						"populated": assert.AnError,
+						"pending":   assert.AnError,
+					},
+					errs,
+				)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestCountPromises(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				pending, completed := CountPromises(context.Background())
+				assert.Equal(t, 0, pending)
+				assert.Equal(t, 0, completed)
+			},
+		},
+		{
+			desc: "with pending and completed promises",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
+
+				PopulateCache(ctx, map[interface{}]Outcome{
+					"populated": {Value: 1},
+				})
+
+				Execute(ctx, "executed", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(ctx, "pending", func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 1, nil
+				})
+
+				<-started
+
+				pending, completed := CountPromises(ctx)
+				assert.Equal(t, 1, pending)
+				assert.Equal(t, 2, completed)
+
+				close(release)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestExecuteWithTimeout(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "hung execution times out",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				release := make(chan struct{})
+				defer close(release)
+
+				outcome, _ := ExecuteWithTimeout(ctx, "hung", func(context.Context) (int, error) {
+					<-release
+					return 1, nil
+				}, 10*time.Millisecond)
+
+				assert.Equal(t, ErrExecutionTimedOut, outcome.Err)
+				assert.True(t, errors.Is(outcome.Err, context.DeadlineExceeded))
+
+				outcome, _ = Execute(ctx, "hung", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.Equal(t, ErrExecutionTimedOut, outcome.Err, "subsequent callers must not be blocked")
+			},
+		},
+		{
+			desc: "memoizedFn sees the deadline",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				outcome, _ := ExecuteWithTimeout(ctx, "deadline", func(ctx context.Context) (bool, error) {
+					_, hasDeadline := ctx.Deadline()
+					return hasDeadline, nil
+				}, time.Minute)
+
+				assert.Equal(t, TypedOutcome[bool]{Value: true}, outcome)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestExecute_Timestamps(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "executed outcome",
+			test: func(t *testing.T) {
+				clock := useFakeClock(t)
+
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				memoizedFn := func(context.Context) (int, error) {
+					clock.add(5 * time.Second)
+					return 1, nil
+				}
+
+				before := timeNow()
+
+				_, extra := Execute(ctx, "key", memoizedFn)
+				assert.Equal(t, before, extra.StartedAt)
+				assert.Equal(t, before.Add(5*time.Second), extra.CompletedAt)
+				assert.Equal(t, 5*time.Second, extra.Duration)
+
+				clock.add(time.Second)
+
+				_, memoizedExtra := Execute(ctx, "key", memoizedFn)
+				assert.Equal(t, extra, memoizedExtra)
+				assert.True(t, memoizedExtra.StartedAt.Before(timeNow()))
+			},
+		},
+		{
+			desc: "populated outcome",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				PopulateCache(ctx, map[interface{}]Outcome{
+					"key": {Value: 1},
+				})
+
+				_, extra := Execute(ctx, "key", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.True(t, extra.StartedAt.IsZero())
+				assert.False(t, extra.CompletedAt.IsZero())
+				assert.Zero(t, extra.Duration)
+			},
+		},
+		{
+			desc: "caller stopped waiting",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				release := make(chan struct{})
+				defer close(release)
+
+				cancelledCtx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				Prefetch(ctx, "key", func(context.Context) (int, error) {
+					<-release
+					return 1, nil
+				})
+
+				_, extra := Execute(cancelledCtx, "key", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.True(t, extra.CompletedAt.IsZero())
+				assert.Zero(t, extra.Duration)
+			},
+		},
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				_, extra := Execute(context.Background(), "key", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.False(t, extra.StartedAt.IsZero())
+				assert.False(t, extra.CompletedAt.IsZero())
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestTryExecute(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				outcome, extra := TryExecute(context.Background(), "key", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, TypedOutcome[int]{Value: 1}, outcome)
+				assert.False(t, extra.IsMemoized)
+			},
+		},
+		{
+			desc: "pending execution is not waited for",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(ctx, "key", func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 1, nil
+				})
+
+				<-started
+
+				outcome, extra := TryExecute(ctx, "key", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.Equal(t, ErrExecutionPending, outcome.Err)
+				assert.True(t, extra.IsMemoized)
+				assert.True(t, extra.CompletedAt.IsZero())
+
+				close(release)
+				<-Done(ctx, "key")
+
+				outcome, _ = TryExecute(ctx, "key", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.Equal(t, TypedOutcome[int]{Value: 1}, outcome)
+			},
+		},
+		{
+			desc: "missing key is executed",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				outcome, extra := TryExecute(ctx, "key", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, TypedOutcome[int]{Value: 1}, outcome)
+				assert.True(t, extra.IsMemoized)
+				assert.True(t, extra.IsExecuted)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestExecuteWithTTL(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "expired outcomes are re-computed",
+			test: func(t *testing.T) {
+				clock := useFakeClock(t)
+
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				calls := 0
+				memoizedFn := func(context.Context) (int, error) {
+					calls++
+					return calls, nil
+				}
+
+				outcome, _ := ExecuteWithTTL(ctx, "key", memoizedFn, time.Minute)
+				assert.Equal(t, 1, outcome.Value)
+
+				clock.add(time.Minute - 1)
+
+				outcome, _ = ExecuteWithTTL(ctx, "key", memoizedFn, time.Minute)
+				assert.Equal(t, 1, outcome.Value)
+				assert.Len(t, FindOutcomes[string, int](ctx, "key"), 1)
+
+				clock.add(1)
+
+				assert.Len(t, FindOutcomes[string, int](ctx, "key"), 0, "expired outcomes must not be found")
+
+				outcome, extra := ExecuteWithTTL(ctx, "key", memoizedFn, time.Minute)
+				assert.Equal(t, 2, outcome.Value)
+				assert.True(t, extra.IsExecuted)
+			},
+		},
+		{
+			desc: "expired outcomes are reaped",
+			test: func(t *testing.T) {
+				reapInterval = time.Millisecond
+				defer func() {
+					reapInterval = time.Minute
+				}()
+
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
+
+				ExecuteWithTTL(ctx, "key", func(context.Context) (int, error) {
+					return 1, nil
+				}, time.Millisecond)
+
+				Execute(ctx, "other", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.Eventually(t, func() bool {
+					count := 0
+					for _, shard := range extractCache(ctx).(concurrentCache) {
+						shard.promisesMu.Lock()
+						count += len(shard.promises)
+						shard.promisesMu.Unlock()
+					}
+
+					return count == 1
+				}, time.Second, time.Millisecond)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+type invalidateTestKey struct {
+	id int
+}
+
+func TestInvalidate(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				assert.NotPanics(t, func() {
+					Invalidate(context.Background(), invalidateTestKey{id: 1})
+					InvalidateByKeyType[invalidateTestKey](context.Background())
+				})
+			},
+		},
+		{
+			desc: "invalidated key is re-executed",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel)
+
+					calls := 0
+					execute := func(key invalidateTestKey) TypedOutcome[int] {
+						outcome, _ := Execute(ctx, key, func(context.Context) (int, error) {
+							calls++
+							return calls, nil
+						})
+
+						return outcome
+					}
+
+					assert.Equal(t, 1, execute(invalidateTestKey{id: 1}).Value)
+					assert.Equal(t, 2, execute(invalidateTestKey{id: 2}).Value)
+
+					Invalidate(ctx, invalidateTestKey{id: 1})
+
+					assert.Equal(t, 3, execute(invalidateTestKey{id: 1}).Value)
+					assert.Equal(t, 2, execute(invalidateTestKey{id: 2}).Value)
+
+					destroyFn()
+				}
+			},
+		},
+		{
+			desc: "all keys of the given type are re-executed",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel)
+
+					PopulateCache(ctx, map[interface{}]Outcome{
+						invalidateTestKey{id: 1}: {Value: 1},
+						invalidateTestKey{id: 2}: {Value: 2},
+						"other":                  {Value: 3},
+					})
+
+					InvalidateByKeyType[invalidateTestKey](ctx)
+
+					assert.Empty(t, FindOutcomes[invalidateTestKey, int](ctx, invalidateTestKey{}))
+					assert.Equal(t, map[interface{}]Outcome{"other": {Value: 3}}, FindAllOutcomes(ctx))
+
+					destroyFn()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestExecuteWithDeps(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "invalidation cascades to dependents transitively",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel)
+
+					calls := map[string]int{}
+					execute := func(key string, deps ...interface{}) int {
+						outcome, _ := ExecuteWithDeps(ctx, key, func(context.Context) (int, error) {
+							calls[key]++
+							return calls[key], nil
+						}, deps...)
+
+						return outcome.Value
+					}
+
+					execute("base")
+					execute("derived", "base")
+					execute("twice-derived", "derived")
+					execute("unrelated")
+
+					Invalidate(ctx, "base")
+
+					assert.Equal(t, 2, execute("base"))
+					assert.Equal(t, 2, execute("derived", "base"))
+					assert.Equal(t, 2, execute("twice-derived", "derived"))
+					assert.Equal(t, 1, execute("unrelated"))
+
+					Invalidate(ctx, "derived")
+
+					assert.Equal(t, 2, execute("base"))
+					assert.Equal(t, 3, execute("derived", "base"))
+					assert.Equal(t, 3, execute("twice-derived", "derived"))
+
+					destroyFn()
+				}
+			},
+		},
+		{
+			desc: "invalidation by key type cascades to dependents",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
+
+				calls := 0
+				fn := func(context.Context) (int, error) {
+					calls++
+					return calls, nil
+				}
+
+				Execute(ctx, invalidateTestKey{id: 1}, fn)
+				ExecuteWithDeps(ctx, "derived", fn, invalidateTestKey{id: 1})
+
+				InvalidateByKeyType[invalidateTestKey](ctx)
+
+				outcome, _ := Execute(ctx, "derived", fn)
+				assert.Equal(t, 3, outcome.Value)
+			},
+		},
+		{
+			desc: "cycles and invalid dependencies",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				calls := 0
+				fn := func(context.Context) (int, error) {
+					calls++
+					return calls, nil
+				}
+
+				ExecuteWithDeps(ctx, "a", fn, "b", "a", []int{1})
+				ExecuteWithDeps(ctx, "b", fn, "a")
+
+				assert.NotPanics(t, func() {
+					Invalidate(ctx, "a")
+				})
+
+				assert.Empty(t, FindAllOutcomes(ctx))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestNewTypedOutcome(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "outcome Value is nil",
+			test: func(t *testing.T) {
+				o := Outcome{}
+
+				actual := newTypedOutcome[int](o)
+				assert.Equal(t, 0, actual.Value)
+			},
+		},
+		{
+			desc: "outcome Value is not of expected type",
+			test: func(t *testing.T) {
+				o := Outcome{
+					Value: "string",
+				}
+
+				actual := newTypedOutcome[int](o)
+				assert.Equal(t, 0, actual.Value)
+			},
+		},
+		{
+			desc: "outcome Value is of expected type",
+			test: func(t *testing.T) {
+				o := Outcome{
+					Value: 1,
+				}
+
+				actual := newTypedOutcome[int](o)
+				assert.Equal(t, 1, actual.Value)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+type statsTestKey struct {
+	id int
+}
+
+func TestStats(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				assert.Equal(t, CacheStats{}, Stats(context.Background()))
+			},
+		},
+		{
+			desc: "hits, misses, panics and in-flight promises",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel)
+
+					PopulateCache(ctx, map[interface{}]Outcome{
+						statsTestKey{id: 1}: {Value: 1},
+					})
+
+					Execute(ctx, statsTestKey{id: 1}, func(context.Context) (int, error) {
+						return 1, nil
+					})
+
+					for i := 0; i < 2; i++ {
+						Execute(ctx, statsTestKey{id: 2}, func(context.Context) (int, error) {
+							return 2, nil
+						})
+					}
+
+					Execute(ctx, "panic", func(context.Context) (int, error) {
+						panic("test")
+					})
+
+					release := make(chan struct{})
+					started := make(chan struct{})
+					go Execute(ctx, "pending", func(context.Context) (int, error) {
+						close(started)
+						<-release
+						return 1, nil
+					})
+
+					<-started
+
+					expectedKeyTypeStats := KeyTypeStats{
+						Hits:          2,
+						Misses:        1,
+						PopulatedHits: 1,
+					}
+
+					stats := Stats(ctx)
+					assert.Equal(
+						t,
+						CacheStats{
+							KeyTypeStats: KeyTypeStats{
+								Hits:          2,
+								Misses:        3,
+								PopulatedHits: 1,
+								Panics:        1,
+								InFlight:      1,
+							},
+							ByKeyType: map[string]KeyTypeStats{
+								helper.NameOf(statsTestKey{}): expectedKeyTypeStats,
+								helper.NameOf(""): {
+									Misses:   2,
+									Panics:   1,
+									InFlight: 1,
+								},
+							},
+						},
+						stats,
+					)
+
+					close(release)
+					destroyFn()
+				}
 			},
 		},
 	}
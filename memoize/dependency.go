@@ -0,0 +1,151 @@
+package memoize
+
+import (
+	"sync"
+
+	"github.com/jamestrandung/go-context/helper"
+)
+
+// dependencyGraph records which executionKeys depend on which other ones so that
+// invalidating a key cascades to its dependents. It is shared by all shards of a
+// cache since dependents may live in other shards than their dependencies. A nil
+// dependencyGraph records nothing.
+type dependencyGraph struct {
+	mu sync.Mutex
+	// dependents maps an executionKey to the keys that depend on it.
+	dependents map[interface{}]map[interface{}]struct{}
+	// dependencies maps an executionKey to the keys it depends on.
+	dependencies map[interface{}]map[interface{}]struct{}
+}
+
+func newDependencyGraph() *dependencyGraph {
+	return &dependencyGraph{
+		dependents:   make(map[interface{}]map[interface{}]struct{}),
+		dependencies: make(map[interface{}]map[interface{}]struct{}),
+	}
+}
+
+// add records that the given executionKey depends on the given keys. Keys that
+// are not comparable are ignored.
+func (g *dependencyGraph) add(executionKey interface{}, dependencies []interface{}) {
+	if g == nil || len(dependencies) == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, dependency := range dependencies {
+		if !helper.TryComparable(dependency) || dependency == executionKey {
+			continue
+		}
+
+		link(g.dependents, dependency, executionKey)
+		link(g.dependencies, executionKey, dependency)
+	}
+}
+
+// cascade returns the given executionKeys along with all keys depending on them,
+// directly or transitively, and forgets the dependencies of the returned keys
+// since their outcomes are about to be invalidated.
+func (g *dependencyGraph) cascade(executionKeys ...interface{}) []interface{} {
+	if g == nil {
+		return executionKeys
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.doCascade(executionKeys)
+}
+
+// cascadeByKeyType returns all keys depending, directly or transitively, on any
+// executionKey of the given type and forgets their dependencies.
+func (g *dependencyGraph) cascadeByKeyType(executionKeyType string) []interface{} {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var roots []interface{}
+	for dependency := range g.dependents {
+		if helper.NameOf(dependency) == executionKeyType {
+			roots = append(roots, dependency)
+		}
+	}
+
+	return g.doCascade(roots)
+}
+
+func (g *dependencyGraph) doCascade(roots []interface{}) []interface{} {
+	visited := make(map[interface{}]struct{}, len(roots))
+	result := make([]interface{}, 0, len(roots))
+
+	queue := roots
+	for len(queue) > 0 {
+		executionKey := queue[0]
+		queue = queue[1:]
+
+		if !helper.TryComparable(executionKey) {
+			continue
+		}
+
+		if _, ok := visited[executionKey]; ok {
+			continue
+		}
+
+		visited[executionKey] = struct{}{}
+		result = append(result, executionKey)
+
+		for dependent := range g.dependents[executionKey] {
+			queue = append(queue, dependent)
+		}
+
+		delete(g.dependents, executionKey)
+
+		for dependency := range g.dependencies[executionKey] {
+			unlink(g.dependents, dependency, executionKey)
+		}
+
+		delete(g.dependencies, executionKey)
+	}
+
+	return result
+}
+
+// clear forgets all dependencies.
+func (g *dependencyGraph) clear() {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.dependents = make(map[interface{}]map[interface{}]struct{})
+	g.dependencies = make(map[interface{}]map[interface{}]struct{})
+}
+
+func link(m map[interface{}]map[interface{}]struct{}, from interface{}, to interface{}) {
+	set, ok := m[from]
+	if !ok {
+		set = make(map[interface{}]struct{})
+		m[from] = set
+	}
+
+	set[to] = struct{}{}
+}
+
+func unlink(m map[interface{}]map[interface{}]struct{}, from interface{}, to interface{}) {
+	set, ok := m[from]
+	if !ok {
+		return
+	}
+
+	delete(set, to)
+	if len(set) == 0 {
+		delete(m, from)
+	}
+}
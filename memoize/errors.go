@@ -1,11 +1,20 @@
 package memoize
 
 import (
+	"context"
 	"errors"
+
+	"github.com/jamestrandung/go-context/ctxerr"
 )
 
 var (
 	ErrPanicExecutingMemoizedFn = errors.New("panic executing memoizedFn")
-	ErrCacheAlreadyDestroyed    = errors.New("cache already destroyed, cannot be used anymore")
+	ErrCacheAlreadyDestroyed    = ctxerr.New(ctxerr.CacheDestroyed, "cache already destroyed, cannot be used anymore")
 	ErrMemoizedFnCannotBeNil    = errors.New("memoizedFn cannot be nil")
+	ErrExecutionPending         = errors.New("memoizedFn is being executed by another caller")
+	ErrExecutionTimedOut        = &ctxerr.Error{
+		Kind:    ctxerr.Cancelled,
+		Message: "memoizedFn timed out",
+		Cause:   context.DeadlineExceeded,
+	}
 )
@@ -0,0 +1,102 @@
+package memoize
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchOption configures a call to ExecuteBatch.
+type BatchOption func(*batchOptions)
+
+type batchOptions struct {
+	// maxConcurrency is the number of keys executed at the same time, unlimited
+	// if not positive.
+	maxConcurrency int
+}
+
+// WithMaxConcurrency bounds the number of keys ExecuteBatch executes at the same
+// time, e.g. to avoid overloading a downstream service.
+func WithMaxConcurrency(maxConcurrency int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxConcurrency = maxConcurrency
+	}
+}
+
+// ExecuteBatch calls Execute for each of the given keys concurrently and returns
+// their outcomes once all of them are available. Keys that were already memoized
+// reuse their outcomes while the others get executed by calling memoizedFn with
+// the key. Duplicate keys are executed only once.
+//
+// Note: if the given context gets cancelled, the outcomes of keys that are not
+// available yet carry the context error.
+func ExecuteBatch[K comparable, V any](
+	ctx context.Context,
+	keys []K,
+	memoizedFn func(context.Context, K) (V, error),
+	opts ...BatchOption,
+) map[K]TypedOutcome[V] {
+	var o batchOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	m := make(map[K]TypedOutcome[V], len(keys))
+	if len(keys) == 0 {
+		return m
+	}
+
+	var slots chan struct{}
+	if o.maxConcurrency > 0 {
+		slots = make(chan struct{}, o.maxConcurrency)
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	seen := make(map[K]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+
+		seen[key] = struct{}{}
+
+		var fn func(context.Context) (V, error)
+		if memoizedFn != nil {
+			k := key
+			fn = func(ctx context.Context) (V, error) {
+				return memoizedFn(ctx, k)
+			}
+		}
+
+		wg.Add(1)
+		go func(key K) {
+			defer wg.Done()
+
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					mu.Lock()
+					m[key] = TypedOutcome[V]{Err: ctx.Err()}
+					mu.Unlock()
+
+					return
+				}
+			}
+
+			outcome, _ := Execute(ctx, key, fn)
+
+			mu.Lock()
+			m[key] = outcome
+			mu.Unlock()
+		}(key)
+	}
+
+	wg.Wait()
+
+	return m
+}
@@ -0,0 +1,129 @@
+package memoize
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type batchTestKey struct {
+	id int
+}
+
+func TestExecuteBatch(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no keys",
+			test: func(t *testing.T) {
+				outcomes := ExecuteBatch(context.Background(), nil, func(context.Context, batchTestKey) (int, error) {
+					return 0, nil
+				})
+
+				assert.Empty(t, outcomes)
+			},
+		},
+		{
+			desc: "memoized keys are reused and duplicates executed once",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				PopulateCache(ctx, map[interface{}]Outcome{
+					batchTestKey{id: 1}: {Value: 100},
+				})
+
+				var calls int32
+				outcomes := ExecuteBatch(
+					ctx,
+					[]batchTestKey{{id: 1}, {id: 2}, {id: 3}, {id: 2}},
+					func(_ context.Context, key batchTestKey) (int, error) {
+						atomic.AddInt32(&calls, 1)
+						return key.id * 10, nil
+					},
+				)
+
+				assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+				assert.Equal(
+					t,
+					map[batchTestKey]TypedOutcome[int]{
+						{id: 1}: {Value: 100},
+						{id: 2}: {Value: 20},
+						{id: 3}: {Value: 30},
+					},
+					outcomes,
+				)
+
+				assert.Len(t, FindOutcomes[batchTestKey, int](ctx, batchTestKey{}), 3)
+			},
+		},
+		{
+			desc: "concurrency is bounded",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
+
+				keys := make([]batchTestKey, 20)
+				for i := range keys {
+					keys[i] = batchTestKey{id: i}
+				}
+
+				var running, maxRunning int32
+				outcomes := ExecuteBatch(
+					ctx,
+					keys,
+					func(_ context.Context, key batchTestKey) (int, error) {
+						current := atomic.AddInt32(&running, 1)
+						defer atomic.AddInt32(&running, -1)
+
+						for {
+							observed := atomic.LoadInt32(&maxRunning)
+							if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+								break
+							}
+						}
+
+						return key.id, nil
+					},
+					WithMaxConcurrency(3),
+				)
+
+				assert.Len(t, outcomes, 20)
+				assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+			},
+		},
+		{
+			desc: "cancelled context",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				cancelledCtx, cancel := context.WithCancel(ctx)
+				cancel()
+
+				outcomes := ExecuteBatch(
+					cancelledCtx,
+					[]batchTestKey{{id: 1}, {id: 2}},
+					func(context.Context, batchTestKey) (int, error) {
+						return 1, nil
+					},
+					WithMaxConcurrency(1),
+				)
+
+				for _, outcome := range outcomes {
+					assert.Equal(t, context.Canceled, outcome.Err)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
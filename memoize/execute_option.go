@@ -0,0 +1,71 @@
+package memoize
+
+import (
+	"time"
+)
+
+// executeOption configures a single execution.
+type executeOption func(*executeOptions)
+
+type executeOptions struct {
+	// ttl is how long the outcome stays memoized after it completes, forever if zero.
+	ttl time.Duration
+	// timeout is how long the memoizedFn may run, forever if zero.
+	timeout time.Duration
+	// maxAge is how old a completed outcome may get before it is refreshed in the
+	// background, never if zero.
+	maxAge time.Duration
+	// dependencies are the executionKeys whose invalidation cascades to the
+	// executed key.
+	dependencies []interface{}
+	// namespace is the namespace new promises are registered in, none if empty.
+	namespace string
+	// noWait makes the execution return ErrExecutionPending instead of waiting
+	// for the memoizedFn run by another caller.
+	noWait bool
+}
+
+func newExecuteOptions(opts ...executeOption) executeOptions {
+	var o executeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+func withTTL(ttl time.Duration) executeOption {
+	return func(o *executeOptions) {
+		o.ttl = ttl
+	}
+}
+
+func withTimeout(timeout time.Duration) executeOption {
+	return func(o *executeOptions) {
+		o.timeout = timeout
+	}
+}
+
+func withMaxAge(maxAge time.Duration) executeOption {
+	return func(o *executeOptions) {
+		o.maxAge = maxAge
+	}
+}
+
+func withDependencies(dependencies []interface{}) executeOption {
+	return func(o *executeOptions) {
+		o.dependencies = dependencies
+	}
+}
+
+func withNamespace(namespace string) executeOption {
+	return func(o *executeOptions) {
+		o.namespace = namespace
+	}
+}
+
+func withoutWaiting() executeOption {
+	return func(o *executeOptions) {
+		o.noWait = true
+	}
+}
@@ -0,0 +1,44 @@
+package memoize
+
+import (
+	"context"
+)
+
+// Cache is a handle to a cache for memoized functions, for call sites that cannot
+// thread a context.Context holding the cache, e.g. some third-party libraries. It
+// can still be attached to contexts so that the context-based functions of this
+// package, e.g. Execute, use the same cache.
+type Cache struct {
+	c iCache
+}
+
+// NewCache returns a new Cache configured with the given options. The given context
+// is the root context of this cache like the one given to WithCache. If it gets
+// cancelled, all pending memoized executions will be abandoned.
+//
+// Note: Destroy must be deferred to minimize memory leaks.
+func NewCache(rootCtx context.Context, opts ...Option) *Cache {
+	return &Cache{
+		c: newOptions(opts...).apply(newCache(rootCtx)),
+	}
+}
+
+// Attach returns a new context.Context derived from ctx that holds a reference to
+// this cache, similar to the one returned by WithCache.
+func (c *Cache) Attach(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoizeStoreKey, c.c)
+}
+
+// Execute works like the package-level Execute using this cache instead of the one
+// held by the given context, which is only used to wait for the outcome and to look
+// up values. Use the package-level Execute on a context returned by Attach to get
+// typed outcomes.
+func (c *Cache) Execute(ctx context.Context, executionKey interface{}, memoizedFn Function) (Outcome, Extra) {
+	return c.c.execute(ctx, executionKey, memoizedFn)
+}
+
+// Destroy clears this cache. Subsequent executions using this cache, including via
+// attached contexts, return ErrCacheAlreadyDestroyed.
+func (c *Cache) Destroy() {
+	c.c.destroy()
+}
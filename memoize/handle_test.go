@@ -0,0 +1,56 @@
+package memoize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "handle and attached contexts share the same cache",
+			test: func(t *testing.T) {
+				c := NewCache(context.Background())
+				defer c.Destroy()
+
+				outcome, extra := c.Execute(context.Background(), "key", func(context.Context) (interface{}, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, Outcome{Value: 1}, outcome)
+				assert.True(t, extra.IsMemoized)
+				assert.True(t, extra.IsExecuted)
+
+				typedOutcome, _ := Execute(c.Attach(context.Background()), "key", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.Equal(t, TypedOutcome[int]{Value: 1}, typedOutcome)
+			},
+		},
+		{
+			desc: "destroyed cache",
+			test: func(t *testing.T) {
+				c := NewCache(context.Background())
+				c.Destroy()
+
+				outcome, _ := c.Execute(context.Background(), "key", func(context.Context) (interface{}, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, ErrCacheAlreadyDestroyed, outcome.Err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
@@ -71,3 +71,22 @@ func BenchmarkStore_Promise(b *testing.B) {
 
 	wg.Wait()
 }
+
+func BenchmarkStore_PromiseHotKey(b *testing.B) {
+	c := newCache(context.Background())
+
+	fn := func(context.Context) (interface{}, error) {
+		return 1, nil
+	}
+
+	p, _ := c.promise("key", fn)
+	p.get(context.Background())
+
+	b.RunParallel(
+		func(pb *testing.PB) {
+			for pb.Next() {
+				c.promise("key", fn)
+			}
+		},
+	)
+}
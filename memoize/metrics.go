@@ -0,0 +1,31 @@
+package memoize
+
+import (
+	"time"
+)
+
+// MetricsReporter receives the hits, misses and executions of a cache so that its
+// effectiveness can be shipped to a metrics backend, e.g. Prometheus or Datadog.
+// The keyType is the type of the executionKey, e.g. "memoize.userKey".
+//
+// Note: callbacks are invoked synchronously while executing memoized functions, so
+// they must be fast and must not use the cache themselves.
+//
+//go:generate mockery --name MetricsReporter --case underscore --inpkg
+type MetricsReporter interface {
+	// OnHit is called when Execute is served by an existing promise.
+	OnHit(keyType string)
+	// OnMiss is called when Execute creates a new promise.
+	OnMiss(keyType string)
+	// OnExecutionDone is called after a memoized function returns with how long
+	// it took and the error it returned, if any.
+	OnExecutionDone(keyType string, duration time.Duration, err error)
+}
+
+// WithMetricsReporter makes the cache report its hits, misses and executions to the
+// given MetricsReporter.
+func WithMetricsReporter(r MetricsReporter) Option {
+	return func(o *options) {
+		o.metricsReporter = r
+	}
+}
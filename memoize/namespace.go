@@ -0,0 +1,119 @@
+package memoize
+
+import (
+	"context"
+)
+
+type namespaceContextKey struct{}
+
+var namespaceKey = namespaceContextKey{}
+
+// WithNamespace returns a new context.Context derived from ctx so that promises
+// created by executions using it are registered in the given namespace. Keys of a
+// namespace can then be enumerated via FindNamespaceOutcomes or destroyed as a unit
+// via DestroyNamespace while leaving the rest of the cache intact.
+//
+// Namespaces don't isolate executionKeys: calling Execute with a key that already
+// has a promise hits this promise regardless of the namespace it was registered in.
+// A promise belongs to the namespace of the context that created it.
+func WithNamespace(ctx context.Context, namespace string) context.Context {
+	return context.WithValue(ctx, namespaceKey, namespace)
+}
+
+// namespaceOf returns the namespace of the given context, empty if none.
+func namespaceOf(ctx context.Context) string {
+	namespace, _ := ctx.Value(namespaceKey).(string)
+	return namespace
+}
+
+// withNamespaceOf returns the given executeOptions along with one registering new
+// promises in the namespace of the given context, if any.
+func withNamespaceOf(ctx context.Context, opts []executeOption) []executeOption {
+	namespace := namespaceOf(ctx)
+	if namespace == "" {
+		return opts
+	}
+
+	// Copy the given options to avoid modifying the slice of the caller
+	return append(opts[:len(opts):len(opts)], withNamespace(namespace))
+}
+
+// FindNamespaceOutcomes returns all Outcome that were memoized in the given
+// namespace at the time FindNamespaceOutcomes was called. If a promise is still
+// pending, the function will block & wait for it to complete to get its Outcome.
+//
+// Note: this function can only return memoized Outcome if the given context has
+// been initialized using WithCache.
+func FindNamespaceOutcomes(ctx context.Context, namespace string) map[interface{}]Outcome {
+	c := extractCache(ctx)
+
+	promises := c.findPromises(nil)
+	if promises == nil {
+		return nil
+	}
+
+	m := make(map[interface{}]Outcome)
+	for key, p := range promises {
+		if p.namespace != namespace {
+			continue
+		}
+
+		// Check if context was cancelled while we were waiting
+		// for the previous promise.
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Wait for the result
+		m[key] = p.get(ctx)
+	}
+
+	return m
+}
+
+// DestroyNamespace removes all outcomes memoized in the given namespace so that
+// the next call to Execute with their keys invokes their memoizedFn again, while
+// the other outcomes of the cache stay intact. Like Invalidate, it cascades to the
+// keys that were executed depending on them via ExecuteWithDeps.
+//
+// Note: callers already waiting for a pending execution in this namespace will
+// still receive its outcome.
+func DestroyNamespace(ctx context.Context, namespace string) {
+	c := extractCache(ctx)
+	c.invalidateNamespace(namespace)
+}
+
+func (c *cache) invalidateNamespace(namespace string) {
+	c.invalidateKeys(c.dependencies.cascade(c.invalidateKeysOfNamespace(namespace)...))
+}
+
+// invalidateKeysOfNamespace removes the promises registered in the given namespace
+// without cascading to their dependents and returns their executionKeys.
+func (c *cache) invalidateKeysOfNamespace(namespace string) []interface{} {
+	c.lock()
+	defer c.promisesMu.Unlock()
+
+	var executionKeys []interface{}
+	for executionKey, p := range c.promises {
+		if p.namespace == namespace {
+			c.remove(executionKey)
+			executionKeys = append(executionKeys, executionKey)
+		}
+	}
+
+	return executionKeys
+}
+
+func (c concurrentCache) invalidateNamespace(namespace string) {
+	var executionKeys []interface{}
+	for _, shard := range c {
+		executionKeys = append(executionKeys, shard.invalidateKeysOfNamespace(namespace)...)
+	}
+
+	// All shards share the same dependencies
+	c.invalidateKeys(c[0].dependencies.cascade(executionKeys...))
+}
+
+func (c *noMemoizeCache) invalidateNamespace(namespace string) {
+	// do nothing
+}
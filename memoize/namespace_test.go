@@ -0,0 +1,120 @@
+package memoize
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamespace(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "without cache",
+			test: func(t *testing.T) {
+				ctx := WithNamespace(context.Background(), "pricing")
+
+				assert.Nil(t, FindNamespaceOutcomes(ctx, "pricing"))
+				assert.NotPanics(t, func() {
+					DestroyNamespace(ctx, "pricing")
+				})
+			},
+		},
+		{
+			desc: "namespaces are enumerated & destroyed as a unit",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel)
+
+					pricingCtx := WithNamespace(ctx, "pricing")
+					shippingCtx := WithNamespace(ctx, "shipping")
+
+					calls := 0
+					execute := func(ctx context.Context, key string) int {
+						outcome, _ := Execute(ctx, key, func(context.Context) (int, error) {
+							calls++
+							return calls, nil
+						})
+
+						return outcome.Value
+					}
+
+					execute(pricingCtx, "price-1")
+					execute(pricingCtx, "price-2")
+					execute(shippingCtx, "shipping-1")
+					execute(ctx, "other")
+
+					assert.Equal(
+						t,
+						map[interface{}]Outcome{
+							"price-1": {Value: 1},
+							"price-2": {Value: 2},
+						},
+						FindNamespaceOutcomes(ctx, "pricing"),
+					)
+
+					DestroyNamespace(ctx, "pricing")
+
+					assert.Empty(t, FindNamespaceOutcomes(ctx, "pricing"))
+					assert.Equal(
+						t,
+						map[interface{}]Outcome{
+							"shipping-1": {Value: 3},
+							"other":      {Value: 4},
+						},
+						FindAllOutcomes(ctx),
+					)
+
+					assert.Equal(t, 5, execute(pricingCtx, "price-1"))
+
+					destroyFn()
+				}
+			},
+		},
+		{
+			desc: "existing promises are hit regardless of their namespace",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				Execute(ctx, "key", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				outcome, _ := Execute(WithNamespace(ctx, "pricing"), "key", func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				assert.Equal(t, 1, outcome.Value)
+				assert.Empty(t, FindNamespaceOutcomes(ctx, "pricing"))
+			},
+		},
+		{
+			desc: "destroying a namespace cascades to dependents",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4)
+				defer destroyFn()
+
+				fn := func(context.Context) (int, error) {
+					return 1, nil
+				}
+
+				Execute(WithNamespace(ctx, "pricing"), "price", fn)
+				ExecuteWithDeps(ctx, "total", fn, "price")
+
+				DestroyNamespace(ctx, "pricing")
+
+				assert.Empty(t, FindAllOutcomes(ctx))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
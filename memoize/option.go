@@ -0,0 +1,194 @@
+package memoize
+
+import (
+	"time"
+
+	"github.com/jamestrandung/go-context/ctxpool"
+	"github.com/jamestrandung/go-context/instrument"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Option configures the caches created by WithCache and WithConcurrentCache.
+type Option func(*options)
+
+type options struct {
+	// instrumentation receives the spans & metrics of executions, if not nil.
+	instrumentation instrument.Instrumentation
+	// pool runs memoized functions, if not nil.
+	pool *ctxpool.Pool
+	// maxEntries caps the number of promises in the cache, if positive.
+	maxEntries int
+	// defaultTTL is how long outcomes stay memoized unless executed with an
+	// explicit TTL, forever if zero.
+	defaultTTL time.Duration
+	// metricsReporter receives hits, misses & executions, if not nil.
+	metricsReporter MetricsReporter
+	// tracerProvider provides the Tracer starting a span for every execution, if
+	// not nil.
+	tracerProvider trace.TracerProvider
+	// executionLimit is the number of memoized functions allowed to run at the
+	// same time, unlimited if not positive.
+	executionLimit int
+	// isRetryable reports errors that should not be memoized, if not nil.
+	isRetryable func(error) bool
+	// panics determines what happens when a memoized function panics.
+	panics panicPolicy
+	// backend is consulted before executing memoized functions, if not nil.
+	backend Backend
+	// hasher assigns executionKeys to shards, if not nil.
+	hasher func(executionKey interface{}) uint64
+}
+
+func newOptions(opts ...Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithInstrumentation makes the cache report a span, a counter & a histogram of its
+// duration for every execution to the given Instrumentation instead of the default
+// one set by instrument.SetDefault.
+func WithInstrumentation(i instrument.Instrumentation) Option {
+	return func(o *options) {
+		o.instrumentation = i
+	}
+}
+
+// WithPool makes the cache run memoized functions on the given Pool instead of on a
+// new goroutine each, which bounds the number of memoized functions running at the
+// same time. If the pool is busy, callers wait for a goroutine to become available
+// like they would wait for the outcome. If the pool gets closed, executions that
+// could not be scheduled yet fail with ctxpool.ErrPoolClosed.
+//
+// Executions nested in a memoized function that already runs on this pool run inline
+// on its goroutine instead, so that they never wait for another goroutine of a busy
+// pool while holding one.
+func WithPool(pool *ctxpool.Pool) Option {
+	return func(o *options) {
+		o.pool = pool
+	}
+}
+
+// WithMaxEntries caps the number of outcomes the cache holds so that requests fanning
+// out over a huge number of keys don't exhaust memory. Once the cap is exceeded, the
+// least recently used outcomes get evicted, and executing their keys again re-executes
+// their memoized functions. Pending executions are never evicted. For concurrent
+// caches, the cap is split evenly across shards, so it is approximate.
+func WithMaxEntries(maxEntries int) Option {
+	return func(o *options) {
+		o.maxEntries = maxEntries
+	}
+}
+
+// WithDefaultTTL makes outcomes stay memoized for the given duration after their
+// memoized functions complete, like ExecuteWithTTL does, unless they are executed with
+// another TTL. This suits caches of long-lived contexts, e.g. those of background
+// workers, in which no outcome should be served forever.
+func WithDefaultTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.defaultTTL = ttl
+	}
+}
+
+// WithTracerProvider makes the cache start an OpenTelemetry span named after the type
+// of the executionKey for every execution, using the Tracer named TracerName of the
+// given TracerProvider. Spans are children of the span active in the context given to
+// Execute and carry whether the outcome was memoized and executed.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
+// WithExecutionLimit bounds the number of memoized functions running at the same time
+// in the cache to the given limit, across all shards of a concurrent cache. Executions
+// beyond this limit queue until a running one completes, while their callers can still
+// give up waiting by cancelling the context given to Execute. If the root context of
+// the cache gets cancelled, queued executions fail with its error.
+//
+// Executions nested in a running memoized function, e.g. via ExecuteWithDeps, bypass
+// the limit instead of queueing, since their caller keeps its slot while waiting for
+// them. Hence, the limit bounds the number of top-level executions running at the
+// same time rather than the number of memoized functions.
+func WithExecutionLimit(limit int) Option {
+	return func(o *options) {
+		o.executionLimit = limit
+	}
+}
+
+// WithErrorPolicy makes the cache drop the outcome of a memoized function that returned
+// an error for which isRetryable returns true, e.g. a context deadline or a 5xx response.
+// Callers already waiting for this execution still receive the error, but the next call
+// to Execute with the same executionKey runs the memoized function again instead of
+// serving the transient failure for the life of the cache.
+func WithErrorPolicy(isRetryable func(error) bool) Option {
+	return func(o *options) {
+		o.isRetryable = isRetryable
+	}
+}
+
+// WithHasher makes a concurrent cache assign executionKeys to its shards using the given
+// hash function instead of hashing them via reflection, which is slower and assigns all
+// keys that cannot be hashed to the first shard. The function must be deterministic,
+// i.e. return the same hash for equal keys, and should spread keys evenly.
+func WithHasher(hasher func(executionKey interface{}) uint64) Option {
+	return func(o *options) {
+		o.hasher = hasher
+	}
+}
+
+// configure applies these options to the given shard.
+func (o options) configure(c *cache, shardCount int, slots chan struct{}, dependencies *dependencyGraph) {
+	c.pool = o.pool
+	c.slots = slots
+	c.dependencies = dependencies
+	c.isRetryable = o.isRetryable
+	c.panics = o.panics
+	c.backend = o.backend
+	c.hasher = o.hasher
+	c.defaultTTL = o.defaultTTL
+	c.statsRecorder.reporter = o.metricsReporter
+
+	if o.maxEntries > 0 {
+		c.maxEntries = (o.maxEntries + shardCount - 1) / shardCount
+	}
+}
+
+// apply returns the given cache configured & decorated according to these options.
+func (o options) apply(c iCache) iCache {
+	var slots chan struct{}
+	if o.executionLimit > 0 {
+		slots = make(chan struct{}, o.executionLimit)
+	}
+
+	dependencies := newDependencyGraph()
+
+	switch cc := c.(type) {
+	case *cache:
+		o.configure(cc, 1, slots, dependencies)
+	case concurrentCache:
+		for _, shard := range cc {
+			o.configure(shard, len(cc), slots, dependencies)
+		}
+	}
+
+	if o.tracerProvider != nil {
+		c = tracedCache{
+			iCache: c,
+			tracer: o.tracerProvider.Tracer(TracerName),
+		}
+	}
+
+	i := instrument.OrDefault(o.instrumentation)
+	if instrument.IsNoop(i) {
+		return c
+	}
+
+	return instrumentedCache{
+		iCache:          c,
+		instrumentation: i,
+	}
+}
@@ -0,0 +1,459 @@
+package memoize
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jamestrandung/go-context/ctxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type poolTestKey struct {
+	id int
+}
+
+func TestWithPool(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "memoized functions run on the pool",
+			test: func(t *testing.T) {
+				pool := ctxpool.New(2)
+				defer pool.Close()
+
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4, WithPool(pool))
+				defer destroyFn()
+
+				var evaled int32
+
+				var wg sync.WaitGroup
+				for i := 0; i < 100; i++ {
+					wg.Add(1)
+
+					go func(i int) {
+						defer wg.Done()
+
+						outcome, extra := Execute(ctx, poolTestKey{id: i % 10}, func(context.Context) (int, error) {
+							atomic.AddInt32(&evaled, 1)
+							return i % 10, nil
+						})
+
+						assert.Equal(t, i%10, outcome.Value)
+						assert.Nil(t, outcome.Err)
+						assert.True(t, extra.IsMemoized)
+					}(i)
+				}
+
+				wg.Wait()
+
+				assert.Equal(t, int32(10), evaled)
+			},
+		},
+		{
+			desc: "nested executions on a busy pool",
+			test: func(t *testing.T) {
+				pool := ctxpool.New(1)
+				defer pool.Close()
+
+				rootCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				ctx, destroyFn := WithCache(rootCtx, WithPool(pool))
+				defer destroyFn()
+
+				outcome, _ := Execute(ctx, poolTestKey{id: 1}, func(ctx context.Context) (int, error) {
+					inner, _ := Execute(ctx, poolTestKey{id: 2}, func(context.Context) (int, error) {
+						return 2, nil
+					})
+
+					return inner.Value + 1, inner.Err
+				})
+
+				assert.Nil(t, outcome.Err)
+				assert.Equal(t, 3, outcome.Value)
+			},
+		},
+		{
+			desc: "closed pool",
+			test: func(t *testing.T) {
+				pool := ctxpool.New(1)
+				pool.Close()
+
+				ctx, destroyFn := WithCache(context.Background(), WithPool(pool))
+				defer destroyFn()
+
+				outcome, _ := Execute(ctx, poolTestKey{}, func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.Equal(t, ctxpool.ErrPoolClosed, outcome.Err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestWithMaxEntries(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "least recently used outcomes are evicted",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background(), WithMaxEntries(2))
+				defer destroyFn()
+
+				calls := make(map[string]int)
+				execute := func(key string) Extra {
+					_, extra := Execute(ctx, key, func(context.Context) (int, error) {
+						calls[key]++
+						return calls[key], nil
+					})
+
+					return extra
+				}
+
+				execute("a")
+				execute("b")
+				execute("a")
+				execute("c")
+
+				assert.Len(t, FindAllOutcomes(ctx), 2)
+
+				execute("a")
+				assert.Equal(t, 1, calls["a"], "a was used recently and must not be evicted")
+
+				execute("b")
+				assert.Equal(t, 2, calls["b"], "b was evicted and must be re-executed")
+			},
+		},
+		{
+			desc: "pending promises are never evicted",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background(), WithMaxEntries(1))
+				defer destroyFn()
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(ctx, "pending", func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 1, nil
+				})
+
+				<-started
+
+				PopulateCache(ctx, map[interface{}]Outcome{
+					"populated": {Value: 1},
+				})
+
+				pending, completed := CountPromises(ctx)
+				assert.Equal(t, 1, pending)
+				assert.Equal(t, 0, completed)
+
+				close(release)
+			},
+		},
+		{
+			desc: "cap is split across shards",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4, WithMaxEntries(10))
+				defer destroyFn()
+
+				for _, shard := range extractCache(ctx).(concurrentCache) {
+					assert.Equal(t, 3, shard.maxEntries)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestWithMetricsReporter(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "hits, misses and executions are reported",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					reporter := &MockMetricsReporter{}
+					reporter.On("OnMiss", "string").Return().Once()
+					reporter.On("OnHit", "string").Return().Once()
+					reporter.On("OnExecutionDone", "string", mock.AnythingOfType("time.Duration"), assert.AnError).Return().Once()
+
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel, WithMetricsReporter(reporter))
+
+					for i := 0; i < 2; i++ {
+						Execute(ctx, "key", func(context.Context) (int, error) {
+							return 0, assert.AnError
+						})
+					}
+
+					reporter.AssertExpectations(t)
+					destroyFn()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestWithDefaultTTL(t *testing.T) {
+	clock := useFakeClock(t)
+
+	ctx, destroyFn := WithCache(context.Background(), WithDefaultTTL(time.Minute))
+	defer destroyFn()
+
+	calls := 0
+	memoizedFn := func(context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	Execute(ctx, "default", memoizedFn)
+	ExecuteWithTTL(ctx, "explicit", memoizedFn, time.Hour)
+
+	clock.add(time.Minute)
+
+	outcome, _ := Execute(ctx, "default", memoizedFn)
+	assert.Equal(t, 3, outcome.Value, "outcomes executed without a TTL must expire")
+
+	outcome, _ = ExecuteWithTTL(ctx, "explicit", memoizedFn, time.Hour)
+	assert.Equal(t, 2, outcome.Value, "explicit TTLs must take precedence")
+}
+
+func TestWithExecutionLimit(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "running executions are bounded across shards",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel, WithExecutionLimit(2))
+
+					var running, maxRunning int32
+					memoizedFn := func(context.Context) (int, error) {
+						current := atomic.AddInt32(&running, 1)
+						defer atomic.AddInt32(&running, -1)
+
+						for {
+							observed := atomic.LoadInt32(&maxRunning)
+							if current <= observed || atomic.CompareAndSwapInt32(&maxRunning, observed, current) {
+								break
+							}
+						}
+
+						time.Sleep(time.Millisecond)
+
+						return 1, nil
+					}
+
+					var wg sync.WaitGroup
+					for i := 0; i < 10; i++ {
+						wg.Add(1)
+						go func(i int) {
+							defer wg.Done()
+
+							outcome, _ := Execute(ctx, poolTestKey{id: i}, memoizedFn)
+							assert.Equal(t, 1, outcome.Value)
+						}(i)
+					}
+
+					wg.Wait()
+
+					assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(2))
+					destroyFn()
+				}
+			},
+		},
+		{
+			desc: "nested executions bypass the limit",
+			test: func(t *testing.T) {
+				rootCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+
+				ctx, destroyFn := WithConcurrentCache(rootCtx, 4, WithExecutionLimit(1))
+				defer destroyFn()
+
+				var nestedFn func(depth int) Function
+				nestedFn = func(depth int) Function {
+					return func(ctx context.Context) (interface{}, error) {
+						if depth == 0 {
+							return 0, nil
+						}
+
+						outcome, _ := Execute(ctx, poolTestKey{id: depth - 1}, nestedFn(depth-1))
+						if outcome.Err != nil {
+							return nil, outcome.Err
+						}
+
+						return outcome.Value.(int) + 1, nil
+					}
+				}
+
+				outcome, _ := Execute(ctx, poolTestKey{id: 3}, nestedFn(3))
+				assert.Nil(t, outcome.Err)
+				assert.Equal(t, 3, outcome.Value)
+			},
+		},
+		{
+			desc: "queued executions fail once the root context is cancelled",
+			test: func(t *testing.T) {
+				rootCtx, cancel := context.WithCancel(context.Background())
+
+				ctx, destroyFn := WithCache(rootCtx, WithExecutionLimit(1))
+				defer destroyFn()
+
+				release := make(chan struct{})
+				started := make(chan struct{})
+				go Execute(ctx, poolTestKey{id: 1}, func(context.Context) (int, error) {
+					close(started)
+					<-release
+					return 1, nil
+				})
+
+				<-started
+
+				go Execute(ctx, poolTestKey{id: 2}, func(context.Context) (int, error) {
+					return 2, nil
+				})
+
+				var queued *promise
+				assert.Eventually(t, func() bool {
+					queued = extractCache(ctx).findPromises(poolTestKey{})[poolTestKey{id: 2}]
+					return queued != nil
+				}, time.Second, time.Millisecond)
+
+				cancel()
+				<-queued.done
+				close(release)
+
+				assert.Equal(t, context.Canceled, queued.outcome.Err)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestWithErrorPolicy(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "retryable errors are not memoized",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					isRetryable := func(err error) bool {
+						return errors.Is(err, context.DeadlineExceeded)
+					}
+
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel, WithErrorPolicy(isRetryable))
+
+					calls := 0
+					execute := func(key poolTestKey, err error) TypedOutcome[int] {
+						outcome, _ := Execute(ctx, key, func(context.Context) (int, error) {
+							calls++
+							return calls, err
+						})
+
+						return outcome
+					}
+
+					assert.Equal(t, context.DeadlineExceeded, execute(poolTestKey{id: 1}, context.DeadlineExceeded).Err)
+					assert.Empty(t, FindAllOutcomes(ctx))
+					assert.Equal(t, TypedOutcome[int]{Value: 2}, execute(poolTestKey{id: 1}, nil))
+					assert.Equal(t, TypedOutcome[int]{Value: 2}, execute(poolTestKey{id: 1}, nil))
+
+					assert.Equal(t, assert.AnError, execute(poolTestKey{id: 2}, assert.AnError).Err)
+					assert.Equal(t, TypedOutcome[int]{Value: 3, Err: assert.AnError}, execute(poolTestKey{id: 2}, nil))
+
+					destroyFn()
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestWithHasher(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "keys are assigned to shards using the hasher",
+			test: func(t *testing.T) {
+				hasher := func(executionKey interface{}) uint64 {
+					return uint64(executionKey.(poolTestKey).id)
+				}
+
+				ctx, destroyFn := WithConcurrentCache(context.Background(), 4, WithHasher(hasher))
+				defer destroyFn()
+
+				for i := 0; i < 8; i++ {
+					Execute(ctx, poolTestKey{id: i}, func(context.Context) (int, error) {
+						return i, nil
+					})
+				}
+
+				c := extractCache(ctx).(concurrentCache)
+				for idx, shard := range c {
+					assert.Len(t, shard.promises, 2)
+					assert.Contains(t, shard.promises, poolTestKey{id: idx})
+					assert.Contains(t, shard.promises, poolTestKey{id: idx + 4})
+				}
+
+				outcome, extra := Execute(ctx, poolTestKey{id: 5}, func(context.Context) (int, error) {
+					return -1, nil
+				})
+
+				assert.Equal(t, TypedOutcome[int]{Value: 5}, outcome)
+				assert.True(t, extra.IsExecuted)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
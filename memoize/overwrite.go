@@ -0,0 +1,73 @@
+package memoize
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jamestrandung/go-context/dvow"
+)
+
+// overwrittenKey is the effective execution key used by ExecuteWithOverwrites.
+// It pairs the caller's executionKey with a canonical encoding of the current
+// values of the variables that affect the computation.
+type overwrittenKey[K comparable] struct {
+	Key        K
+	Overwrites string
+}
+
+// ExecuteWithOverwrites works like Execute but the given variableNames declare
+// which dvow variables affect the memoizedFn. The current values of these variables
+// in the given context are folded into the effective execution key, so that calls
+// made with different overwrites do not share the same memoized outcome.
+//
+// Note: outcomes memoized via this function are stored under a different key type
+// and hence will not be returned by FindOutcomes for the executionKey type. They
+// are still included in FindAllOutcomes.
+func ExecuteWithOverwrites[K comparable, V any](
+	ctx context.Context,
+	executionKey K,
+	variableNames []string,
+	memoizedFn func(context.Context) (V, error),
+) (TypedOutcome[V], Extra) {
+	return Execute(
+		ctx,
+		overwrittenKey[K]{
+			Key:        executionKey,
+			Overwrites: encodeOverwrites(ctx, variableNames),
+		},
+		memoizedFn,
+	)
+}
+
+// encodeOverwrites returns a canonical string representing the current values of
+// the given variables in ctx. Variables that are not overwritten are encoded
+// differently from variables overwritten with nil.
+func encodeOverwrites(ctx context.Context, variableNames []string) string {
+	if len(variableNames) == 0 {
+		return ""
+	}
+
+	names := make([]string, len(variableNames))
+	copy(names, variableNames)
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for idx, name := range names {
+		if idx > 0 && name == names[idx-1] {
+			continue
+		}
+
+		value := dvow.Ops.GetOverwrittenValue(ctx, name)
+		if value == nil {
+			fmt.Fprintf(&sb, "%q;", name)
+			continue
+		}
+
+		v := value.AsIs()
+		fmt.Fprintf(&sb, "%q=%T:%#v;", name, v, v)
+	}
+
+	return sb.String()
+}
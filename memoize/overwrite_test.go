@@ -0,0 +1,118 @@
+package memoize
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jamestrandung/go-context/dvow"
+	"github.com/stretchr/testify/assert"
+)
+
+type overwriteTestKey struct{}
+
+func TestExecuteWithOverwrites(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "same overwrites share the memoized outcome",
+			test: func(t *testing.T) {
+				var evaled int32 = 0
+
+				memoizedFn := func(ctx context.Context) (int64, error) {
+					atomic.AddInt32(&evaled, 1)
+					return dvow.GetOverwrittenValue(ctx, "multiplier").AsInt(), nil
+				}
+
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				ctx = dvow.WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"multiplier": 2,
+						"unrelated":  "a",
+					},
+				)
+
+				outcome, extra := ExecuteWithOverwrites(ctx, overwriteTestKey{}, []string{"multiplier"}, memoizedFn)
+				assert.Equal(t, int64(2), outcome.Value)
+				assert.True(t, extra.IsMemoized)
+
+				// Changing a variable that was not declared must not affect the key
+				childCtx := dvow.WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"unrelated": "b",
+					},
+				)
+
+				outcome, _ = ExecuteWithOverwrites(childCtx, overwriteTestKey{}, []string{"multiplier"}, memoizedFn)
+				assert.Equal(t, int64(2), outcome.Value)
+				assert.Equal(t, int32(1), evaled)
+			},
+		},
+		{
+			desc: "different overwrites produce different outcomes",
+			test: func(t *testing.T) {
+				var evaled int32 = 0
+
+				memoizedFn := func(ctx context.Context) (int64, error) {
+					atomic.AddInt32(&evaled, 1)
+					return dvow.GetOverwrittenValue(ctx, "multiplier").AsInt(), nil
+				}
+
+				ctx, destroyFn := WithCache(context.Background())
+				defer destroyFn()
+
+				outcome, _ := ExecuteWithOverwrites(ctx, overwriteTestKey{}, []string{"multiplier"}, memoizedFn)
+				assert.Equal(t, int64(0), outcome.Value)
+
+				nilCtx := dvow.WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"multiplier": nil,
+					},
+				)
+
+				outcome, _ = ExecuteWithOverwrites(nilCtx, overwriteTestKey{}, []string{"multiplier"}, memoizedFn)
+				assert.Equal(t, int64(0), outcome.Value)
+
+				overwrittenCtx := dvow.WithOverwrittenVariables(
+					ctx, map[string]interface{}{
+						"multiplier": 3,
+					},
+				)
+
+				outcome, _ = ExecuteWithOverwrites(overwrittenCtx, overwriteTestKey{}, []string{"multiplier"}, memoizedFn)
+				assert.Equal(t, int64(3), outcome.Value)
+				assert.Equal(t, int32(3), evaled)
+
+				// Plain Execute with the same key is unaffected
+				outcome, extra := Execute(overwrittenCtx, overwriteTestKey{}, memoizedFn)
+				assert.Equal(t, int64(3), outcome.Value)
+				assert.True(t, extra.IsExecuted)
+				assert.Equal(t, int32(4), evaled)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestEncodeOverwrites(t *testing.T) {
+	ctx := dvow.WithOverwrittenVariables(
+		context.Background(), map[string]interface{}{
+			"a": 1,
+			"b": "1",
+		},
+	)
+
+	assert.Equal(t, "", encodeOverwrites(ctx, nil))
+	assert.Equal(t, encodeOverwrites(ctx, []string{"a", "b"}), encodeOverwrites(ctx, []string{"b", "a", "b"}))
+	assert.NotEqual(t, encodeOverwrites(ctx, []string{"a"}), encodeOverwrites(ctx, []string{"b"}))
+	assert.NotEqual(t, encodeOverwrites(ctx, []string{"c"}), encodeOverwrites(ctx, nil))
+}
@@ -0,0 +1,52 @@
+package memoize
+
+// PanicHandler receives the value recovered from a memoized function that panicked
+// along with its executionKey and the stack trace of the panicking goroutine.
+type PanicHandler func(executionKey interface{}, recovered interface{}, stack []byte)
+
+// WithPanicHandler makes the cache report panics of memoized functions to the given
+// PanicHandler, e.g. to send them to a crash reporter. Panics are still converted into
+// ErrPanicExecutingMemoizedFn unless WithPanicPropagation is given too.
+func WithPanicHandler(handler PanicHandler) Option {
+	return func(o *options) {
+		o.panics.handler = handler
+	}
+}
+
+// WithPanicPropagation makes the caller of Execute that triggered a memoized function
+// panic with the recovered value if this function panics, instead of receiving
+// ErrPanicExecutingMemoizedFn. Other callers waiting for the same execution still
+// receive ErrPanicExecutingMemoizedFn.
+func WithPanicPropagation() Option {
+	return func(o *options) {
+		o.panics.propagate = true
+	}
+}
+
+// panicPolicy determines what happens when a memoized function panics.
+type panicPolicy struct {
+	// handler is notified of panics, if not nil.
+	handler PanicHandler
+	// propagate makes the caller triggering an execution re-panic.
+	propagate bool
+}
+
+// recoveredPanic is the value recovered from a memoized function that panicked.
+type recoveredPanic struct {
+	value interface{}
+}
+
+// onPanic returns the callback given to doExecute when executing the memoized
+// function under the given executionKey, which records the recovered value in
+// recovered, if not nil.
+func (pp panicPolicy) onPanic(executionKey interface{}, recovered **recoveredPanic) func(interface{}, []byte) {
+	return func(value interface{}, stack []byte) {
+		if pp.handler != nil {
+			pp.handler(executionKey, value, stack)
+		}
+
+		if recovered != nil {
+			*recovered = &recoveredPanic{value: value}
+		}
+	}
+}
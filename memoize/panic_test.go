@@ -0,0 +1,125 @@
+package memoize
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPanicHandler(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "panics are reported to the handler",
+			test: func(t *testing.T) {
+				for _, concurrencyLevel := range []int{1, 4} {
+					var (
+						mu        sync.Mutex
+						reported  []interface{}
+						recovered []interface{}
+					)
+
+					handler := func(executionKey interface{}, value interface{}, stack []byte) {
+						mu.Lock()
+						defer mu.Unlock()
+
+						reported = append(reported, executionKey)
+						recovered = append(recovered, value)
+						assert.NotEmpty(t, stack)
+					}
+
+					ctx, destroyFn := WithConcurrentCache(context.Background(), concurrencyLevel, WithPanicHandler(handler))
+
+					outcome, _ := Execute(ctx, "key", func(context.Context) (int, error) {
+						panic("boom")
+					})
+
+					assert.True(t, errors.Is(outcome.Err, ErrPanicExecutingMemoizedFn))
+
+					mu.Lock()
+					assert.Equal(t, []interface{}{"key"}, reported)
+					assert.Equal(t, []interface{}{"boom"}, recovered)
+					mu.Unlock()
+
+					destroyFn()
+				}
+			},
+		},
+		{
+			desc: "panics of non-comparable keys are reported to the handler",
+			test: func(t *testing.T) {
+				var reported interface{}
+				handler := func(executionKey interface{}, value interface{}, stack []byte) {
+					reported = value
+				}
+
+				ctx, destroyFn := WithCache(context.Background(), WithPanicHandler(handler))
+				defer destroyFn()
+
+				outcome, _ := extractCache(ctx).execute(ctx, []int{1}, func(context.Context) (interface{}, error) {
+					panic("boom")
+				})
+
+				assert.True(t, errors.Is(outcome.Err, ErrPanicExecutingMemoizedFn))
+				assert.Equal(t, "boom", reported)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
+
+func TestWithPanicPropagation(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "first caller re-panics",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background(), WithPanicPropagation())
+				defer destroyFn()
+
+				assert.PanicsWithValue(t, "boom", func() {
+					Execute(ctx, "key", func(context.Context) (int, error) {
+						panic("boom")
+					})
+				})
+
+				outcome, _ := Execute(ctx, "key", func(context.Context) (int, error) {
+					return 1, nil
+				})
+
+				assert.True(t, errors.Is(outcome.Err, ErrPanicExecutingMemoizedFn), "subsequent callers receive the error")
+			},
+		},
+		{
+			desc: "non-comparable key re-panics",
+			test: func(t *testing.T) {
+				ctx, destroyFn := WithCache(context.Background(), WithPanicPropagation())
+				defer destroyFn()
+
+				assert.PanicsWithValue(t, "boom", func() {
+					extractCache(ctx).execute(ctx, []int{1}, func(context.Context) (interface{}, error) {
+						panic("boom")
+					})
+				})
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
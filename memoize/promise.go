@@ -1,12 +1,15 @@
 package memoize
 
 import (
+	"container/list"
 	"context"
 	"fmt"
 	"runtime/trace"
 	"sync/atomic"
+	"time"
 
 	"github.com/jamestrandung/go-context/cext"
+	"github.com/jamestrandung/go-context/ctxpool"
 )
 
 // Function is the type of function that can be memoized.
@@ -31,6 +34,40 @@ type Extra struct {
 	// IsExecuted indicates if the outcome came from actual execution or
 	// was pre-populated in the cache.
 	IsExecuted bool
+	// StartedAt is the time at which the memoized function started running,
+	// zero if it never ran, e.g. if the outcome was pre-populated. A StartedAt
+	// earlier than the call means that the outcome came from the execution of
+	// another caller.
+	StartedAt time.Time
+	// CompletedAt is the time at which the outcome became available, zero if
+	// the caller stopped waiting before.
+	CompletedAt time.Time
+	// Duration is how long the memoized function took, i.e. the time between
+	// StartedAt and CompletedAt, zero if either of them is zero.
+	Duration time.Duration
+}
+
+// newExtra returns the Extra of an outcome whose memoized function ran between
+// the given UnixNano times, zero if it did not.
+func newExtra(isMemoized bool, isExecuted bool, startedAt int64, completedAt int64) Extra {
+	extra := Extra{
+		IsMemoized: isMemoized,
+		IsExecuted: isExecuted,
+	}
+
+	if startedAt != 0 {
+		extra.StartedAt = time.Unix(0, startedAt)
+	}
+
+	if completedAt != 0 {
+		extra.CompletedAt = time.Unix(0, completedAt)
+	}
+
+	if startedAt != 0 && completedAt != 0 {
+		extra.Duration = time.Duration(completedAt - startedAt)
+	}
+
+	return extra
 }
 
 // State represents the state enumeration for a promise.
@@ -41,10 +78,12 @@ const (
 	IsCreated   State = iota // IsCreated represents a newly created promise
 	IsExecuted               // IsExecuted represents a promise which was executed
 	IsPopulated              // IsPopulated represents a completed promise carrying populated outcome
+	IsResolved               // IsResolved represents a promise whose execution has completed
 )
 
 // A promise represents the future result of a call to a function.
 type promise struct {
+	executionKey     interface{}
 	executionKeyType string
 
 	// the rootCtx that was used to initialize a cache and would provide
@@ -58,6 +97,44 @@ type promise struct {
 	function Function
 	// outcome is set when execution completes.
 	outcome Outcome
+	// pool runs the function if not nil, otherwise it runs on a new goroutine.
+	pool *ctxpool.Pool
+	// slots bounds the number of functions running at the same time if not nil.
+	slots chan struct{}
+	// ttl is how long the outcome stays valid after completion, forever if zero.
+	ttl time.Duration
+	// timeout is how long the function may run before the promise completes
+	// with ErrExecutionTimedOut, forever if zero.
+	timeout time.Duration
+	// isRetryable reports errors that should not be memoized, if not nil. The
+	// promise expires as soon as it completes with such an error.
+	isRetryable func(error) bool
+	// panics determines what happens when the function panics.
+	panics panicPolicy
+	// recovered is the value recovered from the function if it panicked. It
+	// is set before the promise completes.
+	recovered *recoveredPanic
+	// namespace is the namespace this promise was registered in, empty if none.
+	namespace string
+	// startedAt is the UnixNano time at which the function started running,
+	// zero if it never did.
+	startedAt int64
+	// completedAt is the UnixNano time at which this promise completed, zero
+	// while it is pending.
+	completedAt int64
+	// isRefreshing is set to 1 while a replacement of this promise is being
+	// executed in the background.
+	isRefreshing int32
+	// isCompleted is set to 1 by the first call to complete.
+	isCompleted int32
+	// expiresAt is the UnixNano time after which the outcome is no longer
+	// valid, zero if it never expires. It is set when execution completes.
+	expiresAt int64
+	// element is the entry of this promise in the recency list of its cache,
+	// nil if the cache does not evict promises.
+	element *list.Element
+	// stats records the execution of the function if not nil.
+	stats *statsRecorder
 }
 
 // newPromise returns a promise for the future result of calling the
@@ -89,13 +166,58 @@ func completedPromise(debug string, outcome Outcome) *promise {
 		state:            int32(IsPopulated),
 		done:             done,
 		outcome:          outcome,
+		completedAt:      timeNow().UnixNano(),
 	}
 }
 
 // isExecuted returns whether this promise was actually
 // executed or the result was pre-populated.
 func (p *promise) isExecuted() bool {
-	return p.state == int32(IsExecuted)
+	return atomic.LoadInt32(&p.state) == int32(IsExecuted)
+}
+
+// currentState returns the State of this promise as seen by callers, which is
+// IsResolved once an executed promise has completed.
+func (p *promise) currentState() State {
+	state := State(atomic.LoadInt32(&p.state))
+	if state == IsExecuted && p.isDone() {
+		return IsResolved
+	}
+
+	return state
+}
+
+// extra returns the Extra of the outcome of this promise as seen by callers.
+func (p *promise) extra() Extra {
+	return newExtra(true, p.isExecuted(), atomic.LoadInt64(&p.startedAt), atomic.LoadInt64(&p.completedAt))
+}
+
+// isPopulated returns whether the outcome of this promise was pre-populated.
+func (p *promise) isPopulated() bool {
+	return atomic.LoadInt32(&p.state) == int32(IsPopulated)
+}
+
+// isExpired returns whether this promise has completed with an outcome that
+// is no longer valid.
+func (p *promise) isExpired() bool {
+	expiresAt := atomic.LoadInt64(&p.expiresAt)
+	return expiresAt != 0 && timeNow().UnixNano() >= expiresAt
+}
+
+// isOlderThan returns whether this promise completed more than maxAge ago.
+func (p *promise) isOlderThan(maxAge time.Duration) bool {
+	completedAt := atomic.LoadInt64(&p.completedAt)
+	return completedAt != 0 && timeNow().UnixNano()-completedAt > int64(maxAge)
+}
+
+// isDone returns whether this promise has completed.
+func (p *promise) isDone() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
 }
 
 // get returns the value associated with a promise.
@@ -120,6 +242,43 @@ func (p *promise) get(ctx context.Context) Outcome {
 	return p.wait(ctx)
 }
 
+// tryGet works like get but returns ErrExecutionPending instead of waiting if
+// another caller is running the function.
+func (p *promise) tryGet(ctx context.Context) Outcome {
+	if ctx.Err() != nil {
+		return Outcome{
+			Value: nil,
+			Err:   ctx.Err(),
+		}
+	}
+
+	if p.changeState(IsCreated, IsExecuted) {
+		return p.run(ctx)
+	}
+
+	if !p.isDone() {
+		return Outcome{
+			Value: nil,
+			Err:   ErrExecutionPending,
+		}
+	}
+
+	return p.wait(ctx)
+}
+
+// getInBackground works like get but never propagates the panic of the function
+// since there is no caller to propagate it to.
+func (p *promise) getInBackground(ctx context.Context) (outcome Outcome) {
+	defer func() {
+		if r := recover(); r != nil {
+			// propagatePanic panics only once this promise is done
+			outcome = p.outcome
+		}
+	}()
+
+	return p.get(ctx)
+}
+
 // run starts p.function and returns the result.
 func (p *promise) run(ctx context.Context) Outcome {
 	// To prevent one child goroutines from cancelling the execution of the memoized
@@ -132,22 +291,165 @@ func (p *promise) run(ctx context.Context) Outcome {
 	// the root context get cancelled, all child contexts must be cancelled as well.
 	delegatingCtx := cext.Delegate(p.rootCtx, ctx)
 
-	go func() {
+	// A memoized function already running on the pool must not wait for another
+	// goroutine of it, otherwise nested executions deadlock once the pool is busy.
+	isNested := p.pool != nil && isRunningOn(ctx, p.pool)
+	if p.pool != nil {
+		delegatingCtx = withPool(delegatingCtx, p.pool)
+	}
+
+	// Likewise, executions nested in a memoized function holding an execution slot
+	// bypass the limit since waiting for another slot could never end.
+	holdsSlot := p.slots != nil && holdsExecutionSlot(ctx, p.slots)
+	if p.slots != nil {
+		delegatingCtx = withExecutionSlot(delegatingCtx, p.slots)
+	}
+
+	// complete may clear p.function concurrently once the timeout elapses
+	function := p.function
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		delegatingCtx, cancel = context.WithTimeout(delegatingCtx, p.timeout)
+
+		// Unblock waiters once the timeout elapses even if the function ignores
+		// the cancellation of its context.
+		go func() {
+			defer cancel()
+
+			select {
+			case <-p.done:
+			case <-delegatingCtx.Done():
+				if delegatingCtx.Err() == context.DeadlineExceeded {
+					p.complete(
+						Outcome{
+							Value: nil,
+							Err:   ErrExecutionTimedOut,
+						},
+					)
+				}
+			}
+		}()
+	}
+
+	execute := func(context.Context) {
+		if p.slots != nil && !holdsSlot {
+			select {
+			case p.slots <- struct{}{}:
+				defer func() { <-p.slots }()
+			case <-delegatingCtx.Done():
+				p.complete(
+					Outcome{
+						Value: nil,
+						Err:   delegatingCtx.Err(),
+					},
+				)
+
+				return
+			}
+		}
+
 		trace.WithRegion(
 			delegatingCtx, fmt.Sprintf("promise.run %s", p.executionKeyType), func() {
-				v, err := doExecute(delegatingCtx, p.function)
+				var recovered *recoveredPanic
+
+				atomic.StoreInt64(&p.startedAt, timeNow().UnixNano())
 
-				p.outcome = Outcome{
-					Value: v,
-					Err:   err,
+				startTime := time.Now()
+				v, err := doExecute(delegatingCtx, function, p.panics.onPanic(p.executionKey, &recovered))
+				if p.stats != nil {
+					p.stats.recordExecution(p.executionKeyType, time.Since(startTime), err)
 				}
-				p.function = nil // aid GC
-				close(p.done)
+
+				p.completeWithPanic(
+					Outcome{
+						Value: v,
+						Err:   err,
+					},
+					recovered,
+				)
 			},
 		)
-	}()
+	}
 
-	return p.wait(ctx)
+	if p.pool == nil {
+		go execute(delegatingCtx)
+		return p.propagatePanic(p.wait(ctx))
+	}
+
+	if isNested {
+		execute(delegatingCtx)
+		return p.propagatePanic(p.wait(ctx))
+	}
+
+	if !p.pool.TrySubmit(delegatingCtx, execute) {
+		// All goroutines of the pool are busy, wait for one without blocking the caller
+		// so that it can still give up by cancelling ctx.
+		go func() {
+			if err := p.pool.Submit(p.rootCtx, execute); err != nil {
+				p.complete(
+					Outcome{
+						Value: nil,
+						Err:   err,
+					},
+				)
+			}
+		}()
+	}
+
+	return p.propagatePanic(p.wait(ctx))
+}
+
+// propagatePanic re-panics with the value recovered from the function if it
+// panicked and panics should be propagated, otherwise it returns outcome.
+func (p *promise) propagatePanic(outcome Outcome) Outcome {
+	if p.panics.propagate && p.isDone() && p.recovered != nil {
+		panic(p.recovered.value)
+	}
+
+	return outcome
+}
+
+// complete sets the outcome of this promise and unblocks its waiters. Only the
+// first call has an effect, subsequent outcomes are discarded.
+func (p *promise) complete(outcome Outcome) {
+	p.completeWithPanic(outcome, nil)
+}
+
+// completeWithPanic works like complete but also records the value recovered
+// from the function if it panicked.
+func (p *promise) completeWithPanic(outcome Outcome, recovered *recoveredPanic) {
+	if !atomic.CompareAndSwapInt32(&p.isCompleted, 0, 1) {
+		return
+	}
+
+	p.outcome = outcome
+	p.recovered = recovered
+	atomic.StoreInt64(&p.completedAt, timeNow().UnixNano())
+	p.function = nil // aid GC
+
+	switch {
+	case outcome.Err != nil && p.isRetryable != nil && p.isRetryable(outcome.Err):
+		atomic.StoreInt64(&p.expiresAt, timeNow().UnixNano())
+	case p.ttl > 0:
+		atomic.StoreInt64(&p.expiresAt, timeNow().Add(p.ttl).UnixNano())
+	}
+
+	close(p.done)
+}
+
+// nowFn holds the func() time.Time used by timeNow. It can be replaced in tests
+// to control the expiry and the age of promises, even while other tests still
+// have promises completing in the background.
+var nowFn atomic.Value
+
+// timeNow returns the current time.
+func timeNow() time.Time {
+	if fn, ok := nowFn.Load().(func() time.Time); ok {
+		return fn()
+	}
+
+	return time.Now()
 }
 
 // wait waits for the value to be computed, or ctx to be cancelled.
@@ -167,3 +469,33 @@ func (p *promise) wait(ctx context.Context) Outcome {
 func (p *promise) changeState(from, to State) bool {
 	return atomic.CompareAndSwapInt32(&p.state, int32(from), int32(to))
 }
+
+type poolContextKey struct{}
+
+// withPool returns a new context.Context derived from ctx that marks memoized
+// functions using it as running on the given Pool.
+func withPool(ctx context.Context, pool *ctxpool.Pool) context.Context {
+	return context.WithValue(ctx, poolContextKey{}, pool)
+}
+
+// isRunningOn returns whether ctx belongs to a memoized function running on the
+// given Pool.
+func isRunningOn(ctx context.Context, pool *ctxpool.Pool) bool {
+	running, _ := ctx.Value(poolContextKey{}).(*ctxpool.Pool)
+	return running == pool
+}
+
+type executionSlotContextKey struct{}
+
+// withExecutionSlot returns a new context.Context derived from ctx that marks
+// memoized functions using it as holding a slot of the given semaphore.
+func withExecutionSlot(ctx context.Context, slots chan struct{}) context.Context {
+	return context.WithValue(ctx, executionSlotContextKey{}, slots)
+}
+
+// holdsExecutionSlot returns whether ctx belongs to a memoized function holding a
+// slot of the given semaphore.
+func holdsExecutionSlot(ctx context.Context, slots chan struct{}) bool {
+	held, _ := ctx.Value(executionSlotContextKey{}).(chan struct{})
+	return held == slots
+}
@@ -0,0 +1,111 @@
+package redisbackend
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/jamestrandung/go-context/memoize"
+)
+
+// Client is the subset of a Redis client used by Backend. It can be implemented by a
+// thin adapter around any Redis client, e.g. github.com/redis/go-redis.
+type Client interface {
+	// Get returns the value stored under the given key and whether it was found.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores the given value under the given key for the given duration, or
+	// forever if it is zero.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// Option configures a Backend.
+type Option func(*Backend)
+
+// WithPrefix prepends the given prefix to all Redis keys, e.g. to share a Redis
+// instance between services.
+func WithPrefix(prefix string) Option {
+	return func(b *Backend) {
+		b.prefix = prefix
+	}
+}
+
+// WithTTL makes outcomes expire from Redis after the given duration instead of being
+// stored forever.
+func WithTTL(ttl time.Duration) Option {
+	return func(b *Backend) {
+		b.ttl = ttl
+	}
+}
+
+// WithKeyFunc replaces the function converting execution keys into Redis keys, which
+// defaults to formatting them with %#v.
+func WithKeyFunc(keyFn func(executionKey interface{}) string) Option {
+	return func(b *Backend) {
+		b.keyFn = keyFn
+	}
+}
+
+// Backend is a memoize.Backend storing outcomes in Redis. Values are encoded using
+// encoding/gob, so their concrete types must be registered via gob.Register. Errors
+// of Redis and of the encoding are treated as cache misses.
+type Backend struct {
+	client Client
+	prefix string
+	ttl    time.Duration
+	keyFn  func(executionKey interface{}) string
+}
+
+// New returns a Backend storing outcomes using the given Client.
+func New(client Client, opts ...Option) *Backend {
+	b := &Backend{
+		client: client,
+		keyFn: func(executionKey interface{}) string {
+			return fmt.Sprintf("%#v", executionKey)
+		},
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// entry is the gob-encoded form of an outcome, wrapping its value so that its
+// concrete type gets encoded too.
+type entry struct {
+	Value interface{}
+}
+
+// Get returns the Outcome stored in Redis under the given executionKey.
+func (b *Backend) Get(ctx context.Context, executionKey interface{}) (memoize.Outcome, bool) {
+	data, ok, err := b.client.Get(ctx, b.key(executionKey))
+	if err != nil || !ok {
+		return memoize.Outcome{}, false
+	}
+
+	var e entry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&e); err != nil {
+		return memoize.Outcome{}, false
+	}
+
+	return memoize.Outcome{
+		Value: e.Value,
+	}, true
+}
+
+// Set stores the given Outcome in Redis under the given executionKey.
+func (b *Backend) Set(ctx context.Context, executionKey interface{}, outcome memoize.Outcome) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry{Value: outcome.Value}); err != nil {
+		return
+	}
+
+	_ = b.client.Set(ctx, b.key(executionKey), buf.Bytes(), b.ttl)
+}
+
+func (b *Backend) key(executionKey interface{}) string {
+	return b.prefix + b.keyFn(executionKey)
+}
@@ -0,0 +1,127 @@
+package redisbackend
+
+import (
+	"context"
+	"encoding/gob"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jamestrandung/go-context/memoize"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClient struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+	err  error
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		data: make(map[string][]byte),
+		ttls: make(map[string]time.Duration),
+	}
+}
+
+func (c *fakeClient) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return nil, false, c.err
+	}
+
+	value, ok := c.data[key]
+	return value, ok, nil
+}
+
+func (c *fakeClient) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data[key] = value
+	c.ttls[key] = ttl
+
+	return nil
+}
+
+type userKey struct {
+	ID int
+}
+
+type user struct {
+	Name string
+}
+
+func init() {
+	gob.Register(user{})
+}
+
+func TestBackend(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "outcomes are shared across caches",
+			test: func(t *testing.T) {
+				client := newFakeClient()
+				backend := New(client, WithPrefix("svc:"), WithTTL(time.Minute))
+
+				calls := 0
+				load := func(context.Context) (user, error) {
+					calls++
+					return user{Name: "alice"}, nil
+				}
+
+				for i := 0; i < 2; i++ {
+					ctx, destroyFn := memoize.WithCache(context.Background(), memoize.WithBackend(backend))
+
+					outcome, _ := memoize.Execute(ctx, userKey{ID: 1}, load)
+					assert.Equal(t, memoize.TypedOutcome[user]{Value: user{Name: "alice"}}, outcome)
+
+					destroyFn()
+				}
+
+				assert.Equal(t, 1, calls)
+				assert.Equal(t, time.Minute, client.ttls["svc:redisbackend.userKey{ID:1}"])
+			},
+		},
+		{
+			desc: "redis errors are treated as misses",
+			test: func(t *testing.T) {
+				client := newFakeClient()
+				client.err = assert.AnError
+
+				backend := New(client, WithKeyFunc(func(executionKey interface{}) string {
+					return "user"
+				}))
+
+				_, ok := backend.Get(context.Background(), userKey{ID: 1})
+				assert.False(t, ok)
+			},
+		},
+		{
+			desc: "undecodable values are treated as misses",
+			test: func(t *testing.T) {
+				client := newFakeClient()
+				client.data["user"] = []byte("garbage")
+
+				backend := New(client, WithKeyFunc(func(executionKey interface{}) string {
+					return "user"
+				}))
+
+				_, ok := backend.Get(context.Background(), userKey{ID: 1})
+				assert.False(t, ok)
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+
+		t.Run(sc.desc, sc.test)
+	}
+}
@@ -0,0 +1,11 @@
+package scope
+
+import (
+	"errors"
+)
+
+var (
+	ErrNoScope         = errors.New("context was not initialized using WithScope")
+	ErrScopeClosed     = errors.New("scope already closed, cannot be used anymore")
+	ErrAlreadyProvided = errors.New("dependency already provided in this scope")
+)
@@ -0,0 +1,167 @@
+// Package scope provides a typed store of request-scoped dependencies on the context,
+// e.g. a database transaction or a per-request client, along with hooks releasing them
+// when the request ends. It is a lightweight dependency injection layer that resolves
+// dependencies by their type.
+package scope
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+type contextKey struct{}
+
+var scopeKey = contextKey{}
+
+// CloseFn closes a scope.
+type CloseFn func()
+
+// scope holds the dependencies provided within one request.
+type scope struct {
+	parent *scope
+
+	mu           sync.RWMutex
+	isClosed     bool
+	dependencies map[reflect.Type]interface{}
+	closeHooks   []func()
+}
+
+// WithScope returns a new context.Context that holds a reference to a new scope of
+// dependencies. Dependencies provided in a parent scope, if any, can still be resolved
+// unless they were provided again in the new scope.
+//
+// Note: the returned CloseFn must be deferred to run the hooks registered via OnClose.
+func WithScope(ctx context.Context) (context.Context, CloseFn) {
+	parent, _ := ctx.Value(scopeKey).(*scope)
+
+	s := &scope{
+		parent:       parent,
+		dependencies: make(map[reflect.Type]interface{}),
+	}
+
+	return context.WithValue(ctx, scopeKey, s), s.close
+}
+
+// Provide makes the given dependency resolvable by type T in the nearest scope of ctx.
+// It returns ErrNoScope if ctx was not initialized using WithScope, ErrScopeClosed if
+// this scope was closed, or ErrAlreadyProvided if a dependency of type T was already
+// provided in this scope.
+func Provide[T any](ctx context.Context, dependency T) error {
+	s, ok := ctx.Value(scopeKey).(*scope)
+	if !ok {
+		return ErrNoScope
+	}
+
+	return s.provide(typeOf[T](), dependency)
+}
+
+// Resolve returns the dependency of type T provided in the nearest scope of ctx that
+// has one, and whether it was found. A nil dependency provided as an interface type
+// is resolved as the zero value of T.
+func Resolve[T any](ctx context.Context) (T, bool) {
+	s, _ := ctx.Value(scopeKey).(*scope)
+
+	dependency, ok := s.resolve(typeOf[T]())
+	if !ok {
+		var zero T
+		return zero, false
+	}
+
+	// A nil interface does not assert to T even though it was provided as one
+	resolved, _ := dependency.(T)
+
+	return resolved, true
+}
+
+// MustResolve is similar to Resolve but panics if no dependency of type T was provided.
+func MustResolve[T any](ctx context.Context) T {
+	dependency, ok := Resolve[T](ctx)
+	if !ok {
+		panic("scope: no dependency of type " + typeOf[T]().String())
+	}
+
+	return dependency
+}
+
+// OnClose registers fn to be called when the nearest scope of ctx gets closed. Hooks
+// are called in the reverse order of their registration, similar to defer, so that
+// dependencies are released before those they depend on. It returns ErrNoScope if
+// ctx was not initialized using WithScope, or ErrScopeClosed if this scope was closed.
+//
+// Note: hooks only run when the CloseFn of their scope gets called. This package does
+// not integrate with any other cleanup mechanism.
+func OnClose(ctx context.Context, fn func()) error {
+	s, ok := ctx.Value(scopeKey).(*scope)
+	if !ok {
+		return ErrNoScope
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isClosed {
+		return ErrScopeClosed
+	}
+
+	s.closeHooks = append(s.closeHooks, fn)
+
+	return nil
+}
+
+func (s *scope) provide(t reflect.Type, dependency interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.isClosed {
+		return ErrScopeClosed
+	}
+
+	if _, ok := s.dependencies[t]; ok {
+		return errors.Wrap(ErrAlreadyProvided, t.String())
+	}
+
+	s.dependencies[t] = dependency
+
+	return nil
+}
+
+func (s *scope) resolve(t reflect.Type) (interface{}, bool) {
+	for current := s; current != nil; current = current.parent {
+		current.mu.RLock()
+		dependency, ok := current.dependencies[t]
+		current.mu.RUnlock()
+
+		if ok {
+			return dependency, true
+		}
+	}
+
+	return nil, false
+}
+
+func (s *scope) close() {
+	s.mu.Lock()
+	if s.isClosed {
+		s.mu.Unlock()
+		return
+	}
+
+	s.isClosed = true
+	s.dependencies = nil
+
+	hooks := s.closeHooks
+	s.closeHooks = nil
+	s.mu.Unlock()
+
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i]()
+	}
+}
+
+// typeOf returns the reflect.Type of T, including when T is an interface.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,138 @@
+package scope
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type tx struct {
+	id int
+}
+
+type greeter interface {
+	Greet() string
+}
+
+type englishGreeter struct{}
+
+func (englishGreeter) Greet() string {
+	return "hello"
+}
+
+func TestProvideAndResolve(t *testing.T) {
+	scenarios := []struct {
+		desc string
+		test func(t *testing.T)
+	}{
+		{
+			desc: "no scope",
+			test: func(t *testing.T) {
+				assert.Equal(t, ErrNoScope, Provide(context.Background(), &tx{}))
+
+				_, ok := Resolve[*tx](context.Background())
+				assert.False(t, ok)
+				assert.Panics(t, func() {
+					MustResolve[*tx](context.Background())
+				})
+			},
+		},
+		{
+			desc: "dependencies are resolved by type",
+			test: func(t *testing.T) {
+				ctx, closeFn := WithScope(context.Background())
+				defer closeFn()
+
+				assert.Nil(t, Provide(ctx, &tx{id: 1}))
+				assert.Nil(t, Provide[greeter](ctx, englishGreeter{}))
+
+				resolved, ok := Resolve[*tx](ctx)
+				assert.True(t, ok)
+				assert.Equal(t, &tx{id: 1}, resolved)
+
+				assert.Equal(t, "hello", MustResolve[greeter](ctx).Greet())
+
+				_, ok = Resolve[englishGreeter](ctx)
+				assert.False(t, ok, "dependencies are resolved by the type they were provided as")
+
+				err := Provide(ctx, &tx{id: 2})
+				assert.True(t, errors.Is(err, ErrAlreadyProvided))
+			},
+		},
+		{
+			desc: "nil interface dependency",
+			test: func(t *testing.T) {
+				ctx, closeFn := WithScope(context.Background())
+				defer closeFn()
+
+				assert.Nil(t, Provide[greeter](ctx, nil))
+
+				resolved, ok := Resolve[greeter](ctx)
+				assert.True(t, ok)
+				assert.Nil(t, resolved)
+			},
+		},
+		{
+			desc: "nested scopes",
+			test: func(t *testing.T) {
+				parent, closeParentFn := WithScope(context.Background())
+				defer closeParentFn()
+
+				assert.Nil(t, Provide(parent, &tx{id: 1}))
+				assert.Nil(t, Provide(parent, "parent"))
+
+				child, closeChildFn := WithScope(parent)
+				defer closeChildFn()
+
+				assert.Nil(t, Provide(child, &tx{id: 2}))
+
+				assert.Equal(t, &tx{id: 2}, MustResolve[*tx](child))
+				assert.Equal(t, "parent", MustResolve[string](child))
+				assert.Equal(t, &tx{id: 1}, MustResolve[*tx](parent))
+			},
+		},
+		{
+			desc: "closed scope",
+			test: func(t *testing.T) {
+				ctx, closeFn := WithScope(context.Background())
+				assert.Nil(t, Provide(ctx, &tx{id: 1}))
+
+				closeFn()
+
+				_, ok := Resolve[*tx](ctx)
+				assert.False(t, ok)
+				assert.Equal(t, ErrScopeClosed, Provide(ctx, &tx{id: 2}))
+				assert.Equal(t, ErrScopeClosed, OnClose(ctx, func() {}))
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		sc := scenario
+		t.Run(sc.desc, func(t *testing.T) {
+			sc.test(t)
+		})
+	}
+}
+
+func TestOnClose(t *testing.T) {
+	assert.Equal(t, ErrNoScope, OnClose(context.Background(), func() {}))
+
+	ctx, closeFn := WithScope(context.Background())
+
+	var calls []string
+	for i := 0; i < 3; i++ {
+		i := i
+		assert.Nil(t, OnClose(ctx, func() {
+			calls = append(calls, fmt.Sprint(i))
+		}))
+	}
+
+	closeFn()
+	closeFn()
+
+	assert.Equal(t, []string{"2", "1", "0"}, calls)
+}